@@ -0,0 +1,177 @@
+package cachemar
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// AsyncErrorHandler receives the errors of writes that ran in the background.
+type AsyncErrorHandler func(cacher string, op string, err error)
+
+type asyncJob struct {
+	ctx    context.Context
+	cacher string
+	op     string
+	key    string // Jobs with the same key run in order. Jobs without a key run after all jobs queued before them.
+	run    func(ctx context.Context) error
+
+	barrier *asyncBarrier // Set on the copies of a job without a key queued to every worker.
+}
+
+// asyncBarrier runs a job once every worker has reached its copy, and holds the workers until it ran.
+type asyncBarrier struct {
+	remaining atomic.Int32
+	done      chan struct{}
+}
+
+// arrive reports whether the calling worker arrived last and must run the job. The others wait until it ran.
+func (b *asyncBarrier) arrive() bool {
+	if b.remaining.Add(-1) == 0 {
+		return true
+	}
+
+	<-b.done
+	return false
+}
+
+// asyncPool runs the writes to the cache managers of a chain that are marked async. Each worker has its own
+// queue and the jobs are assigned by key, so the writes of a key are never reordered. Its workers are started
+// on the first write, so SetAsyncWorkers has no effect afterwards.
+type asyncPool struct {
+	mu           sync.RWMutex
+	barrierMu    sync.Mutex // Queues the jobs without a key to all workers in the same order.
+	once         sync.Once
+	workers      int
+	queues       []chan asyncJob
+	wg           sync.WaitGroup
+	closed       bool
+	errorHandler AsyncErrorHandler
+}
+
+func (p *asyncPool) start() {
+	p.once.Do(func() {
+		workers := p.workers
+		if workers <= 0 {
+			workers = DefaultAsyncWorkers
+		}
+
+		p.queues = make([]chan asyncJob, workers)
+		for i := range p.queues {
+			p.queues[i] = make(chan asyncJob, 1)
+			p.wg.Add(1)
+			go p.work(p.queues[i])
+		}
+	})
+}
+
+func (p *asyncPool) work(jobs <-chan asyncJob) {
+	defer p.wg.Done()
+
+	for job := range jobs {
+		if job.barrier != nil && !job.barrier.arrive() {
+			continue
+		}
+
+		if err := job.run(job.ctx); err != nil {
+			p.errorHandler(job.cacher, job.op, err)
+		}
+
+		if job.barrier != nil {
+			close(job.barrier.done)
+		}
+	}
+}
+
+// dispatch queues the job, blocking while its worker is busy. After close, the job runs synchronously.
+func (p *asyncPool) dispatch(job asyncJob) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return job.run(job.ctx)
+	}
+
+	p.start()
+
+	if job.key != "" {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(job.key))
+		p.queues[h.Sum32()%uint32(len(p.queues))] <- job
+		return nil
+	}
+
+	p.barrierMu.Lock()
+	defer p.barrierMu.Unlock()
+
+	job.barrier = &asyncBarrier{done: make(chan struct{})}
+	job.barrier.remaining.Store(int32(len(p.queues)))
+	for _, queue := range p.queues {
+		queue <- job
+	}
+	return nil
+}
+
+// close waits for the queued jobs to finish.
+func (p *asyncPool) close() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	p.closed = true
+	p.start()
+	for _, queue := range p.queues {
+		close(queue)
+	}
+	p.mu.Unlock()
+
+	p.wg.Wait()
+}
+
+// detachedContext keeps the values of its parent but is never cancelled, so that a background write
+// outlives the call that queued it.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+// SetAsync marks the named cache manager of the chain as async, so that Set, Remove and RemoveByTag
+// return without waiting for it. Its errors are passed to the handler set with SetAsyncErrorHandler.
+// Values must not be modified after Set returns, as they may be encoded later.
+func (c *chained) SetAsync(name string, async bool) {
+	c.async[name] = async
+}
+
+// SetAsyncWorkers sets the number of workers running the writes to async cache managers.
+func (c *chained) SetAsyncWorkers(n int) {
+	c.pool.workers = n
+}
+
+// SetAsyncErrorHandler sets the handler of errors of async writes. By default they are logged.
+func (c *chained) SetAsyncErrorHandler(handler AsyncErrorHandler) {
+	c.pool.errorHandler = handler
+}
+
+// runLayer runs the write of the key to the named cache manager, in the background if it is async. Writes
+// without a key, such as RemoveByTag, pass an empty key.
+func (c *chained) runLayer(ctx context.Context, name, op, key string, run func(ctx context.Context) error) error {
+	if !c.async[name] {
+		return run(ctx)
+	}
+
+	return c.pool.dispatch(asyncJob{ctx: detachedContext{ctx}, cacher: name, op: op, key: key, run: run})
+}
@@ -0,0 +1,46 @@
+package cachemar
+
+import (
+	"context"
+	"fmt"
+)
+
+// BackendInfo describes the storage backend of a cache manager.
+type BackendInfo struct {
+	Type          string
+	Addr          string
+	Database      int
+	Version       string
+	UptimeSeconds int64
+}
+
+// BackendInformer is implemented by cache managers that can describe their storage backend.
+type BackendInformer interface {
+	BackendInfo(ctx context.Context) (BackendInfo, error)
+}
+
+// AllBackendInfo returns the backend info of every registered cache manager that implements BackendInformer.
+func (c *manager) AllBackendInfo(ctx context.Context) (map[string]BackendInfo, error) {
+	infos := make(map[string]BackendInfo, len(c.managers))
+	errors := make([]error, 0)
+
+	for name, manager := range c.managers {
+		informer, ok := As[BackendInformer](manager)
+		if !ok {
+			continue
+		}
+
+		info, err := informer.BackendInfo(ctx)
+		if err != nil {
+			errors = append(errors, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		infos[name] = info
+	}
+
+	if len(errors) > 0 {
+		return infos, fmt.Errorf("errors: %v", errors)
+	}
+
+	return infos, nil
+}
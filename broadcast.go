@@ -0,0 +1,72 @@
+package cachemar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// BroadcastSet stores the key-value pair in every registered cache manager, not only the current one.
+// It is meant for values that must change everywhere at once, such as configuration. The error of
+// each failed cache manager is prefixed with its name and joined into the returned error.
+func (c *manager) BroadcastSet(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
+	if c.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	key, err := c.prepareKey(key)
+	if err != nil {
+		return err
+	}
+	tags = c.prepareTags(tags)
+
+	return c.broadcast(func(manager Cacher) error {
+		return manager.Set(ctx, key, value, ttl, tags)
+	})
+}
+
+// BroadcastRemove removes the key from every registered cache manager. Errors are returned like by BroadcastSet.
+func (c *manager) BroadcastRemove(ctx context.Context, key string) error {
+	if c.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	key, err := c.prepareKey(key)
+	if err != nil {
+		return err
+	}
+
+	return c.broadcast(func(manager Cacher) error {
+		return manager.Remove(ctx, key)
+	})
+}
+
+// broadcast runs op on every registered cache manager in the order of their names, and joins the errors.
+func (c *manager) broadcast(op func(manager Cacher) error) error {
+	names := make([]string, 0, len(c.managers))
+	for name := range c.managers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		if err := op(c.managers[name]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// BroadcastSet stores the key-value pair in every registered cache manager, including those outside the chain.
+func (c *chained) BroadcastSet(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
+	return c.m.BroadcastSet(ctx, key, value, ttl, tags)
+}
+
+// BroadcastRemove removes the key from every registered cache manager, including those outside the chain.
+func (c *chained) BroadcastRemove(ctx context.Context, key string) error {
+	return c.m.BroadcastRemove(ctx, key)
+}
@@ -0,0 +1,57 @@
+package cachemar
+
+import (
+	"context"
+	"time"
+)
+
+// CacheItem describes a single value to be stored in the cache.
+type CacheItem struct {
+	Key   string
+	Value interface{}
+	TTL   time.Duration
+	Tags  []string
+}
+
+// CacheEntry is an alias of CacheItem.
+type CacheEntry = CacheItem
+
+// BatchSetter is implemented by cache managers that can store multiple items at once. Every Cacher implements it.
+type BatchSetter interface {
+	// MSet stores all items. A returned MultiError holds the error of each item by index.
+	MSet(ctx context.Context, items []CacheItem) error
+}
+
+// CacheItemBuilder builds a CacheItem fluently.
+type CacheItemBuilder struct {
+	item CacheItem
+}
+
+// NewCacheItem starts building a CacheItem for the given key with the default cache time.
+func NewCacheItem(key string) *CacheItemBuilder {
+	return &CacheItemBuilder{
+		item: CacheItem{
+			Key: key,
+			TTL: DefaultCacheTime,
+		},
+	}
+}
+
+func (b *CacheItemBuilder) Value(value interface{}) *CacheItemBuilder {
+	b.item.Value = value
+	return b
+}
+
+func (b *CacheItemBuilder) TTL(ttl time.Duration) *CacheItemBuilder {
+	b.item.TTL = ttl
+	return b
+}
+
+func (b *CacheItemBuilder) Tags(tags ...string) *CacheItemBuilder {
+	b.item.Tags = tags
+	return b
+}
+
+func (b *CacheItemBuilder) Build() CacheItem {
+	return b.item
+}
@@ -2,20 +2,39 @@ package cachemar
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 )
 
 type chained struct {
-	m        *manager
-	chain    []string
-	fallback string
+	m           *manager
+	chain       []string
+	fallback    string
+	warmWorkers int
+	readThrough bool
+	promoteTTL  time.Duration
+	ttlScales   map[string]float64
+	minLayerTTL time.Duration
+	async       map[string]bool
+	pool        *asyncPool
 }
 
 func newChained(m *manager) ChainedManager {
 	return &chained{
-		m:     m,
-		chain: make([]string, 0),
+		m:           m,
+		chain:       make([]string, 0),
+		warmWorkers: DefaultWarmWorkers,
+		ttlScales:   make(map[string]float64),
+		minLayerTTL: DefaultMinLayerTTL,
+		async:       make(map[string]bool),
+		pool: &asyncPool{
+			workers: DefaultAsyncWorkers,
+			errorHandler: func(cacher string, op string, err error) {
+				m.logger.Error("cachemar: async write failed", "operation", op, "cacher", cacher, "error", err)
+			},
+		},
 	}
 }
 
@@ -23,6 +42,53 @@ func (c *chained) SetFallback(name string) {
 	c.fallback = name
 }
 
+// SetReadThrough enables or disables read-through. When enabled, a value Get finds in a later cache manager
+// of the chain, or in the fallback, is copied into all earlier ones. It is stored for promoteTTL, or for the
+// remaining TTL of the value if promoteTTL is zero.
+func (c *chained) SetReadThrough(enabled bool, promoteTTL time.Duration) {
+	c.readThrough = enabled
+	c.promoteTTL = promoteTTL
+}
+
+// SetLayerTTLScale makes the chain store values in the named cache manager for the TTL multiplied by factor,
+// e.g. 0.1 for a short-lived in-process layer. Factors above 1 are valid for backup layers that should outlive
+// the others. A factor of zero stores values for the minimum layer TTL.
+func (c *chained) SetLayerTTLScale(name string, factor float64) {
+	c.ttlScales[name] = factor
+}
+
+// SetMinLayerTTL sets the TTL of layers whose TTL scale is zero. It defaults to DefaultMinLayerTTL.
+func (c *chained) SetMinLayerTTL(ttl time.Duration) {
+	c.minLayerTTL = ttl
+}
+
+// layerTTL returns the TTL of a value in the named cache manager. TTLs that aren't positive are not scaled.
+func (c *chained) layerTTL(name string, ttl time.Duration) time.Duration {
+	factor, ok := c.ttlScales[name]
+	if !ok || ttl <= 0 {
+		return ttl
+	}
+	if factor == 0 {
+		return c.minLayerTTL
+	}
+
+	return time.Duration(float64(ttl) * factor)
+}
+
+// layerItems returns the items with their TTLs scaled for the named cache manager.
+func (c *chained) layerItems(name string, items []CacheItem) []CacheItem {
+	if _, ok := c.ttlScales[name]; !ok {
+		return items
+	}
+
+	scaled := make([]CacheItem, len(items))
+	for i, item := range items {
+		item.TTL = c.layerTTL(name, item.TTL)
+		scaled[i] = item
+	}
+	return scaled
+}
+
 func (c *chained) AddToChain(name string) {
 	c.chain = append(c.chain, name)
 }
@@ -42,6 +108,10 @@ func (c *chained) Register(name string, manager Cacher) {
 	c.m.Register(name, manager)
 }
 
+func (c *chained) WrapAll(wrapFn func(Cacher) Cacher) {
+	c.m.WrapAll(wrapFn)
+}
+
 func (c *chained) Use(name string) Cacher {
 	return c.m.Use(name)
 }
@@ -62,6 +132,14 @@ func (c *chained) Close() error {
 	return c.m.Close()
 }
 
+func (c *chained) SetReadOnly(readOnly bool) {
+	c.m.SetReadOnly(readOnly)
+}
+
+func (c *chained) IsReadOnly() bool {
+	return c.m.IsReadOnly()
+}
+
 func (c *chained) Chain() ChainedManager {
 	return c
 }
@@ -69,10 +147,22 @@ func (c *chained) Chain() ChainedManager {
 // Implementing the Cacher interface methods with chaining logic
 
 func (c *chained) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
+	if c.m.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	key, err := c.m.prepareKey(key)
+	if err != nil {
+		return err
+	}
+
 	var errors []error
 	for _, managerName := range c.chain {
 		manager := c.m.managers[managerName]
-		err := manager.Set(ctx, key, value, ttl, tags)
+		layerTTL := c.layerTTL(managerName, ttl)
+		err := c.runLayer(ctx, managerName, "Set", key, func(ctx context.Context) error {
+			return manager.Set(ctx, key, value, layerTTL, c.m.prepareTags(tags))
+		})
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -83,27 +173,392 @@ func (c *chained) Set(ctx context.Context, key string, value interface{}, ttl ti
 	return nil
 }
 
-func (c *chained) Get(ctx context.Context, key string, value interface{}) error {
+// SetOpts stores the value in the chain like Set, or like SetIfAbsent or SetIfPresent if the options
+// are conditional.
+func (c *chained) SetOpts(ctx context.Context, key string, value interface{}, opts SetOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	var written bool
+	var err error
+	switch {
+	case opts.IfAbsent:
+		written, err = c.SetIfAbsent(ctx, key, value, opts.TTL, opts.Tags)
+	case opts.IfPresent:
+		written, err = c.SetIfPresent(ctx, key, value, opts.TTL, opts.Tags)
+	default:
+		return c.Set(ctx, key, value, opts.TTL, opts.Tags)
+	}
+	if err == nil && !written {
+		return ErrNotStored
+	}
+	return err
+}
+
+// SetIfAbsent stores the value if the key is absent from the last cache manager of the chain, which
+// decides for the whole chain, and then sets it in the other ones.
+func (c *chained) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	return c.setIf(ctx, key, value, ttl, tags, Cacher.SetIfAbsent)
+}
+
+// SetIfPresent stores the value if the key is present in the last cache manager of the chain, which
+// decides for the whole chain, and then sets it in the other ones.
+func (c *chained) SetIfPresent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	return c.setIf(ctx, key, value, ttl, tags, Cacher.SetIfPresent)
+}
+
+func (c *chained) setIf(
+	ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string,
+	set func(c Cacher, ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error),
+) (bool, error) {
+	if c.m.IsReadOnly() {
+		return false, ErrReadOnly
+	}
+	if len(c.chain) == 0 {
+		return false, nil
+	}
+
+	key, err := c.m.prepareKey(key)
+	if err != nil {
+		return false, err
+	}
+	tags = c.m.prepareTags(tags)
+
+	last := len(c.chain) - 1
+	written, err := set(c.m.managers[c.chain[last]], ctx, key, value, c.layerTTL(c.chain[last], ttl), tags)
+	if err != nil || !written {
+		return false, err
+	}
+
+	var errors []error
+	for _, managerName := range c.chain[:last] {
+		manager := c.m.managers[managerName]
+		err := manager.Set(ctx, key, value, c.layerTTL(managerName, ttl), tags)
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+	if len(errors) > 0 {
+		return true, fmt.Errorf("errors occurred while setting value in chain: %v", errors)
+	}
+	return true, nil
+}
+
+func (c *chained) SetMany(ctx context.Context, items ...CacheItem) error {
+	return c.MSet(ctx, items)
+}
+
+// MSet stores the items in every cache manager of the chain with one MSet each.
+func (c *chained) MSet(ctx context.Context, items []CacheItem) error {
+	if c.m.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	errs := make(MultiError, len(items))
+	prepared := make([]CacheItem, 0, len(items))
+	indexes := make([]int, 0, len(items))
+
+	for i, item := range items {
+		key, err := c.m.prepareKey(item.Key)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		item.Key = key
+		item.Tags = c.m.prepareTags(item.Tags)
+		prepared = append(prepared, item)
+		indexes = append(indexes, i)
+	}
+
+	itemErrors := make([][]error, len(items))
 	for _, managerName := range c.chain {
+		err := c.m.managers[managerName].MSet(ctx, c.layerItems(managerName, prepared))
+
+		var batchErrs MultiError
+		isMulti := errors.As(err, &batchErrs) && len(batchErrs) == len(prepared)
+		for j, i := range indexes {
+			itemErr := err
+			if isMulti {
+				itemErr = batchErrs[j]
+			}
+			if itemErr != nil {
+				itemErrors[i] = append(itemErrors[i], itemErr)
+			}
+		}
+	}
+
+	for i, chainErrs := range itemErrors {
+		if len(chainErrs) > 0 {
+			errs[i] = fmt.Errorf("errors occurred while setting value in chain: %v", chainErrs)
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+func (c *chained) GetOrCreate(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string, create func(ctx context.Context) (interface{}, error)) error {
+	return c.m.getOrCreate(ctx, c, key, value, ttl, tags, create)
+}
+
+func (c *chained) GetOrSet(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string, loader func(ctx context.Context) (interface{}, error)) error {
+	return c.GetOrCreate(ctx, key, value, ttl, tags, loader)
+}
+
+func (c *chained) GetPER(ctx context.Context, key string, value interface{}, beta float64, loader PERLoader) error {
+	return c.m.getPER(ctx, c, key, value, beta, loader)
+}
+
+// GetKeyVersion returns the version counter of baseKey from the first cache manager of the chain that has it.
+func (c *chained) GetKeyVersion(ctx context.Context, baseKey string) (uint64, error) {
+	return getKeyVersion(ctx, c, baseKey)
+}
+
+// WatchKeyVersion polls the version counter of baseKey through the chain.
+func (c *chained) WatchKeyVersion(ctx context.Context, baseKey string) (<-chan uint64, error) {
+	return c.m.watchKeyVersion(ctx, c, baseKey)
+}
+
+func (c *chained) Get(ctx context.Context, key string, value interface{}) error {
+	key, err := c.m.prepareKey(key)
+	if err != nil {
+		return err
+	}
+
+	for i, managerName := range c.chain {
 		manager := c.m.managers[managerName]
-		err := manager.Get(ctx, key, value)
+		err := c.getThrough(ctx, manager, c.chain[:i], key, value)
 		if err == nil {
 			return nil
 		}
+		c.m.logLayerError("Get", key, managerName, c.m.removeCorrupt(ctx, manager, key, err))
+	}
+	if c.fallback != "" {
+		fallback := c.m.managers[c.fallback]
+		return c.m.removeCorrupt(ctx, fallback, key, c.getThrough(ctx, fallback, c.chain, key, value))
+	}
+	return fmt.Errorf("value %w in any cache manager", ErrNotFound)
+}
+
+// getThrough gets the value from the cache manager and, if read-through is enabled, promotes it into
+// the earlier cache managers.
+func (c *chained) getThrough(ctx context.Context, manager Cacher, earlier []string, key string, value interface{}) error {
+	if !c.readThrough || len(earlier) == 0 {
+		return manager.Get(ctx, key, value)
+	}
+
+	ttl, err := manager.GetWithTTL(ctx, key, value)
+	if err != nil {
+		return err
+	}
+
+	c.promote(ctx, manager, earlier, key, value, ttl)
+	return nil
+}
+
+// promote stores the value found in source in the earlier cache managers. Tags are copied if source implements
+// Inspector. Failures are only logged, as the read itself succeeded.
+func (c *chained) promote(ctx context.Context, source Cacher, earlier []string, key string, value interface{}, ttl time.Duration) {
+	if c.m.IsReadOnly() {
+		return
+	}
+
+	switch {
+	case c.promoteTTL > 0:
+		ttl = c.promoteTTL
+	case ttl == NoExpiry:
+		ttl = DefaultCacheTime
+	case ttl <= 0:
+		return
+	}
+
+	var tags []string
+	if inspector, ok := As[Inspector](source); ok {
+		if result, err := inspector.Inspect(ctx, key); err == nil {
+			tags = result.Tags
+		}
+	}
+
+	for _, managerName := range earlier {
+		if err := c.m.managers[managerName].Set(ctx, key, value, c.layerTTL(managerName, ttl), tags); err != nil {
+			c.m.logLayerError("Promote", key, managerName, err)
+		}
+	}
+}
+
+func (c *chained) GetWithTTL(ctx context.Context, key string, value interface{}) (time.Duration, error) {
+	key, err := c.m.prepareKey(key)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, managerName := range c.chain {
+		manager := c.m.managers[managerName]
+		ttl, err := manager.GetWithTTL(ctx, key, value)
+		if err == nil {
+			return ttl, nil
+		}
+		c.m.logLayerError("GetWithTTL", key, managerName, c.m.removeCorrupt(ctx, manager, key, err))
 	}
 	if c.fallback != "" {
-		return c.m.managers[c.fallback].Get(ctx, key, value)
+		fallback := c.m.managers[c.fallback]
+		ttl, err := fallback.GetWithTTL(ctx, key, value)
+		return ttl, c.m.removeCorrupt(ctx, fallback, key, err)
+	}
+	return 0, fmt.Errorf("value %w in any cache manager", ErrNotFound)
+}
+
+// GetAndDelete takes the value from the first cache manager of the chain that has it, and removes it from
+// all of them.
+func (c *chained) GetAndDelete(ctx context.Context, key string, value interface{}) error {
+	if c.m.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	key, err := c.m.prepareKey(key)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	var errors []error
+	for _, managerName := range c.chain {
+		manager := c.m.managers[managerName]
+		if found {
+			err = manager.Remove(ctx, key)
+		} else if err = manager.GetAndDelete(ctx, key, value); err == nil {
+			found = true
+			continue
+		}
+		if err != nil && !IsNotFound(err) {
+			errors = append(errors, err)
+		}
+	}
+	if len(errors) > 0 {
+		return fmt.Errorf("errors occurred while taking value from chain: %v", errors)
+	}
+	if !found {
+		return fmt.Errorf("value %w in any cache manager", ErrNotFound)
+	}
+	return nil
+}
+
+// Touch resets the expiry of the key in every cache manager of the chain that has it.
+func (c *chained) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	if c.m.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	key, err := c.m.prepareKey(key)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	var errors []error
+	for _, managerName := range c.chain {
+		err := c.m.managers[managerName].Touch(ctx, key, c.layerTTL(managerName, ttl))
+		if err == nil {
+			found = true
+		} else if !IsNotFound(err) {
+			errors = append(errors, err)
+		}
+	}
+	if len(errors) > 0 {
+		return fmt.Errorf("errors occurred while touching key in chain: %v", errors)
+	}
+	if !found {
+		return fmt.Errorf("value %w in any cache manager", ErrNotFound)
 	}
-	return fmt.Errorf("value not found in any cache manager")
+	return nil
 }
 
-// ... [Previous code]
+// GetBatchWithSource tries each cache manager in the chain, and the fallback, for all keys not found yet, with one
+// batch per cache manager. Errors other than misses don't stop the lookup; they are returned with the results.
+func (c *chained) GetBatchWithSource(ctx context.Context, keys []string, factory func() interface{}) (map[string]GetResult, error) {
+	results := make(map[string]GetResult, len(keys))
+	pending := make([]string, 0, len(keys))
+	prepared := make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		preparedKey, err := c.m.prepareKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		results[key] = GetResult{}
+		pending = append(pending, key)
+		prepared = append(prepared, preparedKey)
+	}
+
+	sources := c.chain
+	if c.fallback != "" {
+		sources = append(append([]string{}, c.chain...), c.fallback)
+	}
+
+	var errs []error
+	for _, managerName := range sources {
+		if len(pending) == 0 {
+			break
+		}
+
+		values := make([]interface{}, len(pending))
+		for i := range values {
+			values[i] = factory()
+		}
+
+		ttls, err := mgetWithTTL(ctx, c.m.managers[managerName], prepared, values)
+
+		var batchErrs MultiError
+		isMulti := errors.As(err, &batchErrs) && len(batchErrs) == len(pending)
+		if err != nil && !isMulti {
+			errs = append(errs, err)
+			continue
+		}
+
+		var missing, missingPrepared []string
+		for i, key := range pending {
+			var keyErr error
+			if isMulti {
+				keyErr = batchErrs[i]
+			}
+
+			if keyErr == nil {
+				results[key] = GetResult{Value: values[i], Driver: managerName, TTLRemaining: ttls[i]}
+				continue
+			}
+			if !IsNotFound(keyErr) {
+				errs = append(errs, keyErr)
+			}
+			missing = append(missing, key)
+			missingPrepared = append(missingPrepared, prepared[i])
+		}
+		pending, prepared = missing, missingPrepared
+	}
+
+	if len(errs) > 0 {
+		return results, fmt.Errorf("errors occurred while getting batch from chain: %w", errors.Join(errs...))
+	}
+	return results, nil
+}
 
 func (c *chained) Remove(ctx context.Context, key string) error {
+	if c.m.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	key, err := c.m.prepareKey(key)
+	if err != nil {
+		return err
+	}
+
 	var errors []error
 	for _, managerName := range c.chain {
 		manager := c.m.managers[managerName]
-		err := manager.Remove(ctx, key)
+		err := c.runLayer(ctx, managerName, "Remove", key, func(ctx context.Context) error {
+			return manager.Remove(ctx, key)
+		})
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -114,11 +569,39 @@ func (c *chained) Remove(ctx context.Context, key string) error {
 	return nil
 }
 
+func (c *chained) Flush(ctx context.Context) error {
+	if c.m.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	var errors []error
+	for _, managerName := range c.chain {
+		manager := c.m.managers[managerName]
+		err := manager.Flush(ctx)
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+	if len(errors) > 0 {
+		return fmt.Errorf("errors occurred while flushing chain: %v", errors)
+	}
+	return nil
+}
+
 func (c *chained) RemoveByTag(ctx context.Context, tag string) error {
+	if c.m.IsReadOnly() {
+		return ErrReadOnly
+	}
+
 	var errors []error
 	for _, managerName := range c.chain {
 		manager := c.m.managers[managerName]
-		err := manager.RemoveByTag(ctx, tag)
+		err := c.runLayer(ctx, managerName, "RemoveByTag", "", func(ctx context.Context) error {
+			if err := c.m.checkTagSize(ctx, manager, c.m.prepareTag(tag)); err != nil {
+				return err
+			}
+			return manager.RemoveByTag(ctx, c.m.prepareTag(tag))
+		})
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -130,10 +613,22 @@ func (c *chained) RemoveByTag(ctx context.Context, tag string) error {
 }
 
 func (c *chained) RemoveByTags(ctx context.Context, tags []string) error {
+	if c.m.IsReadOnly() {
+		return ErrReadOnly
+	}
+
 	var errors []error
 	for _, managerName := range c.chain {
 		manager := c.m.managers[managerName]
-		err := manager.RemoveByTags(ctx, tags)
+		var err error
+		for _, tag := range c.m.prepareTags(tags) {
+			if err = c.m.checkTagSize(ctx, manager, tag); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = manager.RemoveByTags(ctx, c.m.prepareTags(tags))
+		}
 		if err != nil {
 			errors = append(errors, err)
 		}
@@ -145,6 +640,11 @@ func (c *chained) RemoveByTags(ctx context.Context, tags []string) error {
 }
 
 func (c *chained) Exists(ctx context.Context, key string) (bool, error) {
+	key, err := c.m.prepareKey(key)
+	if err != nil {
+		return false, err
+	}
+
 	for _, managerName := range c.chain {
 		manager := c.m.managers[managerName]
 		exists, err := manager.Exists(ctx, key)
@@ -155,10 +655,19 @@ func (c *chained) Exists(ctx context.Context, key string) (bool, error) {
 	if c.fallback != "" {
 		return c.m.managers[c.fallback].Exists(ctx, key)
 	}
-	return false, fmt.Errorf("key not found in any cache manager")
+	return false, fmt.Errorf("key %w in any cache manager", ErrNotFound)
 }
 
 func (c *chained) Increment(ctx context.Context, key string) error {
+	if c.m.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	key, err := c.m.prepareKey(key)
+	if err != nil {
+		return err
+	}
+
 	var errors []error
 	for _, managerName := range c.chain {
 		manager := c.m.managers[managerName]
@@ -174,6 +683,15 @@ func (c *chained) Increment(ctx context.Context, key string) error {
 }
 
 func (c *chained) Decrement(ctx context.Context, key string) error {
+	if c.m.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	key, err := c.m.prepareKey(key)
+	if err != nil {
+		return err
+	}
+
 	var errors []error
 	for _, managerName := range c.chain {
 		manager := c.m.managers[managerName]
@@ -188,28 +706,351 @@ func (c *chained) Decrement(ctx context.Context, key string) error {
 	return nil
 }
 
+// IncrBy increments the key in every cache manager of the chain and returns the value of the first one.
+func (c *chained) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	return c.addInt(ctx, key, delta, "incrementing")
+}
+
+// DecrBy decrements the key in every cache manager of the chain and returns the value of the first one.
+func (c *chained) DecrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	return c.addInt(ctx, key, -delta, "decrementing")
+}
+
+func (c *chained) addInt(ctx context.Context, key string, delta int64, action string) (int64, error) {
+	if c.m.IsReadOnly() {
+		return 0, ErrReadOnly
+	}
+
+	key, err := c.m.prepareKey(key)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		result int64
+		found  bool
+		errors []error
+	)
+	for _, managerName := range c.chain {
+		manager := c.m.managers[managerName]
+		value, err := manager.IncrBy(ctx, key, delta)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		if !found {
+			result, found = value, true
+		}
+	}
+	if len(errors) > 0 {
+		return result, fmt.Errorf("errors occurred while %s key in chain: %v", action, errors)
+	}
+	return result, nil
+}
+
+// IncrementFloat increments the key in every cache manager of the chain and returns the value of the first one.
+func (c *chained) IncrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	return c.addFloat(ctx, key, delta, "incrementing")
+}
+
+// DecrementFloat decrements the key in every cache manager of the chain and returns the value of the first one.
+func (c *chained) DecrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	return c.addFloat(ctx, key, -delta, "decrementing")
+}
+
+func (c *chained) addFloat(ctx context.Context, key string, delta float64, action string) (float64, error) {
+	if c.m.IsReadOnly() {
+		return 0, ErrReadOnly
+	}
+
+	key, err := c.m.prepareKey(key)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		result float64
+		found  bool
+		errors []error
+	)
+	for _, managerName := range c.chain {
+		manager := c.m.managers[managerName]
+		value, err := manager.IncrementFloat(ctx, key, delta)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		if !found {
+			result, found = value, true
+		}
+	}
+	if len(errors) > 0 {
+		return result, fmt.Errorf("errors occurred while %s key in chain: %v", action, errors)
+	}
+	return result, nil
+}
+
 func (c *chained) GetKeysByTag(ctx context.Context, tag string) ([]string, error) {
+	tag = c.m.prepareTag(tag)
+
 	var allKeys []string
 	for _, managerName := range c.chain {
 		manager := c.m.managers[managerName]
+		if err := c.m.checkTagSize(ctx, manager, tag); err != nil {
+			continue
+		}
 		keys, err := manager.GetKeysByTag(ctx, tag)
 		if err == nil {
-			allKeys = append(allKeys, keys...)
+			allKeys = append(allKeys, c.m.stripGlobalPrefix(manager, keys)...)
 		}
 	}
 	if len(allKeys) == 0 && c.fallback != "" {
-		return c.m.managers[c.fallback].GetKeysByTag(ctx, tag)
+		fallback := c.m.managers[c.fallback]
+		keys, err := fallback.GetKeysByTag(ctx, tag)
+		if err != nil {
+			return nil, err
+		}
+		return c.m.stripGlobalPrefix(fallback, keys), nil
 	}
 	return allKeys, nil
 }
 
+func (c *chained) ForceRemoveByTag(ctx context.Context, tag string, batchSize int) error {
+	if c.m.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	var errors []error
+	for _, managerName := range c.chain {
+		manager := c.m.managers[managerName]
+		err := forceRemoveByTag(ctx, manager, c.m.prepareTag(tag), batchSize)
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+	if len(errors) > 0 {
+		return fmt.Errorf("errors occurred while force removing by tag in chain: %v", errors)
+	}
+	return nil
+}
+
+// Inspect dumps the full metadata of the key in the first cache manager of the chain that has it, or the fallback.
+func (c *chained) Inspect(ctx context.Context, key string) (*InspectResult, error) {
+	key, err := c.m.prepareKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, managerName := range c.chain {
+		result, err := inspect(ctx, managerName, c.m.managers[managerName], key)
+		if err == nil {
+			return result, nil
+		}
+	}
+	if c.fallback != "" {
+		return inspect(ctx, c.fallback, c.m.managers[c.fallback], key)
+	}
+	return nil, fmt.Errorf("value %w in any cache manager", ErrNotFound)
+}
+
+func (c *chained) AllBackendInfo(ctx context.Context) (map[string]BackendInfo, error) {
+	return c.m.AllBackendInfo(ctx)
+}
+
+func (c *chained) EffectiveKey(key string) string {
+	return c.m.EffectiveKey(key)
+}
+
 // Override method to create a new chain with the given names and use it as the current call
 func (c *chained) Override(names ...string) ChainedManager {
 	newChain := &chained{
-		m:        c.m,
-		chain:    names,
-		fallback: c.fallback,
+		m:           c.m,
+		chain:       names,
+		fallback:    c.fallback,
+		warmWorkers: c.warmWorkers,
+		readThrough: c.readThrough,
+		promoteTTL:  c.promoteTTL,
+		ttlScales:   c.ttlScales,
+		minLayerTTL: c.minLayerTTL,
+		async:       c.async,
+		pool:        c.pool,
 	}
 
 	return newChain
 }
+
+func (c *chained) SetWarmWorkers(n int) {
+	c.warmWorkers = n
+}
+
+// WarmChainFromTail rehydrates the earlier cache managers in the chain from the last one
+func (c *chained) WarmChainFromTail(ctx context.Context, keys []string) error {
+	if c.m.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	if len(c.chain) < 2 {
+		return nil
+	}
+
+	prepared := make([]string, 0, len(keys))
+	var errors []error
+	for _, key := range keys {
+		finalKey, err := c.m.prepareKey(key)
+		if err != nil {
+			errors = append(errors, err)
+			continue
+		}
+		prepared = append(prepared, finalKey)
+	}
+
+	return c.warm(ctx, prepared, errors)
+}
+
+// warm copies the prepared keys from the last cache manager into the earlier ones, reporting the errors
+// with the ones already found.
+func (c *chained) warm(ctx context.Context, keys []string, errors []error) error {
+	tail := c.m.managers[c.chain[len(c.chain)-1]]
+	heads := c.chain[:len(c.chain)-1]
+
+	workers := c.warmWorkers
+	if workers <= 0 {
+		workers = DefaultWarmWorkers
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	keysCh := make(chan string)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range keysCh {
+				if err := c.warmKey(ctx, tail, heads, key); err != nil {
+					mu.Lock()
+					errors = append(errors, err)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, key := range keys {
+		keysCh <- key
+	}
+	close(keysCh)
+	wg.Wait()
+
+	if len(errors) > 0 {
+		return fmt.Errorf("errors occurred while warming chain: %v", errors)
+	}
+	return nil
+}
+
+// warmKey copies the key from tail into heads with its remaining TTL and its tags, if tail implements Inspector.
+// Heads of the same Format as the tail get the serialized value, if both implement EncodedCacher, so the value
+// needs no type to be copied, and the bytes stored by SetRaw are copied into heads that implement RawCacher.
+// Other heads get the value decoded into an interface{}.
+func (c *chained) warmKey(ctx context.Context, tail Cacher, heads []string, key string) error {
+	ttl := DefaultCacheTime
+	var tags []string
+	if inspector, ok := As[Inspector](tail); ok {
+		result, err := inspector.Inspect(ctx, key)
+		if err != nil {
+			if IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+
+		if result.TTLRemaining > 0 {
+			ttl = result.TTLRemaining
+		}
+		tags = result.Tags
+	} else if exists, err := tail.Exists(ctx, key); err != nil || !exists {
+		return err
+	}
+
+	var (
+		data []byte
+		raw  bool
+	)
+	encoder, hasEncoded := As[EncodedCacher](tail)
+	if hasEncoded {
+		var err error
+		data, err = encoder.GetEncoded(ctx, key)
+		if errors.Is(err, ErrRawValue) {
+			data, err = c.getRaw(ctx, tail, key)
+			raw = err == nil
+		}
+		if err != nil {
+			if IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	var value interface{}
+	decoded := false
+	for _, managerName := range heads {
+		manager := c.m.managers[managerName]
+		layerTTL := c.layerTTL(managerName, ttl)
+
+		if rawCacher, ok := As[RawCacher](manager); ok && raw {
+			if err := rawCacher.SetRaw(ctx, key, data, layerTTL, tags); err != nil {
+				return err
+			}
+			continue
+		}
+		if head, ok := As[EncodedCacher](manager); ok && hasEncoded && !raw && head.Format() == encoder.Format() {
+			if err := head.SetEncoded(ctx, key, data, layerTTL, tags); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !decoded {
+			if err := tail.Get(ctx, key, &value); err != nil {
+				return err
+			}
+			decoded = true
+		}
+		if err := manager.Set(ctx, key, value, layerTTL, tags); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getRaw retrieves the bytes stored by SetRaw, if tail implements RawGetter.
+func (c *chained) getRaw(ctx context.Context, tail Cacher, key string) ([]byte, error) {
+	getter, ok := As[RawGetter](tail)
+	if !ok {
+		return nil, ErrRawValue
+	}
+
+	return getter.GetRaw(ctx, key)
+}
+
+// WarmChainByTag warms the chain with the keys associated with the tag in the last cache manager
+func (c *chained) WarmChainByTag(ctx context.Context, tag string) error {
+	if c.m.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	if len(c.chain) < 2 {
+		return nil
+	}
+
+	tail := c.m.managers[c.chain[len(c.chain)-1]]
+	keys, err := tail.GetKeysByTag(ctx, c.m.prepareTag(tag))
+	if err != nil {
+		return err
+	}
+
+	return c.warm(ctx, stripDriverPrefix(tail, keys), nil)
+}
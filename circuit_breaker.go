@@ -0,0 +1,171 @@
+package cachemar
+
+import (
+	"sync"
+	"time"
+)
+
+// circuitState is the state of the circuit breaker guarding the current cache manager.
+type circuitState int32
+
+const (
+	// circuitClosed routes operations to the current cache manager.
+	circuitClosed circuitState = iota
+	// circuitOpen routes operations to the fallback cache manager until the check interval elapses.
+	circuitOpen
+	// circuitHalfOpen lets one probe operation through to the current cache manager, and routes the
+	// others to the fallback until the probe decides whether to close or reopen the circuit.
+	circuitHalfOpen
+)
+
+// root returns the manager holding the shared state of a namespace.
+func (c *manager) root() *manager {
+	if c.parent != nil {
+		return c.parent
+	}
+
+	return c
+}
+
+// active returns the cache manager operations are forwarded to: the current one, or the fallback while
+// the circuit breaker is open. done must be called with the error of the operation once it ends.
+func (c *manager) active() (cacher Cacher, done func(err *error)) {
+	root := c.root()
+	if root.circuitFallback == "" || root.circuitFallback == root.current {
+		return c.Current(), noopDone
+	}
+
+	return root.route(c.Current())
+}
+
+func noopDone(*error) {}
+
+// route implements the state machine of the circuit breaker. While closed, the primary is pinged in the
+// background every check interval, and the circuit opens when the ping fails or, with a failure threshold,
+// when too many operations fail within the window. Once the check interval
+// has elapsed with the circuit open, it becomes half-open and a single operation probes the primary. The
+// probe is released by done, so it is decided by the operation as a whole.
+func (c *manager) route(primary Cacher) (Cacher, func(err *error)) {
+	now := time.Now()
+
+	switch circuitState(c.circuitState.Load()) {
+	case circuitClosed:
+		c.pingPrimary(primary, now)
+		if c.failures != nil {
+			return primary, c.recordFailure
+		}
+		return primary, noopDone
+	case circuitOpen:
+		if now.Sub(time.Unix(0, c.circuitSince.Load())) < c.checkInterval {
+			return c.managers[c.circuitFallback], noopDone
+		}
+		if c.circuitState.CompareAndSwap(int32(circuitOpen), int32(circuitHalfOpen)) {
+			c.notifyCircuit(c.onCircuitHalfOpen)
+		}
+	}
+
+	if c.halfOpenInFlight.CompareAndSwap(false, true) {
+		return primary, func(err *error) { c.finishProbe(isCircuitFailure(*err)) }
+	}
+	return c.managers[c.circuitFallback], noopDone
+}
+
+// pingPrimary pings the primary in the background if the check interval has elapsed since the last ping.
+func (c *manager) pingPrimary(primary Cacher, now time.Time) {
+	since := c.circuitSince.Load()
+	if now.Sub(time.Unix(0, since)) < c.checkInterval || !c.circuitSince.CompareAndSwap(since, now.UnixNano()) {
+		return
+	}
+
+	go func() {
+		if err := primary.Ping(); err != nil {
+			c.openCircuit(circuitClosed)
+		}
+	}()
+}
+
+// recordFailure opens the circuit when err is a failure that reaches the failure threshold.
+func (c *manager) recordFailure(err *error) {
+	if isCircuitFailure(*err) && c.failures.add(time.Now()) {
+		c.openCircuit(circuitClosed)
+	}
+}
+
+// openCircuit opens the circuit if it is in the given state, and restarts the check interval.
+func (c *manager) openCircuit(from circuitState) {
+	if c.circuitState.CompareAndSwap(int32(from), int32(circuitOpen)) {
+		c.circuitSince.Store(time.Now().UnixNano())
+		c.notifyCircuit(c.onCircuitOpen)
+	}
+}
+
+// finishProbe closes the circuit if the probe succeeded, or reopens it.
+func (c *manager) finishProbe(failed bool) {
+	if failed {
+		c.openCircuit(circuitHalfOpen)
+	} else if c.circuitState.CompareAndSwap(int32(circuitHalfOpen), int32(circuitClosed)) {
+		c.circuitSince.Store(time.Now().UnixNano())
+		if c.failures != nil {
+			c.failures.reset()
+		}
+		c.notifyCircuit(c.onCircuitClose)
+	}
+	c.halfOpenInFlight.Store(false)
+}
+
+// notifyCircuit calls the callback of a state transition, if set, with the name of the current cache manager.
+func (c *manager) notifyCircuit(callback func(primaryCacher string)) {
+	if callback != nil {
+		callback(c.current)
+	}
+}
+
+// failureRing holds the times of the most recent failures of the primary, as many as the failure threshold.
+type failureRing struct {
+	mu     sync.Mutex
+	times  []time.Time
+	next   int
+	window time.Duration
+}
+
+func newFailureRing(failureCount int, window time.Duration) *failureRing {
+	return &failureRing{times: make([]time.Time, failureCount), window: window}
+}
+
+// add records a failure and reports whether the threshold is reached, i.e. the oldest failure in the full
+// ring happened within the window. The ring is emptied when it is.
+func (r *failureRing) add(now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.times[r.next] = now
+	r.next = (r.next + 1) % len(r.times)
+
+	oldest := r.times[r.next]
+	if oldest.IsZero() || now.Sub(oldest) > r.window {
+		return false
+	}
+
+	r.clear()
+	return true
+}
+
+func (r *failureRing) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clear()
+}
+
+func (r *failureRing) clear() {
+	for i := range r.times {
+		r.times[i] = time.Time{}
+	}
+	r.next = 0
+}
+
+// isCircuitFailure reports whether err means the cache manager is unavailable, as opposed to errors
+// about the key or the value such as ErrNotFound.
+func isCircuitFailure(err error) bool {
+	return IsConnectionError(err) || IsTimeout(err)
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/spf13/cobra"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+type cli struct {
+	cfg        config
+	configPath string
+	jsonOutput bool
+	conn       *connection
+}
+
+func newRootCommand() *cobra.Command {
+	c := &cli{}
+
+	root := &cobra.Command{
+		Use:           "cachemar-cli",
+		Short:         "Inspect and manage caches through cachemar",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return c.connect()
+		},
+		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+			if c.conn == nil {
+				return nil
+			}
+			return c.conn.Close()
+		},
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&c.cfg.RedisURL, "redis-url", "", "Redis connection URL, e.g. redis://localhost:6379/0, redis+sentinel://host:26379/master or redis+cluster://node1:7000,node2:7001")
+	flags.StringSliceVar(&c.cfg.MemcachedServers, "memcached-servers", nil, "comma separated list of Memcached servers")
+	flags.StringVar(&c.cfg.Prefix, "prefix", "", "key prefix used by the drivers")
+	flags.StringVar(&c.configPath, "config", "", "path to a JSON config file")
+	flags.BoolVar(&c.jsonOutput, "json", false, "output machine-readable JSON")
+
+	root.AddCommand(
+		c.getCommand(),
+		c.setCommand(),
+		c.delCommand(),
+		c.delTagCommand(),
+		c.keysByTagCommand(),
+		c.pingCommand(),
+		c.statsCommand(),
+		c.flushCommand(),
+	)
+
+	return root
+}
+
+func (c *cli) connect() error {
+	cfg := c.cfg
+	if c.configPath != "" {
+		fileCfg, err := loadConfig(c.configPath)
+		if err != nil {
+			return err
+		}
+
+		// Flags take precedence over the config file.
+		if cfg.RedisURL == "" {
+			cfg.RedisURL = fileCfg.RedisURL
+		}
+		if len(cfg.MemcachedServers) == 0 {
+			cfg.MemcachedServers = fileCfg.MemcachedServers
+		}
+		if cfg.Prefix == "" {
+			cfg.Prefix = fileCfg.Prefix
+		}
+	}
+
+	conn, err := connect(&cfg)
+	if err != nil {
+		return err
+	}
+
+	c.conn = conn
+	return nil
+}
+
+// print writes v as JSON when --json is set and the human readable text otherwise.
+func (c *cli) print(cmd *cobra.Command, text string, v interface{}) error {
+	if c.jsonOutput {
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+
+	_, err := fmt.Fprintln(cmd.OutOrStdout(), text)
+	return err
+}
+
+func (c *cli) getCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <key>",
+		Short: "Get the value stored under a key",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var value json.RawMessage
+			if err := c.conn.manager.Get(cmd.Context(), args[0], &value); err != nil {
+				return err
+			}
+
+			var pretty bytes.Buffer
+			if err := json.Indent(&pretty, value, "", "  "); err != nil {
+				pretty.Reset()
+				pretty.Write(value)
+			}
+
+			return c.print(cmd, pretty.String(), map[string]interface{}{"key": args[0], "value": value})
+		},
+	}
+}
+
+func (c *cli) setCommand() *cobra.Command {
+	var (
+		ttl  time.Duration
+		tags []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Store a value under a key. JSON values are stored as-is, anything else as a string",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var value interface{} = args[1]
+			if json.Valid([]byte(args[1])) {
+				value = json.RawMessage(args[1])
+			}
+
+			if err := c.conn.manager.Set(cmd.Context(), args[0], value, ttl, tags); err != nil {
+				return err
+			}
+
+			return c.print(cmd, "OK", map[string]interface{}{"key": args[0], "ok": true})
+		},
+	}
+
+	cmd.Flags().DurationVar(&ttl, "ttl", cachemar.DefaultCacheTime, "time-to-live of the value")
+	cmd.Flags().StringSliceVar(&tags, "tags", nil, "comma separated list of tags")
+
+	return cmd
+}
+
+func (c *cli) delCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "del <key>",
+		Short: "Remove a key",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := c.conn.manager.Remove(cmd.Context(), args[0]); err != nil {
+				return err
+			}
+
+			return c.print(cmd, "OK", map[string]interface{}{"key": args[0], "ok": true})
+		},
+	}
+}
+
+func (c *cli) delTagCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "del-tag <tag>",
+		Short: "Remove all keys associated with a tag",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := c.conn.manager.RemoveByTag(cmd.Context(), args[0]); err != nil {
+				return err
+			}
+
+			return c.print(cmd, "OK", map[string]interface{}{"tag": args[0], "ok": true})
+		},
+	}
+}
+
+func (c *cli) keysByTagCommand() *cobra.Command {
+	var driver string
+
+	cmd := &cobra.Command{
+		Use:   "keys-by-tag <tag>",
+		Short: "List the keys associated with a tag",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cacher := c.conn.manager.Current()
+			if driver != "" {
+				cacher = c.conn.manager.Use(driver)
+				if cacher == nil {
+					return fmt.Errorf("driver %q is not configured", driver)
+				}
+			}
+
+			keys, err := cacher.GetKeysByTag(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			if keys == nil {
+				keys = []string{}
+			}
+
+			return c.print(cmd, strings.Join(keys, "\n"), map[string]interface{}{"tag": args[0], "keys": keys})
+		},
+	}
+
+	cmd.Flags().StringVar(&driver, "driver", "", "driver to query (redis or memcached)")
+
+	return cmd
+}
+
+func (c *cli) pingCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "ping",
+		Short: "Check that all configured caches are reachable",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := c.conn.manager.Ping(); err != nil {
+				return err
+			}
+
+			return c.print(cmd, "PONG", map[string]interface{}{"ok": true})
+		},
+	}
+}
+
+func (c *cli) statsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stats",
+		Short: "Show hits, misses, evictions and item counts",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stats, err := c.conn.stats(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			if c.jsonOutput {
+				return c.print(cmd, "", stats)
+			}
+
+			return writeStatsTable(cmd.OutOrStdout(), stats)
+		},
+	}
+}
+
+func (c *cli) flushCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "flush",
+		Short: "Remove all items from all configured caches",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := c.conn.flush(cmd.Context()); err != nil {
+				return err
+			}
+
+			return c.print(cmd, "OK", map[string]interface{}{"ok": true})
+		},
+	}
+}
+
+func (c *connection) flush(ctx context.Context) error {
+	if c.redis != nil {
+		if err := c.redis.FlushDB(ctx).Err(); err != nil {
+			return fmt.Errorf("failed to flush Redis: %w", err)
+		}
+	}
+
+	if len(c.memcached) > 0 {
+		if err := memcache.New(c.memcached...).FlushAll(); err != nil {
+			return fmt.Errorf("failed to flush Memcached: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memcached"
+	"github.com/stremovskyy/cachemar/drivers/redis"
+	"github.com/stremovskyy/cachemar/dsn"
+)
+
+// config describes the caches the CLI connects to.
+type config struct {
+	RedisURL         string   `json:"redis_url"`
+	MemcachedServers []string `json:"memcached_servers"`
+	Prefix           string   `json:"prefix"`
+}
+
+func loadConfig(path string) (*config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	cfg := &config{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// connection holds the manager together with the raw clients used for stats and flush.
+type connection struct {
+	manager   cachemar.Manager
+	redis     goredis.UniversalClient
+	redisAddr string
+	memcached []string
+}
+
+func connect(cfg *config) (*connection, error) {
+	if cfg.RedisURL == "" && len(cfg.MemcachedServers) == 0 {
+		return nil, errors.New("no cache configured: use --redis-url, --memcached-servers or --config")
+	}
+
+	conn := &connection{manager: cachemar.New()}
+
+	if len(cfg.MemcachedServers) > 0 {
+		conn.memcached = cfg.MemcachedServers
+		conn.manager.Register(
+			cachemar.MemcachedCacherName.String(), memcached.New(
+				&memcached.Options{
+					Servers: cfg.MemcachedServers,
+					Prefix:  cfg.Prefix,
+				},
+			),
+		)
+	}
+
+	if cfg.RedisURL != "" {
+		redisOptions, err := dsn.ParseRedisURL(cfg.RedisURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis url: %w", err)
+		}
+		if cfg.Prefix != "" {
+			redisOptions.Prefix = cfg.Prefix
+		}
+
+		conn.redis = goredis.NewUniversalClient(
+			&goredis.UniversalOptions{
+				Addrs:      redisAddrs(redisOptions),
+				MasterName: redisOptions.MasterName,
+				Password:   redisOptions.Password,
+				DB:         redisOptions.Database,
+				TLSConfig:  redisOptions.TLSConfig,
+			},
+		)
+		conn.redisAddr = strings.Join(redisAddrs(redisOptions), ",")
+		conn.manager.Register(cachemar.RedisCacherName.String(), redis.New(redisOptions))
+	}
+
+	return conn, nil
+}
+
+// redisAddrs returns the servers the raw client should connect to.
+func redisAddrs(options *redis.Options) []string {
+	switch {
+	case len(options.ClusterAddrs) > 0:
+		return options.ClusterAddrs
+	case len(options.SentinelAddrs) > 0:
+		return options.SentinelAddrs
+	default:
+		return []string{options.DSN}
+	}
+}
+
+func (c *connection) Close() error {
+	if c.redis != nil {
+		_ = c.redis.Close()
+	}
+
+	return c.manager.Close()
+}
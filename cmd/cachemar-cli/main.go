@@ -0,0 +1,14 @@
+// Command cachemar-cli inspects and manages caches through cachemar drivers.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
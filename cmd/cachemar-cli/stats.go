@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+const memcachedStatsTimeout = 5 * time.Second
+
+// stats is a snapshot of a single cache's counters.
+type stats struct {
+	Driver    string `json:"driver"`
+	Server    string `json:"server,omitempty"`
+	Hits      int64  `json:"hits"`
+	Misses    int64  `json:"misses"`
+	Evictions int64  `json:"evictions"`
+	Items     int64  `json:"items"`
+}
+
+func (c *connection) stats(ctx context.Context) ([]stats, error) {
+	result := make([]stats, 0)
+
+	if c.redis != nil {
+		s, err := c.redisStats(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+
+	for _, server := range c.memcached {
+		s, err := memcachedStats(server)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+
+	return result, nil
+}
+
+func (c *connection) redisStats(ctx context.Context) (stats, error) {
+	info, err := c.redis.Info(ctx, "stats").Result()
+	if err != nil {
+		return stats{}, fmt.Errorf("failed to get Redis stats: %w", err)
+	}
+
+	items, err := c.redis.DBSize(ctx).Result()
+	if err != nil {
+		return stats{}, fmt.Errorf("failed to get Redis db size: %w", err)
+	}
+
+	fields := parseFields(info, ":")
+
+	return stats{
+		Driver:    cachemar.RedisCacherName.String(),
+		Server:    c.redisAddr,
+		Hits:      fields["keyspace_hits"],
+		Misses:    fields["keyspace_misses"],
+		Evictions: fields["evicted_keys"],
+		Items:     items,
+	}, nil
+}
+
+// memcachedStats queries the "stats" command directly since gomemcache doesn't expose it.
+func memcachedStats(server string) (stats, error) {
+	conn, err := net.DialTimeout("tcp", server, memcachedStatsTimeout)
+	if err != nil {
+		return stats{}, fmt.Errorf("failed to connect to Memcached: %w", err)
+	}
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(memcachedStatsTimeout))
+
+	if _, err := fmt.Fprint(conn, "stats\r\n"); err != nil {
+		return stats{}, fmt.Errorf("failed to get Memcached stats: %w", err)
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "END" {
+			break
+		}
+		lines = append(lines, strings.TrimPrefix(line, "STAT "))
+	}
+	if err := scanner.Err(); err != nil {
+		return stats{}, fmt.Errorf("failed to get Memcached stats: %w", err)
+	}
+
+	fields := parseFields(strings.Join(lines, "\n"), " ")
+
+	return stats{
+		Driver:    cachemar.MemcachedCacherName.String(),
+		Server:    server,
+		Hits:      fields["get_hits"],
+		Misses:    fields["get_misses"],
+		Evictions: fields["evictions"],
+		Items:     fields["curr_items"],
+	}, nil
+}
+
+// parseFields parses "name<sep>value" lines, keeping the numeric ones.
+func parseFields(text string, sep string) map[string]int64 {
+	fields := make(map[string]int64)
+
+	for _, line := range strings.Split(text, "\n") {
+		name, value, ok := strings.Cut(strings.TrimSpace(line), sep)
+		if !ok {
+			continue
+		}
+
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			fields[name] = n
+		}
+	}
+
+	return fields
+}
+
+func writeStatsTable(out io.Writer, all []stats) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DRIVER\tSERVER\tHITS\tMISSES\tEVICTIONS\tITEMS")
+	for _, s := range all {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\n", s.Driver, s.Server, s.Hits, s.Misses, s.Evictions, s.Items)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,17 @@
+package cachemar
+
+import "fmt"
+
+// Codec serializes values stored in a cache.
+type Codec interface {
+	// Marshal encodes the value into bytes.
+	Marshal(v interface{}) ([]byte, error)
+
+	// Unmarshal decodes the bytes into the value pointed to by v.
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// CodecFormat identifies the serialization of codec, for EncodedCacher.Format.
+func CodecFormat(codec Codec) string {
+	return fmt.Sprintf("%T", codec)
+}
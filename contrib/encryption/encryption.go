@@ -0,0 +1,266 @@
+// Package encryption provides a Cacher that encrypts values with AES-256-GCM before they reach the wrapped cache manager.
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+// KeySize is the size of AES-256 keys.
+const KeySize = 32
+
+// nonceSize is the size of the random GCM nonce stored before the ciphertext.
+const nonceSize = 12
+
+// magic starts every encrypted value, so that values that were not encrypted are recognized.
+var magic = []byte("cmenc1:")
+
+var (
+	// ErrNotEncrypted is the cause of a decode error for a stored value that was not written encrypted.
+	ErrNotEncrypted = errors.New("encryption: value is not encrypted")
+	// ErrDecrypt is the cause of a decode error for a value none of the keys can decrypt.
+	ErrDecrypt = errors.New("encryption: value can't be decrypted with any key")
+	// ErrUnsupported is returned by the counter operations, which can't work on encrypted values.
+	ErrUnsupported = errors.New("encryption: operation not supported on encrypted values")
+)
+
+type encrypted struct {
+	wrapped cachemar.Cacher
+	aeads   []cipher.AEAD
+}
+
+// NewEncryptedCacher wraps the cache manager so that values are stored as JSON encrypted with the 32-byte key.
+// Keys and tags are not encrypted. The counter operations return ErrUnsupported.
+func NewEncryptedCacher(inner cachemar.Cacher, key []byte) (cachemar.Cacher, error) {
+	return NewEncryptedCacherWithKeyring(inner, [][]byte{key})
+}
+
+// NewEncryptedCacherWithKeyring is like NewEncryptedCacher with several keys for key rotation. Values are
+// encrypted with the first key, and decrypted with whichever key works.
+func NewEncryptedCacherWithKeyring(inner cachemar.Cacher, keys [][]byte) (cachemar.Cacher, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("encryption: no keys")
+	}
+
+	aeads := make([]cipher.AEAD, len(keys))
+	for i, key := range keys {
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("encryption: key %d is %d bytes, want %d", i, len(key), KeySize)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		if aeads[i], err = cipher.NewGCMWithNonceSize(block, nonceSize); err != nil {
+			return nil, err
+		}
+	}
+
+	return &encrypted{wrapped: inner, aeads: aeads}, nil
+}
+
+// seal serializes the value to JSON and encrypts it with the first key.
+func (e *encrypted) seal(value interface{}) ([]byte, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, len(magic)+nonceSize, len(magic)+nonceSize+len(plaintext)+e.aeads[0].Overhead())
+	copy(data, magic)
+	nonce := data[len(magic):]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return e.aeads[0].Seal(data, nonce, plaintext, nil), nil
+}
+
+// open decrypts the data with each key in turn and deserializes it into value.
+func (e *encrypted) open(key string, data []byte, value interface{}) error {
+	if !bytes.HasPrefix(data, magic) || len(data) < len(magic)+nonceSize {
+		return decodeError(key, ErrNotEncrypted)
+	}
+
+	nonce, ciphertext := data[len(magic):len(magic)+nonceSize], data[len(magic)+nonceSize:]
+	for _, aead := range e.aeads {
+		plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+		if err != nil {
+			continue
+		}
+
+		if err := json.Unmarshal(plaintext, value); err != nil {
+			return decodeError(key, err)
+		}
+		return nil
+	}
+
+	return decodeError(key, ErrDecrypt)
+}
+
+// decodeError reports a value that can't be decrypted like a driver reports one it can't decode,
+// so that cachemar.IsDecodeError and WithAutoRemoveCorrupt treat it alike.
+func decodeError(key string, err error) error {
+	return &cachemar.DriverError{Driver: "encryption", Operation: cachemar.OperationDeserialize, Key: key, Cause: err}
+}
+
+func (e *encrypted) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
+	data, err := e.seal(value)
+	if err != nil {
+		return err
+	}
+	return e.wrapped.Set(ctx, key, data, ttl, tags)
+}
+
+func (e *encrypted) SetOpts(ctx context.Context, key string, value interface{}, opts cachemar.SetOptions) error {
+	data, err := e.seal(value)
+	if err != nil {
+		return err
+	}
+	return e.wrapped.SetOpts(ctx, key, data, opts)
+}
+
+func (e *encrypted) MSet(ctx context.Context, items []cachemar.CacheItem) error {
+	sealed := make([]cachemar.CacheItem, len(items))
+	for i, item := range items {
+		data, err := e.seal(item.Value)
+		if err != nil {
+			return err
+		}
+		item.Value = data
+		sealed[i] = item
+	}
+	return e.wrapped.MSet(ctx, sealed)
+}
+
+func (e *encrypted) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	data, err := e.seal(value)
+	if err != nil {
+		return false, err
+	}
+	return e.wrapped.SetIfAbsent(ctx, key, data, ttl, tags)
+}
+
+func (e *encrypted) SetIfPresent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	data, err := e.seal(value)
+	if err != nil {
+		return false, err
+	}
+	return e.wrapped.SetIfPresent(ctx, key, data, ttl, tags)
+}
+
+func (e *encrypted) Get(ctx context.Context, key string, value interface{}) error {
+	var data []byte
+	if err := e.wrapped.Get(ctx, key, &data); err != nil {
+		return err
+	}
+	return e.open(key, data, value)
+}
+
+func (e *encrypted) GetWithTTL(ctx context.Context, key string, value interface{}) (time.Duration, error) {
+	var data []byte
+	ttl, err := e.wrapped.GetWithTTL(ctx, key, &data)
+	if err != nil {
+		return ttl, err
+	}
+	return ttl, e.open(key, data, value)
+}
+
+func (e *encrypted) MGet(ctx context.Context, keys []string, values []interface{}) error {
+	data := make([][]byte, len(keys))
+	targets := make([]interface{}, len(keys))
+	for i := range data {
+		targets[i] = &data[i]
+	}
+
+	errs := make(cachemar.MultiError, len(keys))
+	err := e.wrapped.MGet(ctx, keys, targets)
+	if err != nil && !errors.As(err, &errs) {
+		return err
+	}
+
+	for i, key := range keys {
+		if errs[i] == nil {
+			errs[i] = e.open(key, data[i], values[i])
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+func (e *encrypted) GetAndDelete(ctx context.Context, key string, value interface{}) error {
+	var data []byte
+	if err := e.wrapped.GetAndDelete(ctx, key, &data); err != nil {
+		return err
+	}
+	return e.open(key, data, value)
+}
+
+func (e *encrypted) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	return e.wrapped.Touch(ctx, key, ttl)
+}
+
+func (e *encrypted) Remove(ctx context.Context, key string) error {
+	return e.wrapped.Remove(ctx, key)
+}
+
+func (e *encrypted) RemoveByTag(ctx context.Context, tag string) error {
+	return e.wrapped.RemoveByTag(ctx, tag)
+}
+
+func (e *encrypted) RemoveByTags(ctx context.Context, tags []string) error {
+	return e.wrapped.RemoveByTags(ctx, tags)
+}
+
+func (e *encrypted) Flush(ctx context.Context) error {
+	return e.wrapped.Flush(ctx)
+}
+
+func (e *encrypted) Exists(ctx context.Context, key string) (bool, error) {
+	return e.wrapped.Exists(ctx, key)
+}
+
+func (e *encrypted) Increment(ctx context.Context, key string) error {
+	return ErrUnsupported
+}
+
+func (e *encrypted) Decrement(ctx context.Context, key string) error {
+	return ErrUnsupported
+}
+
+func (e *encrypted) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	return 0, ErrUnsupported
+}
+
+func (e *encrypted) DecrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	return 0, ErrUnsupported
+}
+
+func (e *encrypted) IncrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	return 0, ErrUnsupported
+}
+
+func (e *encrypted) DecrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	return 0, ErrUnsupported
+}
+
+func (e *encrypted) GetKeysByTag(ctx context.Context, tag string) ([]string, error) {
+	return e.wrapped.GetKeysByTag(ctx, tag)
+}
+
+func (e *encrypted) Ping() error {
+	return e.wrapped.Ping()
+}
+
+func (e *encrypted) Close() error {
+	return e.wrapped.Close()
+}
@@ -0,0 +1,22 @@
+// Package logging adapts structured loggers to cachemar.Logger.
+package logging
+
+import "go.uber.org/zap"
+
+// ZapAdapter logs through a zap.SugaredLogger. The fields are passed as its loosely typed key-value pairs.
+type ZapAdapter struct {
+	logger *zap.SugaredLogger
+}
+
+// NewZapAdapter returns a cachemar.Logger that logs through the logger.
+func NewZapAdapter(logger *zap.Logger) *ZapAdapter {
+	return &ZapAdapter{logger: logger.Sugar()}
+}
+
+func (a *ZapAdapter) Debug(msg string, fields ...interface{}) {
+	a.logger.Debugw(msg, fields...)
+}
+
+func (a *ZapAdapter) Error(msg string, fields ...interface{}) {
+	a.logger.Errorw(msg, fields...)
+}
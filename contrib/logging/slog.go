@@ -0,0 +1,30 @@
+//go:build go1.21
+
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogAdapter logs through a slog.Logger. It requires Go 1.21 or newer.
+type SlogAdapter struct {
+	logger *slog.Logger
+}
+
+// NewSlogAdapter returns a cachemar.Logger that logs through the logger. A nil logger means slog.Default().
+func NewSlogAdapter(logger *slog.Logger) *SlogAdapter {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &SlogAdapter{logger: logger}
+}
+
+func (a *SlogAdapter) Debug(msg string, fields ...interface{}) {
+	a.logger.Log(context.Background(), slog.LevelDebug, msg, fields...)
+}
+
+func (a *SlogAdapter) Error(msg string, fields ...interface{}) {
+	a.logger.Log(context.Background(), slog.LevelError, msg, fields...)
+}
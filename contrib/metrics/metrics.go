@@ -0,0 +1,267 @@
+// Package metrics provides a Cacher that records Prometheus metrics for every operation of the wrapped cache manager.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+// Values of the status label of the operations counter. A miss counts as StatusOK.
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+type collectors struct {
+	operations *prometheus.CounterVec
+	duration   *prometheus.HistogramVec
+	hits       *prometheus.CounterVec
+	misses     *prometheus.CounterVec
+}
+
+func newCollectors(reg prometheus.Registerer) *collectors {
+	return &collectors{
+		operations: register(
+			reg, prometheus.NewCounterVec(
+				prometheus.CounterOpts{
+					Name: "cachemar_operations_total",
+					Help: "Number of cache operations.",
+				}, []string{"driver", "operation", "status"},
+			),
+		),
+		duration: register(
+			reg, prometheus.NewHistogramVec(
+				prometheus.HistogramOpts{
+					Name:    "cachemar_operation_duration_seconds",
+					Help:    "Duration of cache operations.",
+					Buckets: prometheus.DefBuckets,
+				}, []string{"driver", "operation"},
+			),
+		),
+		hits: register(
+			reg, prometheus.NewCounterVec(
+				prometheus.CounterOpts{
+					Name: "cachemar_hits_total",
+					Help: "Number of cache reads that found the key.",
+				}, []string{"driver"},
+			),
+		),
+		misses: register(
+			reg, prometheus.NewCounterVec(
+				prometheus.CounterOpts{
+					Name: "cachemar_misses_total",
+					Help: "Number of cache reads that did not find the key.",
+				}, []string{"driver"},
+			),
+		),
+	}
+}
+
+// register registers the collector, or returns the one already registered under its name, so that several
+// cache managers can be wrapped with the same registerer. It panics on any other registration error.
+func register[T prometheus.Collector](reg prometheus.Registerer, collector T) T {
+	if err := reg.Register(collector); err != nil {
+		var registered prometheus.AlreadyRegisteredError
+		if errors.As(err, &registered) {
+			if existing, ok := registered.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+		panic(err)
+	}
+
+	return collector
+}
+
+type metered struct {
+	wrapped cachemar.Cacher
+	driver  string
+	metrics *collectors
+}
+
+// NewMetricsCacher wraps the cache manager so that every operation is counted and timed with the
+// driver label set to driverLabel. Get, GetWithTTL, GetAndDelete and MGet also count hits and misses.
+// The collectors are registered with reg, which may be shared by several wrapped cache managers.
+// A nil reg means prometheus.DefaultRegisterer.
+func NewMetricsCacher(cacher cachemar.Cacher, reg prometheus.Registerer, driverLabel string) cachemar.Cacher {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	return &metered{
+		wrapped: cacher,
+		driver:  driverLabel,
+		metrics: newCollectors(reg),
+	}
+}
+
+// observe must be deferred by every method with the start time of the operation.
+func (m *metered) observe(op string, start time.Time, err *error) {
+	status := StatusOK
+	if *err != nil && !cachemar.IsNotFound(*err) {
+		status = StatusError
+	}
+
+	m.metrics.operations.WithLabelValues(m.driver, op, status).Inc()
+	m.metrics.duration.WithLabelValues(m.driver, op).Observe(time.Since(start).Seconds())
+}
+
+// observeRead observes the operation like observe, and counts a hit or a miss.
+func (m *metered) observeRead(op string, start time.Time, err *error) {
+	m.observe(op, start, err)
+
+	switch {
+	case *err == nil:
+		m.metrics.hits.WithLabelValues(m.driver).Inc()
+	case cachemar.IsNotFound(*err):
+		m.metrics.misses.WithLabelValues(m.driver).Inc()
+	}
+}
+
+func (m *metered) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (err error) {
+	defer m.observe("Set", time.Now(), &err)
+	return m.wrapped.Set(ctx, key, value, ttl, tags)
+}
+
+func (m *metered) SetOpts(ctx context.Context, key string, value interface{}, opts cachemar.SetOptions) (err error) {
+	defer m.observe("SetOpts", time.Now(), &err)
+	return m.wrapped.SetOpts(ctx, key, value, opts)
+}
+
+func (m *metered) MSet(ctx context.Context, items []cachemar.CacheItem) (err error) {
+	defer m.observe("MSet", time.Now(), &err)
+	return m.wrapped.MSet(ctx, items)
+}
+
+func (m *metered) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (written bool, err error) {
+	defer m.observe("SetIfAbsent", time.Now(), &err)
+	return m.wrapped.SetIfAbsent(ctx, key, value, ttl, tags)
+}
+
+func (m *metered) SetIfPresent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (written bool, err error) {
+	defer m.observe("SetIfPresent", time.Now(), &err)
+	return m.wrapped.SetIfPresent(ctx, key, value, ttl, tags)
+}
+
+func (m *metered) Get(ctx context.Context, key string, value interface{}) (err error) {
+	defer m.observeRead("Get", time.Now(), &err)
+	return m.wrapped.Get(ctx, key, value)
+}
+
+func (m *metered) GetWithTTL(ctx context.Context, key string, value interface{}) (ttl time.Duration, err error) {
+	defer m.observeRead("GetWithTTL", time.Now(), &err)
+	return m.wrapped.GetWithTTL(ctx, key, value)
+}
+
+// MGet counts a hit or a miss for each key.
+func (m *metered) MGet(ctx context.Context, keys []string, values []interface{}) (err error) {
+	defer m.observe("MGet", time.Now(), &err)
+
+	err = m.wrapped.MGet(ctx, keys, values)
+
+	var errs cachemar.MultiError
+	switch {
+	case err == nil:
+		m.metrics.hits.WithLabelValues(m.driver).Add(float64(len(keys)))
+	case errors.As(err, &errs):
+		for _, itemErr := range errs {
+			if itemErr == nil {
+				m.metrics.hits.WithLabelValues(m.driver).Inc()
+			} else if cachemar.IsNotFound(itemErr) {
+				m.metrics.misses.WithLabelValues(m.driver).Inc()
+			}
+		}
+	}
+
+	return err
+}
+
+func (m *metered) GetAndDelete(ctx context.Context, key string, value interface{}) (err error) {
+	defer m.observeRead("GetAndDelete", time.Now(), &err)
+	return m.wrapped.GetAndDelete(ctx, key, value)
+}
+
+func (m *metered) Touch(ctx context.Context, key string, ttl time.Duration) (err error) {
+	defer m.observe("Touch", time.Now(), &err)
+	return m.wrapped.Touch(ctx, key, ttl)
+}
+
+func (m *metered) Remove(ctx context.Context, key string) (err error) {
+	defer m.observe("Remove", time.Now(), &err)
+	return m.wrapped.Remove(ctx, key)
+}
+
+func (m *metered) RemoveByTag(ctx context.Context, tag string) (err error) {
+	defer m.observe("RemoveByTag", time.Now(), &err)
+	return m.wrapped.RemoveByTag(ctx, tag)
+}
+
+func (m *metered) RemoveByTags(ctx context.Context, tags []string) (err error) {
+	defer m.observe("RemoveByTags", time.Now(), &err)
+	return m.wrapped.RemoveByTags(ctx, tags)
+}
+
+func (m *metered) Flush(ctx context.Context) (err error) {
+	defer m.observe("Flush", time.Now(), &err)
+	return m.wrapped.Flush(ctx)
+}
+
+func (m *metered) Exists(ctx context.Context, key string) (exists bool, err error) {
+	defer m.observe("Exists", time.Now(), &err)
+	return m.wrapped.Exists(ctx, key)
+}
+
+func (m *metered) Increment(ctx context.Context, key string) (err error) {
+	defer m.observe("Increment", time.Now(), &err)
+	return m.wrapped.Increment(ctx, key)
+}
+
+func (m *metered) Decrement(ctx context.Context, key string) (err error) {
+	defer m.observe("Decrement", time.Now(), &err)
+	return m.wrapped.Decrement(ctx, key)
+}
+
+func (m *metered) IncrBy(ctx context.Context, key string, delta int64) (value int64, err error) {
+	defer m.observe("IncrBy", time.Now(), &err)
+	return m.wrapped.IncrBy(ctx, key, delta)
+}
+
+func (m *metered) DecrBy(ctx context.Context, key string, delta int64) (value int64, err error) {
+	defer m.observe("DecrBy", time.Now(), &err)
+	return m.wrapped.DecrBy(ctx, key, delta)
+}
+
+func (m *metered) IncrementFloat(ctx context.Context, key string, delta float64) (value float64, err error) {
+	defer m.observe("IncrementFloat", time.Now(), &err)
+	return m.wrapped.IncrementFloat(ctx, key, delta)
+}
+
+func (m *metered) DecrementFloat(ctx context.Context, key string, delta float64) (value float64, err error) {
+	defer m.observe("DecrementFloat", time.Now(), &err)
+	return m.wrapped.DecrementFloat(ctx, key, delta)
+}
+
+func (m *metered) GetKeysByTag(ctx context.Context, tag string) (keys []string, err error) {
+	defer m.observe("GetKeysByTag", time.Now(), &err)
+	return m.wrapped.GetKeysByTag(ctx, tag)
+}
+
+func (m *metered) Ping() (err error) {
+	defer m.observe("Ping", time.Now(), &err)
+	return m.wrapped.Ping()
+}
+
+func (m *metered) Close() error {
+	return m.wrapped.Close()
+}
+
+// Unwrap returns the measured cache manager, so the manager still finds its optional interfaces. Their
+// operations are not measured.
+func (m *metered) Unwrap() cachemar.Cacher {
+	return m.wrapped
+}
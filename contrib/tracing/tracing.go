@@ -0,0 +1,282 @@
+// Package tracing provides a Cacher that creates an OpenTelemetry span for every operation of the wrapped cache manager.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+const tracerName = "github.com/stremovskyy/cachemar/contrib/tracing"
+
+// Span attributes set by the traced cache manager.
+const (
+	AttributeKey    = attribute.Key("cache.key")
+	AttributeDriver = attribute.Key("cache.driver")
+	AttributeHit    = attribute.Key("cache.hit")
+	AttributeTTL    = attribute.Key("cache.ttl")
+	AttributeKeys   = attribute.Key("cache.keys")
+	AttributeHits   = attribute.Key("cache.hits")
+	AttributeTag    = attribute.Key("cache.tag")
+)
+
+type traced struct {
+	wrapped cachemar.Cacher
+	tracer  trace.Tracer
+	driver  string
+}
+
+// NewTracedCacher wraps the cache manager so that every operation runs in a span named "cachemar.<Operation>".
+// Reads record whether they hit; a miss is not recorded as an error. A nil tracer means the global tracer provider.
+// Use it with Manager.WrapAll to trace all registered cache managers:
+//
+//	manager.WrapAll(func(c cachemar.Cacher) cachemar.Cacher { return tracing.NewTracedCacher(c, tracer) })
+func NewTracedCacher(cacher cachemar.Cacher, tracer trace.Tracer) cachemar.Cacher {
+	if tracer == nil {
+		tracer = otel.Tracer(tracerName)
+	}
+
+	driver := "unknown"
+	if named, ok := cachemar.As[cachemar.Named](cacher); ok {
+		driver = named.Name()
+	}
+
+	return &traced{
+		wrapped: cacher,
+		tracer:  tracer,
+		driver:  driver,
+	}
+}
+
+func (t *traced) start(ctx context.Context, op string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, AttributeDriver.String(t.driver))
+	return t.tracer.Start(ctx, "cachemar."+op, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// end records the error, if any, and ends the span.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// endRead records whether the read hit, and any error other than a miss, and ends the span.
+func endRead(span trace.Span, err error) {
+	span.SetAttributes(AttributeHit.Bool(err == nil))
+	if cachemar.IsNotFound(err) {
+		err = nil
+	}
+	end(span, err)
+}
+
+func ttlAttribute(ttl time.Duration) attribute.KeyValue {
+	return AttributeTTL.String(ttl.String())
+}
+
+func (t *traced) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
+	ctx, span := t.start(ctx, "Set", AttributeKey.String(key), ttlAttribute(ttl))
+	err := t.wrapped.Set(ctx, key, value, ttl, tags)
+	end(span, err)
+	return err
+}
+
+func (t *traced) SetOpts(ctx context.Context, key string, value interface{}, opts cachemar.SetOptions) error {
+	ctx, span := t.start(ctx, "SetOpts", AttributeKey.String(key), ttlAttribute(opts.TTL))
+	err := t.wrapped.SetOpts(ctx, key, value, opts)
+	end(span, err)
+	return err
+}
+
+func (t *traced) MSet(ctx context.Context, items []cachemar.CacheItem) error {
+	ctx, span := t.start(ctx, "MSet", AttributeKeys.Int(len(items)))
+	err := t.wrapped.MSet(ctx, items)
+	end(span, err)
+	return err
+}
+
+func (t *traced) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	ctx, span := t.start(ctx, "SetIfAbsent", AttributeKey.String(key), ttlAttribute(ttl))
+	written, err := t.wrapped.SetIfAbsent(ctx, key, value, ttl, tags)
+	end(span, err)
+	return written, err
+}
+
+func (t *traced) SetIfPresent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	ctx, span := t.start(ctx, "SetIfPresent", AttributeKey.String(key), ttlAttribute(ttl))
+	written, err := t.wrapped.SetIfPresent(ctx, key, value, ttl, tags)
+	end(span, err)
+	return written, err
+}
+
+func (t *traced) Get(ctx context.Context, key string, value interface{}) error {
+	ctx, span := t.start(ctx, "Get", AttributeKey.String(key))
+	err := t.wrapped.Get(ctx, key, value)
+	endRead(span, err)
+	return err
+}
+
+func (t *traced) GetWithTTL(ctx context.Context, key string, value interface{}) (time.Duration, error) {
+	ctx, span := t.start(ctx, "GetWithTTL", AttributeKey.String(key))
+	ttl, err := t.wrapped.GetWithTTL(ctx, key, value)
+	if err == nil {
+		span.SetAttributes(ttlAttribute(ttl))
+	}
+	endRead(span, err)
+	return ttl, err
+}
+
+// MGet records the number of keys and hits. Misses are not recorded as errors.
+func (t *traced) MGet(ctx context.Context, keys []string, values []interface{}) error {
+	ctx, span := t.start(ctx, "MGet", AttributeKeys.Int(len(keys)))
+	err := t.wrapped.MGet(ctx, keys, values)
+
+	hits, failed := len(keys), err != nil
+	var errs cachemar.MultiError
+	if errors.As(err, &errs) {
+		hits, failed = 0, false
+		for _, itemErr := range errs {
+			switch {
+			case itemErr == nil:
+				hits++
+			case !cachemar.IsNotFound(itemErr):
+				failed = true
+			}
+		}
+	} else if err != nil {
+		hits = 0
+	}
+	span.SetAttributes(AttributeHits.Int(hits))
+
+	if failed {
+		end(span, err)
+	} else {
+		end(span, nil)
+	}
+	return err
+}
+
+func (t *traced) GetAndDelete(ctx context.Context, key string, value interface{}) error {
+	ctx, span := t.start(ctx, "GetAndDelete", AttributeKey.String(key))
+	err := t.wrapped.GetAndDelete(ctx, key, value)
+	endRead(span, err)
+	return err
+}
+
+func (t *traced) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	ctx, span := t.start(ctx, "Touch", AttributeKey.String(key), ttlAttribute(ttl))
+	err := t.wrapped.Touch(ctx, key, ttl)
+	endRead(span, err)
+	return err
+}
+
+func (t *traced) Remove(ctx context.Context, key string) error {
+	ctx, span := t.start(ctx, "Remove", AttributeKey.String(key))
+	err := t.wrapped.Remove(ctx, key)
+	end(span, err)
+	return err
+}
+
+func (t *traced) RemoveByTag(ctx context.Context, tag string) error {
+	ctx, span := t.start(ctx, "RemoveByTag", AttributeTag.String(tag))
+	err := t.wrapped.RemoveByTag(ctx, tag)
+	end(span, err)
+	return err
+}
+
+func (t *traced) RemoveByTags(ctx context.Context, tags []string) error {
+	ctx, span := t.start(ctx, "RemoveByTags", AttributeTag.StringSlice(tags))
+	err := t.wrapped.RemoveByTags(ctx, tags)
+	end(span, err)
+	return err
+}
+
+func (t *traced) Flush(ctx context.Context) error {
+	ctx, span := t.start(ctx, "Flush")
+	err := t.wrapped.Flush(ctx)
+	end(span, err)
+	return err
+}
+
+func (t *traced) Exists(ctx context.Context, key string) (bool, error) {
+	ctx, span := t.start(ctx, "Exists", AttributeKey.String(key))
+	exists, err := t.wrapped.Exists(ctx, key)
+	span.SetAttributes(AttributeHit.Bool(exists))
+	end(span, err)
+	return exists, err
+}
+
+func (t *traced) Increment(ctx context.Context, key string) error {
+	ctx, span := t.start(ctx, "Increment", AttributeKey.String(key))
+	err := t.wrapped.Increment(ctx, key)
+	end(span, err)
+	return err
+}
+
+func (t *traced) Decrement(ctx context.Context, key string) error {
+	ctx, span := t.start(ctx, "Decrement", AttributeKey.String(key))
+	err := t.wrapped.Decrement(ctx, key)
+	end(span, err)
+	return err
+}
+
+func (t *traced) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	ctx, span := t.start(ctx, "IncrBy", AttributeKey.String(key))
+	value, err := t.wrapped.IncrBy(ctx, key, delta)
+	end(span, err)
+	return value, err
+}
+
+func (t *traced) DecrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	ctx, span := t.start(ctx, "DecrBy", AttributeKey.String(key))
+	value, err := t.wrapped.DecrBy(ctx, key, delta)
+	end(span, err)
+	return value, err
+}
+
+func (t *traced) IncrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	ctx, span := t.start(ctx, "IncrementFloat", AttributeKey.String(key))
+	value, err := t.wrapped.IncrementFloat(ctx, key, delta)
+	end(span, err)
+	return value, err
+}
+
+func (t *traced) DecrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	ctx, span := t.start(ctx, "DecrementFloat", AttributeKey.String(key))
+	value, err := t.wrapped.DecrementFloat(ctx, key, delta)
+	end(span, err)
+	return value, err
+}
+
+func (t *traced) GetKeysByTag(ctx context.Context, tag string) ([]string, error) {
+	ctx, span := t.start(ctx, "GetKeysByTag", AttributeTag.String(tag))
+	keys, err := t.wrapped.GetKeysByTag(ctx, tag)
+	span.SetAttributes(AttributeKeys.Int(len(keys)))
+	end(span, err)
+	return keys, err
+}
+
+func (t *traced) Ping() error {
+	_, span := t.start(context.Background(), "Ping")
+	err := t.wrapped.Ping()
+	end(span, err)
+	return err
+}
+
+func (t *traced) Close() error {
+	return t.wrapped.Close()
+}
+
+// Unwrap returns the traced cache manager, so the manager still finds its optional interfaces. Their
+// operations are not traced.
+func (t *traced) Unwrap() cachemar.Cacher {
+	return t.wrapped
+}
@@ -3,7 +3,16 @@ package cachemar
 import "time"
 
 const (
-	DefaultCacheTime = time.Hour
+	DefaultCacheTime   = time.Hour
+	DefaultWarmWorkers = 10
+	// DefaultAsyncWorkers is the number of workers running the writes to async cache managers of a chain.
+	DefaultAsyncWorkers = 10
+	// DefaultPERBeta is the beta of GetPER. Higher values recompute values earlier before they expire.
+	DefaultPERBeta = 1.0
+	// DefaultCheckInterval is how often background checks, such as WatchKeyVersion, poll.
+	DefaultCheckInterval = time.Second
+	// DefaultMinLayerTTL is the TTL of chain layers whose TTL scale is zero.
+	DefaultMinLayerTTL = time.Second
 )
 
 type CacherName string
@@ -12,6 +21,8 @@ const (
 	MemoryCacherName    CacherName = "memory"
 	RedisCacherName     CacherName = "redis"
 	MemcachedCacherName CacherName = "memcached"
+	RistrettoCacherName CacherName = "ristretto"
+	BigCacheCacherName  CacherName = "bigcache"
 )
 
 func (c CacherName) String() string {
@@ -0,0 +1,589 @@
+// Package bigcache provides an in-memory cache manager backed by BigCache, which keeps the values in large
+// byte slices without pointers, so that millions of entries don't add to the pauses of the garbage collector.
+package bigcache
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/codecs"
+)
+
+// DefaultLifeWindow is the LifeWindow of DefaultOptions.
+const DefaultLifeWindow = cachemar.DefaultCacheTime
+
+// headerSize is the size of the header of a stored entry: the expiry as Unix nanoseconds, zero if the
+// entry does not expire, and the length of the comma-separated tags that follow it.
+const headerSize = 8 + 2
+
+// errTooManyTags is returned when the tags of an entry don't fit its header.
+var errTooManyTags = errors.New("bigcache: tags too long")
+
+type bigCache struct {
+	cache *bigcache.BigCache
+	// tags maps each tag to the comma-separated keys associated with it.
+	tags  *bigcache.BigCache
+	codec cachemar.Codec
+
+	// mu serializes the conditional writes, the counters and the updates of the tag lists, which all read
+	// before they write.
+	mu sync.Mutex
+}
+
+type Options struct {
+	// Config configures BigCache. Its LifeWindow bounds the time-to-live of all entries, whatever the ttl
+	// they are set with, and its OnRemove callbacks receive the entries with the header of the driver.
+	Config bigcache.Config
+
+	// Codec serializes the stored values. Defaults to codecs.JSONCodec.
+	Codec cachemar.Codec
+}
+
+// DefaultOptions returns the default BigCache configuration with DefaultLifeWindow and without logging
+// of the allocations.
+func DefaultOptions() *Options {
+	config := bigcache.DefaultConfig(DefaultLifeWindow)
+	config.Verbose = false
+
+	return &Options{Config: config}
+}
+
+// Validate checks that the number of shards is a power of two, as BigCache requires.
+func (o *Options) Validate() error {
+	if o == nil {
+		return errors.New("bigcache: options are nil")
+	}
+	if o.Config.Shards <= 0 || o.Config.Shards&(o.Config.Shards-1) != 0 {
+		return errors.New("bigcache: the number of shards must be a power of two")
+	}
+
+	return nil
+}
+
+// New creates a BigCache cache manager. It panics if the options are invalid.
+func New(options *Options) cachemar.Cacher {
+	if err := options.Validate(); err != nil {
+		panic(err)
+	}
+
+	codec := options.Codec
+	if codec == nil {
+		codec = codecs.JSONCodec{}
+	}
+
+	cache, err := bigcache.New(context.Background(), options.Config)
+	if err != nil {
+		panic(fmt.Errorf("bigcache: %w", err))
+	}
+
+	// The tag lists are rewritten whenever a key is tagged, so they live as long as their newest key.
+	tagConfig := options.Config
+	tagConfig.OnRemove = nil
+	tagConfig.OnRemoveWithMetadata = nil
+	tagConfig.OnRemoveWithReason = nil
+	tags, err := bigcache.New(context.Background(), tagConfig)
+	if err != nil {
+		panic(fmt.Errorf("bigcache: %w", err))
+	}
+
+	return &bigCache{cache: cache, tags: tags, codec: codec}
+}
+
+// entry is a stored value with its header decoded.
+type entry struct {
+	expiry time.Time
+	tags   []string
+	data   []byte
+}
+
+func (e entry) expired(now time.Time) bool {
+	return !e.expiry.IsZero() && !now.Before(e.expiry)
+}
+
+func (e entry) hasTag(tag string) bool {
+	for _, t := range e.tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+func encodeEntry(ttl time.Duration, tags []string, data []byte) ([]byte, error) {
+	csv := strings.Join(uniqueTags(tags), ",")
+	if len(csv) > 1<<16-1 {
+		return nil, errTooManyTags
+	}
+
+	var expiry int64
+	if ttl > 0 {
+		expiry = time.Now().Add(ttl).UnixNano()
+	}
+
+	b := make([]byte, headerSize+len(csv)+len(data))
+	binary.BigEndian.PutUint64(b, uint64(expiry))
+	binary.BigEndian.PutUint16(b[8:], uint16(len(csv)))
+	copy(b[headerSize:], csv)
+	copy(b[headerSize+len(csv):], data)
+
+	return b, nil
+}
+
+func decodeEntry(b []byte) (entry, bool) {
+	if len(b) < headerSize {
+		return entry{}, false
+	}
+
+	var e entry
+	if expiry := int64(binary.BigEndian.Uint64(b)); expiry != 0 {
+		e.expiry = time.Unix(0, expiry)
+	}
+
+	n := int(binary.BigEndian.Uint16(b[8:]))
+	if len(b) < headerSize+n {
+		return entry{}, false
+	}
+	if n > 0 {
+		e.tags = strings.Split(string(b[headerSize:headerSize+n]), ",")
+	}
+	e.data = b[headerSize+n:]
+
+	return e, true
+}
+
+func uniqueTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]struct{}, len(tags))
+	unique := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if _, ok := seen[tag]; !ok {
+			seen[tag] = struct{}{}
+			unique = append(unique, tag)
+		}
+	}
+
+	return unique
+}
+
+func (d *bigCache) Name() string {
+	return cachemar.BigCacheCacherName.String()
+}
+
+// lookup returns the entry stored under key, if it exists and has not expired.
+func (d *bigCache) lookup(key string) (entry, bool) {
+	b, err := d.cache.Get(key)
+	if err != nil {
+		return entry{}, false
+	}
+
+	e, ok := decodeEntry(b)
+	if !ok || e.expired(time.Now()) {
+		return entry{}, false
+	}
+
+	return e, true
+}
+
+// store stores the entry and adds the key to the lists of its tags. Tagged writes hold mu from the write
+// of the entry on, unless the caller holds it already, so RemoveByTags can't miss them; the others don't
+// lock. As the lists are comma-separated, tagged keys and tags must not contain commas.
+func (d *bigCache) store(key string, data []byte, ttl time.Duration, tags []string, locked bool) error {
+	if len(tags) > 0 && (strings.Contains(key, ",") || strings.Contains(strings.Join(tags, ""), ",")) {
+		return wrapError("Set", key, cachemar.ErrInvalidKey)
+	}
+
+	b, err := encodeEntry(ttl, tags, data)
+	if err != nil {
+		return wrapError("Set", key, err)
+	}
+
+	if len(tags) > 0 && !locked {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+	}
+	if err := d.cache.Set(key, b); err != nil {
+		return wrapError("Set", key, err)
+	}
+
+	for _, tag := range uniqueTags(tags) {
+		if err := d.addToTag(tag, key); err != nil {
+			return wrapError("AddTags", key, err)
+		}
+	}
+
+	return nil
+}
+
+// addToTag adds the key to the list of the tag. The list is written even if it has the key already, so
+// it lives in BigCache at least as long as the entries of the tag. The caller must hold mu.
+func (d *bigCache) addToTag(tag, key string) error {
+	keys := d.tagKeys(tag)
+	listed := false
+	for _, k := range keys {
+		if k == key {
+			listed = true
+			break
+		}
+	}
+	if !listed {
+		keys = append(keys, key)
+	}
+
+	return d.tags.Set(tag, []byte(strings.Join(keys, ",")))
+}
+
+// tagKeys returns the keys listed under the tag. The list is not cleaned up when keys are removed or
+// set without the tag, so callers check the tags of each key.
+func (d *bigCache) tagKeys(tag string) []string {
+	b, err := d.tags.Get(tag)
+	if err != nil || len(b) == 0 {
+		return nil
+	}
+
+	return strings.Split(string(b), ",")
+}
+
+func (d *bigCache) encode(key string, value interface{}) ([]byte, error) {
+	data, err := d.codec.Marshal(value)
+	if err != nil {
+		return nil, wrapError("Serialize", key, err)
+	}
+
+	return data, nil
+}
+
+func (d *bigCache) decode(key string, e entry, value interface{}) error {
+	if err := d.codec.Unmarshal(e.data, value); err != nil {
+		return wrapError(cachemar.OperationDeserialize, key, err)
+	}
+
+	return nil
+}
+
+func (d *bigCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
+	return d.SetOpts(ctx, key, value, cachemar.SetOptions{TTL: ttl, Tags: tags})
+}
+
+func (d *bigCache) MSet(ctx context.Context, items []cachemar.CacheItem) error {
+	errs := make(cachemar.MultiError, len(items))
+	for i, item := range items {
+		errs[i] = d.Set(ctx, item.Key, item.Value, item.TTL, item.Tags)
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// SetOpts stores the value as described by the options.
+func (d *bigCache) SetOpts(ctx context.Context, key string, value interface{}, opts cachemar.SetOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	if opts.Conditional() {
+		written, err := d.setIf(ctx, key, value, opts.TTL, opts.Tags, opts.IfPresent)
+		if err == nil && !written {
+			return cachemar.ErrNotStored
+		}
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := d.encode(key, value)
+	if err != nil {
+		return err
+	}
+
+	return d.store(key, data, opts.TTL, opts.Tags, false)
+}
+
+func (d *bigCache) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	return d.setIf(ctx, key, value, ttl, tags, false)
+}
+
+func (d *bigCache) SetIfPresent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	return d.setIf(ctx, key, value, ttl, tags, true)
+}
+
+// setIf stores the value if the presence of the key matches present. The check is atomic with respect
+// to the other conditional writes and the counters, not to plain writes.
+func (d *bigCache) setIf(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string, present bool) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	data, err := d.encode(key, value)
+	if err != nil {
+		return false, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, exists := d.lookup(key); exists != present {
+		return false, nil
+	}
+
+	return true, d.store(key, data, ttl, tags, true)
+}
+
+func (d *bigCache) Get(ctx context.Context, key string, value interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	e, ok := d.lookup(key)
+	if !ok {
+		return wrapError("Get", key, cachemar.ErrNotFound)
+	}
+
+	return d.decode(key, e, value)
+}
+
+// GetWithTTL retrieves the value together with the remaining time-to-live it was set with. The entry may
+// be evicted earlier, once it is older than the LifeWindow.
+func (d *bigCache) GetWithTTL(ctx context.Context, key string, value interface{}) (time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	e, ok := d.lookup(key)
+	if !ok {
+		return 0, wrapError("GetWithTTL", key, cachemar.ErrNotFound)
+	}
+	if err := d.decode(key, e, value); err != nil {
+		return 0, err
+	}
+
+	if e.expiry.IsZero() {
+		return cachemar.NoExpiry, nil
+	}
+	if remaining := time.Until(e.expiry); remaining > 0 {
+		return remaining, nil
+	}
+
+	return 0, nil
+}
+
+func (d *bigCache) MGet(ctx context.Context, keys []string, values []interface{}) error {
+	if len(keys) != len(values) {
+		return fmt.Errorf("bigcache: got %d keys and %d values", len(keys), len(values))
+	}
+
+	errs := make(cachemar.MultiError, len(keys))
+	for i, key := range keys {
+		errs[i] = d.Get(ctx, key, values[i])
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// GetAndDelete retrieves the value and removes it under mu, so concurrent callers can't both get it.
+func (d *bigCache) GetAndDelete(ctx context.Context, key string, value interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	e, ok := d.lookup(key)
+	if !ok {
+		return wrapError("GetAndDelete", key, cachemar.ErrNotFound)
+	}
+	_ = d.cache.Delete(key)
+
+	return d.decode(key, e, value)
+}
+
+// Touch stores the entry again with the new ttl. The value is not decoded.
+func (d *bigCache) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	e, ok := d.lookup(key)
+	if !ok {
+		return wrapError("Touch", key, cachemar.ErrNotFound)
+	}
+
+	return d.store(key, e.data, ttl, e.tags, true)
+}
+
+func (d *bigCache) Remove(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := d.cache.Delete(key); err != nil && !errors.Is(err, bigcache.ErrEntryNotFound) {
+		return wrapError("Remove", key, err)
+	}
+
+	return nil
+}
+
+func (d *bigCache) RemoveByTag(ctx context.Context, tag string) error {
+	return d.RemoveByTags(ctx, []string{tag})
+}
+
+// RemoveByTags removes the keys still tagged with any of the tags, and the lists of the tags.
+func (d *bigCache) RemoveByTags(ctx context.Context, tags []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, tag := range tags {
+		for _, key := range d.tagKeys(tag) {
+			if e, ok := d.lookup(key); ok && e.hasTag(tag) {
+				_ = d.cache.Delete(key)
+			}
+		}
+		_ = d.tags.Delete(tag)
+	}
+
+	return nil
+}
+
+func (d *bigCache) Exists(ctx context.Context, key string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	_, ok := d.lookup(key)
+	return ok, nil
+}
+
+func (d *bigCache) Increment(ctx context.Context, key string) error {
+	_, err := d.IncrBy(ctx, key, 1)
+	return err
+}
+
+func (d *bigCache) Decrement(ctx context.Context, key string) error {
+	_, err := d.IncrBy(ctx, key, -1)
+	return err
+}
+
+// IncrBy adds delta to the int64 value of the key. A missing key starts at zero
+// and is stored for cachemar.DefaultCacheTime.
+func (d *bigCache) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	var value int64
+	err := d.update(ctx, "IncrBy", key, &value, func() { value += delta })
+	return value, err
+}
+
+func (d *bigCache) DecrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	return d.IncrBy(ctx, key, -delta)
+}
+
+// IncrementFloat adds delta to the float64 value of the key. A missing key starts at zero
+// and is stored for cachemar.DefaultCacheTime.
+func (d *bigCache) IncrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	var value float64
+	err := d.update(ctx, "IncrementFloat", key, &value, func() { value += delta })
+	return value, err
+}
+
+func (d *bigCache) DecrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	return d.IncrementFloat(ctx, key, -delta)
+}
+
+// update decodes the value of the key into value, applies fn and stores the result, keeping the expiry
+// and the tags of the key. It returns ErrInvalidType if the value can't be decoded.
+func (d *bigCache) update(ctx context.Context, operation, key string, value interface{}, fn func()) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ttl, tags := cachemar.DefaultCacheTime, []string(nil)
+	if e, ok := d.lookup(key); ok {
+		if err := d.codec.Unmarshal(e.data, value); err != nil {
+			return wrapError(operation, key, cachemar.ErrInvalidType)
+		}
+		ttl, tags = 0, e.tags
+		if !e.expiry.IsZero() {
+			// A zero ttl would not expire, so an entry expiring meanwhile keeps the shortest one.
+			if ttl = time.Until(e.expiry); ttl <= 0 {
+				ttl = time.Nanosecond
+			}
+		}
+	}
+
+	fn()
+
+	data, err := d.encode(key, value)
+	if err != nil {
+		return err
+	}
+
+	return d.store(key, data, ttl, tags, true)
+}
+
+// GetKeysByTag returns the keys that exist and are still tagged with the tag.
+func (d *bigCache) GetKeysByTag(ctx context.Context, tag string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, key := range d.tagKeys(tag) {
+		if e, ok := d.lookup(key); ok && e.hasTag(tag) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+// Flush removes all entries and tag lists.
+func (d *bigCache) Flush(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.cache.Reset(); err != nil {
+		return wrapError("Flush", "", err)
+	}
+	if err := d.tags.Reset(); err != nil {
+		return wrapError("Flush", "", err)
+	}
+
+	return nil
+}
+
+func (d *bigCache) Ping() error {
+	return nil
+}
+
+// Close stops the cleanup goroutines of BigCache.
+func (d *bigCache) Close() error {
+	return errors.Join(d.cache.Close(), d.tags.Close())
+}
+
+// wrapError attaches the driver, operation and key to err.
+func wrapError(operation, key string, err error) error {
+	return &cachemar.DriverError{Driver: cachemar.BigCacheCacherName.String(), Operation: operation, Key: key, Cause: err}
+}
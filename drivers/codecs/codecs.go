@@ -0,0 +1,54 @@
+// Package codecs provides cachemar.Codec implementations shared by the drivers.
+package codecs
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// GobCodec encodes values with encoding/gob. Interface values require their types to be registered with gob.
+type GobCodec struct{}
+
+func (GobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (GobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// JSONCodec encodes values with encoding/json. It is the default codec of the Redis and Memcached drivers.
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// MsgpackCodec encodes values with MessagePack, which is cheaper than JSON for structs with many
+// fields and for binary data. See BenchmarkCodecs.
+type MsgpackCodec struct{}
+
+// NewMsgpackCodec returns a MsgpackCodec, e.g. for redis.Options.WithCodec.
+func NewMsgpackCodec() MsgpackCodec {
+	return MsgpackCodec{}
+}
+
+func (MsgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+func (MsgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	return msgpack.Unmarshal(data, v)
+}
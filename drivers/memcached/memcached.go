@@ -1,94 +1,583 @@
 package memcached
 
 import (
+	"bufio"
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/bradfitz/gomemcache/memcache"
 
 	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/codecs"
+	"github.com/stremovskyy/cachemar/internal/compress"
+	"github.com/stremovskyy/cachemar/internal/keybuf"
 )
 
 type memcached struct {
-	client *memcache.Client
-	prefix string
+	client  *memcache.Client
+	prefix  string
+	servers []string
+	codec   cachemar.Codec
+	dial    func(ctx context.Context, network, address string) (net.Conn, error)
+
+	compress          bool
+	algo              compress.Algo
+	compressThreshold int
+
+	getTimeout    time.Duration
+	setTimeout    time.Duration
+	deleteTimeout time.Duration
 }
 
 type Options struct {
 	Servers []string
 	Prefix  string
+
+	// Codec serializes the stored values. Defaults to codecs.JSONCodec. Counters changed with Increment
+	// and Decrement are stored as plain numbers, which only JSON decodes. Tag lists always use JSON.
+	Codec cachemar.Codec
+
+	// CompressionEnabled compresses the serialized values. Reads detect compressed values from their
+	// magic bytes, so entries stored before compression was enabled stay readable.
+	CompressionEnabled bool
+	// CompressionAlgo is "gzip", "zstd" or "lz4". Defaults to "gzip".
+	CompressionAlgo string
+	// CompressionThreshold is the size in bytes below which values are stored uncompressed, as compressing
+	// tiny values costs more than it saves.
+	CompressionThreshold int
+
+	// TLSConfig enables TLS for the connections when set, for servers started with --enable-ssl.
+	// gomemcache supports it natively through Client.DialContext, so no fork is needed.
+	TLSConfig *tls.Config
+
+	// GetTimeout bounds read operations. Zero means the context is not checked.
+	GetTimeout time.Duration
+	// SetTimeout bounds write operations. Zero means the context is not checked.
+	SetTimeout time.Duration
+	// DeleteTimeout bounds remove operations. Zero means the context is not checked.
+	DeleteTimeout time.Duration
+}
+
+// WithGetTimeout sets GetTimeout and returns the options for chaining.
+func (o *Options) WithGetTimeout(d time.Duration) *Options {
+	o.GetTimeout = d
+	return o
+}
+
+// WithSetTimeout sets SetTimeout and returns the options for chaining.
+func (o *Options) WithSetTimeout(d time.Duration) *Options {
+	o.SetTimeout = d
+	return o
+}
+
+// WithDeleteTimeout sets DeleteTimeout and returns the options for chaining.
+func (o *Options) WithDeleteTimeout(d time.Duration) *Options {
+	o.DeleteTimeout = d
+	return o
+}
+
+// WithCodec sets Codec and returns the options for chaining.
+func (o *Options) WithCodec(c cachemar.Codec) *Options {
+	o.Codec = c
+	return o
+}
+
+// Validate checks that the options describe a usable Memcached connection.
+func (o *Options) Validate() error {
+	if o == nil {
+		return errors.New("memcached: options are nil")
+	}
+	if len(o.Servers) == 0 {
+		return errors.New("memcached: at least one server is required")
+	}
+	for _, server := range o.Servers {
+		if server == "" {
+			return errors.New("memcached: server address must not be empty")
+		}
+	}
+	if o.GetTimeout < 0 || o.SetTimeout < 0 || o.DeleteTimeout < 0 {
+		return errors.New("memcached: timeouts must not be negative")
+	}
+	if _, err := compressionAlgo(o.CompressionAlgo); err != nil {
+		return err
+	}
+	if o.CompressionThreshold < 0 {
+		return errors.New("memcached: compression threshold must not be negative")
+	}
+
+	return nil
+}
+
+// compressionAlgo returns the algorithm named by CompressionAlgo.
+func compressionAlgo(name string) (compress.Algo, error) {
+	switch name {
+	case "", "gzip":
+		return compress.Gzip, nil
+	case "zstd":
+		return compress.Zstd, nil
+	case "lz4":
+		return compress.LZ4, nil
+	default:
+		return 0, fmt.Errorf("memcached: invalid compression algorithm %q", name)
+	}
 }
 
 func New(options *Options) cachemar.Cacher {
 	client := memcache.New(options.Servers...)
 
+	dial := (&net.Dialer{}).DialContext
+	if options.TLSConfig != nil {
+		dial = (&tls.Dialer{Config: options.TLSConfig}).DialContext
+		client.DialContext = dial
+	}
+
+	codec := options.Codec
+	if codec == nil {
+		codec = codecs.JSONCodec{}
+	}
+
+	// An unknown algorithm falls back to gzip, Validate reports it.
+	algo, _ := compressionAlgo(options.CompressionAlgo)
+
 	return &memcached{
-		client: client,
-		prefix: options.Prefix,
+		client:  client,
+		prefix:  options.Prefix,
+		servers: options.Servers,
+		codec:   codec,
+		dial:    dial,
+
+		compress:          options.CompressionEnabled,
+		algo:              algo,
+		compressThreshold: options.CompressionThreshold,
+
+		getTimeout:    options.GetTimeout,
+		setTimeout:    options.SetTimeout,
+		deleteTimeout: options.DeleteTimeout,
 	}
 }
 
+// run runs op bounded by the context and the timeout, unless the timeout is zero. The Memcached client
+// does not support contexts, so an abandoned op finishes in the background within the client's own timeout.
+func run(ctx context.Context, timeout time.Duration, op func() error) error {
+	_, err := runResult(ctx, timeout, func() (struct{}, error) { return struct{}{}, op() })
+	return err
+}
+
+// runResult is run for ops returning a value. The value is handed over through a channel, so an abandoned
+// op never writes to the caller's variables.
+func runResult[T any](ctx context.Context, timeout time.Duration, op func() (T, error)) (T, error) {
+	if timeout <= 0 {
+		return op()
+	}
+
+	if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > timeout {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	type result struct {
+		value T
+		err   error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		value, err := op()
+		done <- result{value: value, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.value, res.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// getItem fetches the item bounded by the get timeout.
+func (d *memcached) getItem(ctx context.Context, finalKey string) (*memcache.Item, error) {
+	return runResult(ctx, d.getTimeout, func() (*memcache.Item, error) {
+		return d.client.Get(finalKey)
+	})
+}
+
+func (d *memcached) Name() string {
+	return cachemar.MemcachedCacherName.String()
+}
+
 func (d *memcached) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
-	data, err := json.Marshal(value)
+	return d.SetOpts(ctx, key, value, cachemar.SetOptions{TTL: ttl, Tags: tags})
+}
+
+// MSet stores the items one by one, as Memcached has no multi-key set. The connections to the servers are
+// reused, so this mostly saves the per-call overhead.
+func (d *memcached) MSet(ctx context.Context, items []cachemar.CacheItem) error {
+	errs := make(cachemar.MultiError, len(items))
+	for i, item := range items {
+		if err := ctx.Err(); err != nil {
+			errs[i] = err
+			continue
+		}
+		errs[i] = d.Set(ctx, item.Key, item.Value, item.TTL, item.Tags)
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// SetOpts stores the value as described by the options, with the add or replace command if they are
+// conditional.
+func (d *memcached) SetOpts(ctx context.Context, key string, value interface{}, opts cachemar.SetOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	if opts.Conditional() {
+		var written bool
+		var err error
+		if opts.IfAbsent {
+			written, err = d.SetIfAbsent(ctx, key, value, opts.TTL, opts.Tags)
+		} else {
+			written, err = d.SetIfPresent(ctx, key, value, opts.TTL, opts.Tags)
+		}
+		if err == nil && !written {
+			return cachemar.ErrNotStored
+		}
+		return err
+	}
+
+	ttl, tags := opts.TTL, opts.Tags
+	item, err := d.newItem(key, value, ttl)
 	if err != nil {
-		return fmt.Errorf("failed to serialize value: %v", err)
+		return err
 	}
 
-	finalKey := d.keyWithPrefix(key)
-	item := &memcache.Item{
-		Key:        finalKey,
-		Value:      data,
-		Expiration: int32(ttl.Seconds()),
+	err = run(ctx, d.setTimeout, func() error { return d.client.Set(item) })
+	if err != nil {
+		return wrapError("Set", item.Key, err)
 	}
 
-	err = d.client.Set(item)
+	return d.addTags(key, tags)
+}
+
+// SetIfAbsent stores the value with the add command.
+func (d *memcached) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	return d.setIf(ctx, "SetIfAbsent", key, value, ttl, tags, d.client.Add)
+}
+
+// SetIfPresent stores the value with the replace command.
+func (d *memcached) SetIfPresent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	return d.setIf(ctx, "SetIfPresent", key, value, ttl, tags, d.client.Replace)
+}
+
+// setIf stores the value with a conditional command, which fails with memcache.ErrNotStored if the
+// condition does not hold.
+func (d *memcached) setIf(
+	ctx context.Context, operation, key string, value interface{}, ttl time.Duration, tags []string,
+	set func(item *memcache.Item) error,
+) (bool, error) {
+	item, err := d.newItem(key, value, ttl)
 	if err != nil {
-		return fmt.Errorf("failed to set key-value pair in Memcached: %v", err)
+		return false, err
 	}
 
-	if len(tags) > 0 {
-		for _, tag := range tags {
-			tagKey := d.getTagKey(tag)
-			tagValueItem, err := d.client.Get(tagKey)
-			if err != nil && err != memcache.ErrCacheMiss {
-				return err
-			}
-			tagValue := make([]string, 0)
-			if err != memcache.ErrCacheMiss {
-				if err := json.Unmarshal(tagValueItem.Value, &tagValue); err != nil {
-					return err
-				}
-			}
-			tagValue = append(tagValue, key)
-			tagValueBytes, err := json.Marshal(tagValue)
-			if err != nil {
+	err = run(ctx, d.setTimeout, func() error { return set(item) })
+	if err == memcache.ErrNotStored {
+		return false, nil
+	}
+	if err != nil {
+		return false, wrapError(operation, item.Key, err)
+	}
+
+	return true, d.addTags(key, tags)
+}
+
+// newItem serializes the value into an item stored under the prefixed key, compressing it if compression
+// is enabled and the value reaches the threshold.
+func (d *memcached) newItem(key string, value interface{}, ttl time.Duration) (*memcache.Item, error) {
+	data, err := d.codec.Marshal(value)
+	if err != nil {
+		return nil, wrapError("Serialize", key, err)
+	}
+
+	return d.newEncodedItem(key, data, ttl)
+}
+
+// newEncodedItem is newItem for a value already serialized by the codec.
+func (d *memcached) newEncodedItem(key string, data []byte, ttl time.Duration) (*memcache.Item, error) {
+	if d.compress && len(data) >= d.compressThreshold {
+		compressed, err := compress.Compress(d.algo, data)
+		if err != nil {
+			return nil, wrapError("Compress", key, err)
+		}
+		data = compressed
+	}
+
+	return &memcache.Item{
+		Key:        d.keyWithPrefix(key),
+		Value:      data,
+		Flags:      expiryFlags(ttl),
+		Expiration: int32(ttl.Seconds()),
+	}, nil
+}
+
+// addTags associates the key with the tags.
+func (d *memcached) addTags(key string, tags []string) error {
+	for _, tag := range tags {
+		tagKey := d.getTagKey(tag)
+		tagValueItem, err := d.client.Get(tagKey)
+		if err != nil && err != memcache.ErrCacheMiss {
+			return err
+		}
+		tagValue := make([]string, 0)
+		if err != memcache.ErrCacheMiss {
+			if err := json.Unmarshal(tagValueItem.Value, &tagValue); err != nil {
 				return err
 			}
-			d.client.Set(&memcache.Item{Key: tagKey, Value: tagValueBytes})
 		}
-
+		tagValue = append(tagValue, key)
+		tagValueBytes, err := json.Marshal(tagValue)
+		if err != nil {
+			return err
+		}
+		d.client.Set(&memcache.Item{Key: tagKey, Value: tagValueBytes})
 	}
 
 	return nil
 }
 
+// SetRaw stores the bytes without serializing them.
+func (d *memcached) SetRaw(ctx context.Context, key string, value []byte, ttl time.Duration, tags []string) error {
+	item := &memcache.Item{
+		Key:        d.keyWithPrefix(key),
+		Value:      cachemar.EncodeRaw(value),
+		Flags:      expiryFlags(ttl),
+		Expiration: int32(ttl.Seconds()),
+	}
+
+	err := run(ctx, d.setTimeout, func() error { return d.client.Set(item) })
+	if err != nil {
+		return wrapError("SetRaw", key, err)
+	}
+
+	return d.addTags(key, tags)
+}
+
+// GetRaw retrieves the bytes stored by SetRaw, and other values as JSON.
+func (d *memcached) GetRaw(ctx context.Context, key string) ([]byte, error) {
+	item, err := d.getItem(ctx, d.keyWithPrefix(key))
+	if err != nil {
+		if err == memcache.ErrCacheMiss {
+			return nil, cachemar.ErrNotFound
+		}
+		return nil, wrapError("GetRaw", key, err)
+	}
+
+	if raw, ok := cachemar.DecodeRaw(item.Value); ok {
+		return raw, nil
+	}
+
+	return decompressItem(key, item.Value)
+}
+
+// Format reports the codec, see cachemar.EncodedCacher.
+func (d *memcached) Format() string {
+	return cachemar.CodecFormat(d.codec)
+}
+
+// GetEncoded retrieves the value as serialized by the codec, decompressed. Values stored by SetRaw are
+// reported with cachemar.ErrRawValue.
+func (d *memcached) GetEncoded(ctx context.Context, key string) ([]byte, error) {
+	item, err := d.getItem(ctx, d.keyWithPrefix(key))
+	if err != nil {
+		if err == memcache.ErrCacheMiss {
+			return nil, cachemar.ErrNotFound
+		}
+		return nil, wrapError("GetEncoded", key, err)
+	}
+
+	if _, ok := cachemar.DecodeRaw(item.Value); ok {
+		return nil, cachemar.ErrRawValue
+	}
+
+	return decompressItem(key, item.Value)
+}
+
+// SetEncoded stores the data serialized by a codec of the same Format as if its value was given to Set.
+func (d *memcached) SetEncoded(ctx context.Context, key string, data []byte, ttl time.Duration, tags []string) error {
+	item, err := d.newEncodedItem(key, data, ttl)
+	if err != nil {
+		return err
+	}
+
+	err = run(ctx, d.setTimeout, func() error { return d.client.Set(item) })
+	if err != nil {
+		return wrapError("SetEncoded", item.Key, err)
+	}
+
+	return d.addTags(key, tags)
+}
+
 func (d *memcached) Get(ctx context.Context, key string, value interface{}) error {
+	_, err := d.get(ctx, "Get", key, value)
+	return err
+}
+
+// GetWithTTL retrieves the value together with its remaining time-to-live, to the second. Memcached doesn't
+// return the expiry of items, so it is read from the flags set by Set and SetRaw. Items stored otherwise
+// are reported as not expiring.
+func (d *memcached) GetWithTTL(ctx context.Context, key string, value interface{}) (time.Duration, error) {
+	item, err := d.get(ctx, "GetWithTTL", key, value)
+	if err != nil {
+		return 0, err
+	}
+	if item.Flags == 0 {
+		return cachemar.NoExpiry, nil
+	}
+
+	remaining := time.Until(time.Unix(int64(item.Flags), 0))
+	if remaining < 0 {
+		return 0, nil
+	}
+
+	return remaining, nil
+}
+
+// get fetches the item and decodes its value.
+func (d *memcached) get(ctx context.Context, operation, key string, value interface{}) (*memcache.Item, error) {
 	finalKey := d.keyWithPrefix(key)
 
-	item, err := d.client.Get(finalKey)
+	item, err := d.getItem(ctx, finalKey)
 	if err != nil {
 		if err == memcache.ErrCacheMiss {
-			return fmt.Errorf("key not found: %s", finalKey)
+			return nil, wrapError(operation, finalKey, cachemar.ErrNotFound)
 		}
-		return fmt.Errorf("failed to get value from Memcached: %v", err)
+		return nil, wrapError(operation, finalKey, err)
+	}
+
+	return item, d.decodeItem(finalKey, item, value)
+}
+
+// decodeItem decompresses and unmarshals the value of the item.
+func (d *memcached) decodeItem(finalKey string, item *memcache.Item, value interface{}) error {
+	if raw, ok := cachemar.DecodeRaw(item.Value); ok {
+		return cachemar.AssignRaw(raw, value)
 	}
 
-	err = json.Unmarshal(item.Value, value)
+	data, err := decompressItem(finalKey, item.Value)
 	if err != nil {
-		return fmt.Errorf("failed to deserialize value: %v", err)
+		return err
+	}
+
+	err = d.codec.Unmarshal(data, value)
+	if err != nil {
+		return wrapError(cachemar.OperationDeserialize, finalKey, err)
+	}
+
+	return nil
+}
+
+// decompressItem decompresses the data if it is compressed, whether or not compression is enabled.
+func decompressItem(finalKey string, data []byte) ([]byte, error) {
+	if _, compressed := compress.Detect(data); !compressed {
+		return data, nil
+	}
+
+	data, err := compress.Decompress(data)
+	if err != nil {
+		return nil, wrapError(cachemar.OperationDecompress, finalKey, err)
+	}
+
+	return data, nil
+}
+
+// expiryFlags returns the flags recording the expiry of an item set with ttl, as Unix seconds. Zero means
+// the item does not expire.
+func expiryFlags(ttl time.Duration) uint32 {
+	if ttl <= 0 {
+		return 0
+	}
+
+	return uint32(time.Now().Add(ttl).Unix())
+}
+
+// MGet retrieves the values with a single multi-key get.
+func (d *memcached) MGet(ctx context.Context, keys []string, values []interface{}) error {
+	if len(keys) != len(values) {
+		return fmt.Errorf("memcached: got %d keys and %d values", len(keys), len(values))
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	finalKeys := make([]string, len(keys))
+	for i, key := range keys {
+		finalKeys[i] = d.keyWithPrefix(key)
+	}
+
+	items, err := runResult(ctx, d.getTimeout, func() (map[string]*memcache.Item, error) {
+		return d.client.GetMulti(finalKeys)
+	})
+	if err != nil {
+		return wrapError("MGet", strings.Join(finalKeys, ","), err)
+	}
+
+	errs := make(cachemar.MultiError, len(keys))
+	for i, finalKey := range finalKeys {
+		item, ok := items[finalKey]
+		if !ok {
+			errs[i] = wrapError("MGet", finalKey, cachemar.ErrNotFound)
+			continue
+		}
+
+		errs[i] = d.decodeItem(finalKey, item, values[i])
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// GetAndDelete retrieves the value and then deletes it. This is not atomic, but if another caller deletes
+// the value in between, only that caller succeeds and ErrNotFound is returned.
+func (d *memcached) GetAndDelete(ctx context.Context, key string, value interface{}) error {
+	if _, err := d.get(ctx, "GetAndDelete", key, value); err != nil {
+		return err
+	}
+
+	finalKey := d.keyWithPrefix(key)
+	err := run(ctx, d.deleteTimeout, func() error { return d.client.Delete(finalKey) })
+	if err == memcache.ErrCacheMiss {
+		return wrapError("GetAndDelete", finalKey, cachemar.ErrNotFound)
+	}
+	if err != nil {
+		return wrapError("GetAndDelete", finalKey, err)
+	}
+
+	return nil
+}
+
+// Touch reads the item and stores it again with the new expiration, which also updates the expiry
+// GetWithTTL reports. The value is not decoded. If the item changes in between, the error is returned.
+func (d *memcached) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	finalKey := d.keyWithPrefix(key)
+
+	item, err := d.getItem(ctx, finalKey)
+	if err == nil {
+		item.Flags = expiryFlags(ttl)
+		item.Expiration = int32(ttl.Seconds())
+		err = run(ctx, d.setTimeout, func() error { return d.client.CompareAndSwap(item) })
+	}
+	if err == memcache.ErrCacheMiss {
+		return wrapError("Touch", finalKey, cachemar.ErrNotFound)
+	}
+	if err != nil {
+		return wrapError("Touch", finalKey, err)
 	}
 
 	return nil
@@ -97,27 +586,31 @@ func (d *memcached) Get(ctx context.Context, key string, value interface{}) erro
 func (d *memcached) Remove(ctx context.Context, key string) error {
 	finalKey := d.keyWithPrefix(key)
 
-	err := d.client.Delete(finalKey)
+	err := run(ctx, d.deleteTimeout, func() error { return d.client.Delete(finalKey) })
 	if err != nil {
-		return fmt.Errorf("failed to remove key from Memcached: %v", err)
+		return wrapError("Remove", finalKey, err)
 	}
 
 	return nil
 }
 
 func (d *memcached) RemoveByTag(ctx context.Context, tag string) error {
+	return run(ctx, d.deleteTimeout, func() error { return d.removeByTag(tag) })
+}
+
+func (d *memcached) removeByTag(tag string) error {
 	keyForTags := getTagKey(tag)
 
 	item, err := d.client.Get(keyForTags)
 	if err != nil {
-		return fmt.Errorf("failed to get keys associated with tag: %v", err)
+		return wrapError("RemoveByTag", tag, err)
 	}
 
 	keys := strings.Split(string(item.Value), ",")
 	for _, key := range keys {
 		err := d.client.Delete(key)
 		if err != nil {
-			return fmt.Errorf("failed to remove key from Memcached: %v", err)
+			return wrapError("RemoveByTag", tag, err)
 		}
 	}
 
@@ -128,7 +621,7 @@ func (d *memcached) RemoveByTags(ctx context.Context, tags []string) error {
 	for _, tag := range tags {
 		err := d.RemoveByTag(ctx, tag)
 		if err != nil {
-			return fmt.Errorf("failed to remove keys for tag: %v", err)
+			return err
 		}
 	}
 
@@ -136,21 +629,26 @@ func (d *memcached) RemoveByTags(ctx context.Context, tags []string) error {
 }
 
 func getTagKey(tag string) string {
-	return fmt.Sprintf("tag:%s", tag)
+	return keybuf.Join(":", "tag", tag)
+}
+
+// EffectiveKey returns the key as it is stored in Memcached.
+func (d *memcached) EffectiveKey(key string) string {
+	return d.keyWithPrefix(key)
 }
 
 func (d *memcached) keyWithPrefix(key string) string {
-	return fmt.Sprintf("%s:%s", d.prefix, key)
+	return keybuf.Join(":", d.prefix, key)
 }
 
 func (d *memcached) Exists(ctx context.Context, key string) (bool, error) {
 	finalKey := d.keyWithPrefix(key)
-	_, err := d.client.Get(finalKey)
+	_, err := d.getItem(ctx, finalKey)
 
 	if err == memcache.ErrCacheMiss {
 		return false, nil
 	} else if err != nil {
-		return false, fmt.Errorf("failed to check key existence in Memcached: %v", err)
+		return false, wrapError("Exists", finalKey, err)
 	}
 
 	return true, nil
@@ -161,7 +659,7 @@ func (d *memcached) Increment(ctx context.Context, key string) error {
 
 	_, err := d.client.Increment(finalKey, 1)
 	if err != nil {
-		return fmt.Errorf("failed to increment key value in Memcached: %v", err)
+		return wrapError("Increment", finalKey, err)
 	}
 
 	return nil
@@ -172,16 +670,118 @@ func (d *memcached) Decrement(ctx context.Context, key string) error {
 
 	_, err := d.client.Decrement(finalKey, 1)
 	if err != nil {
-		return fmt.Errorf("failed to decrement key value in Memcached: %v", err)
+		return wrapError("Decrement", finalKey, err)
 	}
 
 	return nil
 }
+
+// IncrBy adds delta to the counter with the native incr or decr command. Memcached counters are unsigned,
+// so decrementing below zero stops at zero. A missing key starts at zero and doesn't expire.
+func (d *memcached) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	finalKey := d.keyWithPrefix(key)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		var (
+			value uint64
+			err   error
+		)
+		if delta >= 0 {
+			value, err = d.client.Increment(finalKey, uint64(delta))
+		} else {
+			value, err = d.client.Decrement(finalKey, uint64(-delta))
+		}
+
+		if err == memcache.ErrCacheMiss {
+			initial := delta
+			if initial < 0 {
+				initial = 0
+			}
+
+			err = d.client.Add(&memcache.Item{Key: finalKey, Value: []byte(strconv.FormatInt(initial, 10))})
+			if err == memcache.ErrNotStored {
+				continue
+			}
+			if err != nil {
+				return 0, wrapError("IncrBy", finalKey, err)
+			}
+			return initial, nil
+		}
+		if err != nil {
+			if strings.Contains(err.Error(), "non-numeric") {
+				return 0, wrapError("IncrBy", finalKey, cachemar.ErrInvalidType)
+			}
+			return 0, wrapError("IncrBy", finalKey, err)
+		}
+		return int64(value), nil
+	}
+}
+
+func (d *memcached) DecrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	return d.IncrBy(ctx, key, -delta)
+}
+
+// IncrementFloat adds delta to the value with a compare-and-swap loop, as Memcached has no float increment.
+// The expiration of the key is not preserved.
+func (d *memcached) IncrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	finalKey := d.keyWithPrefix(key)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		item, err := d.client.Get(finalKey)
+		if err == memcache.ErrCacheMiss {
+			err = d.client.Add(&memcache.Item{Key: finalKey, Value: formatFloat(delta)})
+			if err == memcache.ErrNotStored {
+				continue
+			}
+			if err != nil {
+				return 0, wrapError("IncrementFloat", finalKey, err)
+			}
+			return delta, nil
+		}
+		if err != nil {
+			return 0, wrapError("IncrementFloat", finalKey, err)
+		}
+
+		value, err := strconv.ParseFloat(string(item.Value), 64)
+		if err != nil {
+			return 0, wrapError("IncrementFloat", finalKey, cachemar.ErrInvalidType)
+		}
+
+		value += delta
+		item.Value = formatFloat(value)
+
+		err = d.client.CompareAndSwap(item)
+		if err == memcache.ErrCASConflict || err == memcache.ErrNotStored {
+			continue
+		}
+		if err != nil {
+			return 0, wrapError("IncrementFloat", finalKey, err)
+		}
+		return value, nil
+	}
+}
+
+func (d *memcached) DecrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	return d.IncrementFloat(ctx, key, -delta)
+}
+
+func formatFloat(value float64) []byte {
+	return []byte(strconv.FormatFloat(value, 'f', -1, 64))
+}
+
 func (d *memcached) GetKeysByTag(ctx context.Context, tag string) ([]string, error) {
 	tagKey := d.getTagKey(tag)
 	item, err := d.client.Get(tagKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get keys associated with tag: %v", err)
+		return nil, wrapError("GetKeysByTag", tag, err)
 	}
 
 	var keys []string
@@ -192,8 +792,84 @@ func (d *memcached) GetKeysByTag(ctx context.Context, tag string) ([]string, err
 	return keys, nil
 }
 
+func (d *memcached) CountByTag(ctx context.Context, tag string) (int64, error) {
+	item, err := d.client.Get(d.getTagKey(tag))
+	if err == memcache.ErrCacheMiss {
+		return 0, nil
+	} else if err != nil {
+		return 0, wrapError("CountByTag", tag, err)
+	}
+
+	var keys []string
+	if err := json.Unmarshal(item.Value, &keys); err != nil {
+		return 0, err
+	}
+
+	return int64(len(keys)), nil
+}
+
 func (d *memcached) getTagKey(tag string) string {
-	return fmt.Sprintf("tag:%s", tag)
+	return keybuf.Join(":", "tag", tag)
+}
+
+// BackendInfo reports the version and uptime of the first Memcached server.
+// gomemcache doesn't expose the "stats" command, so it is issued over a separate connection.
+func (d *memcached) BackendInfo(ctx context.Context) (cachemar.BackendInfo, error) {
+	info := cachemar.BackendInfo{Type: cachemar.MemcachedCacherName.String()}
+	if len(d.servers) == 0 {
+		return info, wrapError("BackendInfo", "", memcache.ErrNoServers)
+	}
+	info.Addr = d.servers[0]
+
+	conn, err := d.dial(ctx, "tcp", info.Addr)
+	if err != nil {
+		return info, wrapError("BackendInfo", "", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Now().Add(memcache.DefaultTimeout))
+	}
+
+	if _, err := fmt.Fprint(conn, "stats\r\n"); err != nil {
+		return info, wrapError("BackendInfo", "", err)
+	}
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 1 && fields[0] == "END" {
+			break
+		}
+		if len(fields) != 3 || fields[0] != "STAT" {
+			continue
+		}
+
+		switch fields[1] {
+		case "version":
+			info.Version = fields[2]
+		case "uptime":
+			info.UptimeSeconds, _ = strconv.ParseInt(fields[2], 10, 64)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return info, wrapError("BackendInfo", "", err)
+	}
+
+	return info, nil
+}
+
+// Flush invalidates all items on the servers, including those stored under other prefixes, as Memcached
+// can't enumerate keys.
+func (d *memcached) Flush(ctx context.Context) error {
+	err := run(ctx, d.deleteTimeout, d.client.FlushAll)
+	if err != nil {
+		return wrapError("Flush", "", err)
+	}
+
+	return nil
 }
 
 func (d *memcached) Close() error {
@@ -207,8 +883,13 @@ func (d *memcached) Ping() error {
 	}
 	_, err = d.client.Get("selfcheck")
 	if err != nil {
-		return fmt.Errorf("failed to get value from Memcached: %v", err)
+		return wrapError("Ping", "", err)
 	}
 
 	return nil
 }
+
+// wrapError attaches the driver, operation and key to err.
+func wrapError(operation, key string, err error) error {
+	return &cachemar.DriverError{Driver: cachemar.MemcachedCacherName.String(), Operation: operation, Key: key, Cause: err}
+}
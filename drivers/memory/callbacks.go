@@ -0,0 +1,37 @@
+package memory
+
+// notification is a pending call of OnEvict or OnExpire.
+type notification struct {
+	callback func(key string, value []byte)
+	key      string
+	item     Item
+}
+
+// notify queues a call of callback for the removed item, made once the lock is released. The caller must
+// hold the lock.
+func (d *memory) notify(callback func(key string, value []byte), key string, item Item) {
+	if callback != nil {
+		d.notifications = append(d.notifications, notification{callback: callback, key: key, item: item})
+	}
+}
+
+// release unlocks the mutex and then calls the callbacks queued while it was held, so they may use the cache.
+func (d *memory) release() {
+	notifications := d.notifications
+	d.notifications = nil
+	d.mu.Unlock()
+
+	for _, n := range notifications {
+		value := n.item.Value
+		if !n.item.Raw {
+			// Stored values never change in place, so they are safe to read without the lock.
+			decompressed, err := decompressData(value)
+			if err != nil {
+				decompressed = nil
+			}
+			value = decompressed
+		}
+
+		n.callback(n.key, value)
+	}
+}
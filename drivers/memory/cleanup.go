@@ -0,0 +1,36 @@
+package memory
+
+import "time"
+
+// isExpired reports whether the item's time-to-live has passed at now.
+func isExpired(item Item, now time.Time) bool {
+	return item.ExpiryTime.Before(now)
+}
+
+// runCleanup removes the expired items every interval until Close.
+func (d *memory) runCleanup(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			d.removeExpired()
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+// removeExpired removes all expired items.
+func (d *memory) removeExpired() {
+	d.mu.Lock()
+	defer d.release()
+
+	now := time.Now()
+	for key, item := range d.items {
+		if isExpired(item, now) {
+			d.expire(key)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCoalesceWindow is how often coalesced writes are applied when Config.CoalesceWindow is zero.
+	DefaultCoalesceWindow = time.Millisecond
+	// DefaultCoalesceBufferSize is the number of buffered writes that triggers an immediate flush when
+	// Config.CoalesceBufferSize is zero.
+	DefaultCoalesceBufferSize = 1024
+)
+
+type pendingWrite struct {
+	key  string
+	item Item
+}
+
+// coalescer buffers Set calls so they are applied in batches under a single acquisition of the main lock.
+type coalescer struct {
+	mu      sync.Mutex
+	pending []pendingWrite
+	size    int
+	window  time.Duration
+	stop    chan struct{}
+	stopped sync.Once
+}
+
+func newCoalescer(config *Config) *coalescer {
+	window := config.CoalesceWindow
+	if window == 0 {
+		window = DefaultCoalesceWindow
+	}
+
+	size := config.CoalesceBufferSize
+	if size == 0 {
+		size = DefaultCoalesceBufferSize
+	}
+
+	return &coalescer{
+		pending: make([]pendingWrite, 0, size),
+		size:    size,
+		window:  window,
+		stop:    make(chan struct{}),
+	}
+}
+
+// add buffers a write and reports whether the buffer is full.
+func (c *coalescer) add(key string, item Item) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pending = append(c.pending, pendingWrite{key: key, item: item})
+	return len(c.pending) >= c.size
+}
+
+// take returns the buffered writes and empties the buffer.
+func (c *coalescer) take() []pendingWrite {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pending := c.pending
+	c.pending = make([]pendingWrite, 0, c.size)
+	return pending
+}
+
+// runCoalescer applies the buffered writes every window until Close.
+func (d *memory) runCoalescer() {
+	ticker := time.NewTicker(d.coalescer.window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			d.flushPending()
+		case <-d.coalescer.stop:
+			return
+		}
+	}
+}
+
+// setCoalesced buffers the write, flushing at once if the buffer is full.
+func (d *memory) setCoalesced(ctx context.Context, key string, item Item) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if d.coalescer.add(key, item) {
+		d.flushPending()
+	}
+
+	return nil
+}
+
+func (d *memory) flushPending() {
+	d.mu.Lock()
+	defer d.release()
+
+	d.applyPending()
+}
+
+// applyPending applies the buffered writes in order. The caller must hold the main lock.
+func (d *memory) applyPending() {
+	if d.coalescer == nil {
+		return
+	}
+
+	pending := d.coalescer.take()
+	if len(pending) == 0 {
+		return
+	}
+
+	for _, write := range pending {
+		d.setItem(write.key, write.item)
+	}
+	d.evictLRU()
+}
+
+// lockWrite acquires the main lock for a write. Buffered writes are applied first so that they keep
+// their order relative to the write, e.g. a Remove is not undone by an earlier Set flushed after it.
+func (d *memory) lockWrite(ctx context.Context) error {
+	if err := d.lock(ctx); err != nil {
+		return err
+	}
+
+	d.applyPending()
+	return nil
+}
+
+// stopCoalescer stops the background flush and applies the remaining writes.
+func (d *memory) stopCoalescer() {
+	if d.coalescer == nil {
+		return
+	}
+
+	d.coalescer.stopped.Do(
+		func() {
+			close(d.coalescer.stop)
+			d.flushPending()
+		},
+	)
+}
@@ -0,0 +1,176 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Reservation reserves capacity for the keys starting with Prefix, so subsystems sharing the cache
+// can't starve each other. Zero limits mean no limit.
+type Reservation struct {
+	Prefix   string
+	MaxItems int
+	MaxBytes int64
+}
+
+// partition tracks the usage of the keys of a reservation.
+type partition struct {
+	Reservation
+	order evictionList // Keys ordered for eviction by the policy.
+	items int
+	bytes int64
+}
+
+func (p *partition) overLimit() bool {
+	return (p.MaxItems > 0 && p.items > p.MaxItems) ||
+		(p.MaxBytes > 0 && p.bytes > p.MaxBytes)
+}
+
+// usage is the position of a key in the global and in its partition's eviction order.
+type usage struct {
+	global    *entry
+	local     *entry
+	partition *partition
+}
+
+// newPartitions creates the partitions of the reservations, the longest prefix first so it wins the match.
+// Keys not matching any reservation fall into a default partition without limits of its own.
+func newPartitions(reservations []Reservation) ([]*partition, *partition) {
+	partitions := make([]*partition, 0, len(reservations))
+	for _, reservation := range reservations {
+		partitions = append(partitions, &partition{Reservation: reservation})
+	}
+
+	sort.SliceStable(
+		partitions, func(i, j int) bool {
+			return len(partitions[i].Prefix) > len(partitions[j].Prefix)
+		},
+	)
+
+	return partitions, &partition{}
+}
+
+func (d *memory) partitionFor(key string) *partition {
+	for _, p := range d.partitions {
+		if strings.HasPrefix(key, p.Prefix) {
+			return p
+		}
+	}
+
+	return d.defaultPartition
+}
+
+// store saves the item and records it as used. Its prefetched value, if any, is dropped, as it may be
+// outdated. The caller must hold the lock.
+func (d *memory) store(key string, item Item) {
+	d.save(key)
+	u := d.touch(key)
+	d.lastStored = key
+	delete(d.prefetched, key)
+	if old, exists := d.items[key]; exists {
+		d.totalBytes -= int64(len(old.Value))
+		u.partition.bytes -= int64(len(old.Value))
+	}
+
+	d.items[key] = item
+	d.totalBytes += int64(len(item.Value))
+	u.partition.bytes += int64(len(item.Value))
+}
+
+// touch records a use of the key. The caller must hold the lock.
+func (d *memory) touch(key string) *usage {
+	if u, exists := d.usage[key]; exists {
+		d.order.access(u.global)
+		u.partition.order.access(u.local)
+		return u
+	}
+
+	p := d.partitionFor(key)
+	p.items++
+
+	u := &usage{
+		global:    d.order.push(key),
+		local:     p.order.push(key),
+		partition: p,
+	}
+	d.usage[key] = u
+
+	return u
+}
+
+// forget removes the key from the usage tracking. The caller must hold the lock.
+func (d *memory) forget(key string, item Item) {
+	u, exists := d.usage[key]
+	if !exists {
+		return
+	}
+
+	d.totalBytes -= int64(len(item.Value))
+	d.order.remove(u.global)
+
+	u.partition.bytes -= int64(len(item.Value))
+	u.partition.items--
+	u.partition.order.remove(u.local)
+
+	delete(d.usage, key)
+	delete(d.prefetched, key)
+}
+
+// overLimit reports whether the cache exceeds MaxSize or MaxBytes.
+func (d *memory) overLimit() bool {
+	return (d.maxSize > 0 && len(d.items) > d.maxSize) ||
+		(d.maxBytes > 0 && d.totalBytes > d.maxBytes)
+}
+
+// evictLRU removes items chosen by the eviction policy, first of every reservation over its quota and
+// then of the whole cache, until both MaxSize and MaxBytes are satisfied. The caller must hold the lock.
+//
+// The item stored last is evicted only if nothing else is left, so with LFU a new item isn't evicted
+// right away for not having been used yet.
+func (d *memory) evictLRU() {
+	var global, local *entry
+	if u, exists := d.usage[d.lastStored]; exists {
+		global, local = u.global, u.local
+	}
+
+	for _, p := range d.partitions {
+		d.evict(p.overLimit, p.order, local)
+	}
+
+	d.evict(d.overLimit, d.order, global)
+}
+
+func (d *memory) evict(overLimit func() bool, order evictionList, spare *entry) {
+	for overLimit() {
+		key, ok := order.victim(spare)
+		if !ok {
+			return
+		}
+		if item := d.items[key]; !isExpired(item, time.Now()) {
+			d.notify(d.onEvict, key, item)
+		}
+		d.removeEntry(key)
+		d.counters.evictions.Add(1)
+	}
+}
+
+// BytesUsed returns the total size of the stored values in bytes.
+func (d *memory) BytesUsed() int64 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.totalBytes
+}
+
+func (d *memory) resetUsage() {
+	d.order = newEvictionList(d.policy)
+	d.usage = make(map[string]*usage)
+	d.totalBytes = 0
+
+	for _, p := range append(d.partitions, d.defaultPartition) {
+		p.order = newEvictionList(d.policy)
+		p.items = 0
+		p.bytes = 0
+	}
+}
@@ -4,32 +4,352 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
-	"encoding/gob"
 	"errors"
+	"fmt"
 	"io"
+	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/codecs"
 )
 
+// ctxCheckInterval is how many items long-running loops process between context checks.
+const ctxCheckInterval = 1000
+
 type Item struct {
 	Value      []byte
 	Tags       []string
 	ExpiryTime time.Time
+	Raw        bool // Value holds uncompressed content stored by SetRaw or SetStream.
+	SetAt      time.Time
+	TTL        time.Duration // The ttl the item was set with, re-applied on access with Config.SlidingTTL.
 }
 
 type memory struct {
-	mu    sync.Mutex
-	items map[string]Item
+	mu               sync.RWMutex
+	items            map[string]Item
+	tagKeys          map[string]map[string]struct{} // Reverse lookup of the keys associated with each tag.
+	codec            cachemar.Codec
+	createdAt        time.Time
+	maxSize          int
+	maxBytes         int64
+	totalBytes       int64 // Sum of len(item.Value) of all items.
+	policy           EvictionPolicy
+	order            evictionList      // Keys ordered for eviction by the policy.
+	usage            map[string]*usage // Position of each key in the eviction orders.
+	counters         counters
+	lastStored       string               // Key of the item stored last, spared by the eviction.
+	partitions       []*partition         // Usage of the reservations, the longest prefix first.
+	defaultPartition *partition           // Usage of the keys not matching any reservation.
+	coalescer        *coalescer           // Buffer of the coalesced writes, nil unless Config.CoalesceWrites is set.
+	tx               map[string]savedItem // State of the keys before the running transaction changed them.
+	slidingTTL       bool
+	onEvict          func(key string, value []byte)
+	onExpire         func(key string, value []byte)
+	notifications    []notification // Callbacks to call once the lock is released.
+	readOptimized    bool           // Reads take a read lock, as they don't change the state. See readsMutate.
+	stop             chan struct{}  // Closed by Close to stop the background cleanup.
+	closeOnce        sync.Once
+	slots            chan struct{} // Semaphore limiting concurrent operations, nil if unlimited.
+	waitForSlot      time.Duration
+
+	prefetchDecodeAsync bool
+	prefetched          map[string]prefetched // Values decompressed ahead by PrefetchHint.
+}
+
+// Config configures the memory driver.
+type Config struct {
+	// Codec serializes the stored values. Defaults to codecs.GobCodec.
+	Codec cachemar.Codec
+	// MaxSize limits the number of items. Items are evicted according to EvictionPolicy. Zero means no limit.
+	MaxSize int
+	// MaxBytes limits the total size of the stored values. Items are evicted according to EvictionPolicy. Zero means no limit.
+	MaxBytes int64
+	// Reservations limit the keys with the given prefixes independently. When a reservation is full,
+	// only its own items are evicted. The longest matching prefix wins.
+	Reservations []Reservation
+	// EvictionPolicy selects the items to evict. Defaults to LRU.
+	EvictionPolicy EvictionPolicy
+
+	// CoalesceWrites buffers Set calls and applies them in batches, every CoalesceWindow or once
+	// CoalesceBufferSize writes are pending. Reads may miss a value for up to CoalesceWindow after it is set,
+	// so leave this off if a Get must observe the preceding Set. Other writes apply the buffer first.
+	CoalesceWrites bool
+	// CoalesceWindow defaults to DefaultCoalesceWindow.
+	CoalesceWindow time.Duration
+	// CoalesceBufferSize defaults to DefaultCoalesceBufferSize.
+	CoalesceBufferSize int
+
+	// CleanupInterval is how often expired items are removed in the background. Otherwise they are
+	// only dropped when overwritten or evicted. Zero disables the cleanup.
+	CleanupInterval time.Duration
+
+	// OnEvict is called with the key and the value of each live item evicted to satisfy MaxSize, MaxBytes
+	// or a reservation limit. OnExpire is called for each expired item removed. The value is decompressed
+	// but still encoded by the codec, unless stored raw. Both run after the lock is released, on the goroutine
+	// of the operation that removed the item, so they may use the cache but delay the caller.
+	OnEvict  func(key string, value []byte)
+	OnExpire func(key string, value []byte)
+
+	// SlidingTTL resets the expiry of an item to its original ttl whenever it is read, so items expire
+	// after a period of inactivity rather than at a fixed time.
+	SlidingTTL bool
+
+	// MaxConcurrentOps limits how many operations may run or wait for the lock at once. Further operations
+	// fail with cachemar.ErrTooManyRequests instead of queuing. Zero means no limit.
+	MaxConcurrentOps int
+	// WaitForSlot is how long an operation waits for a free slot before failing. Zero fails immediately.
+	WaitForSlot time.Duration
+
+	// PrefetchDecodeAsync makes PrefetchHint decompress the hinted values in the background, so the next
+	// Get of each only has to unmarshal it.
+	PrefetchDecodeAsync bool
+}
+
+// Validate checks the configuration.
+func (c *Config) Validate() error {
+	if c == nil {
+		return errors.New("memory: config is nil")
+	}
+	if c.MaxSize < 0 {
+		return errors.New("memory: max size must not be negative")
+	}
+	if c.MaxBytes < 0 {
+		return errors.New("memory: max bytes must not be negative")
+	}
+	if c.EvictionPolicy < LRU || c.EvictionPolicy > FIFO {
+		return fmt.Errorf("memory: unknown eviction policy %v", c.EvictionPolicy)
+	}
+
+	prefixes := make(map[string]struct{}, len(c.Reservations))
+	for _, reservation := range c.Reservations {
+		if reservation.Prefix == "" {
+			return errors.New("memory: reservation prefix must not be empty")
+		}
+		if _, exists := prefixes[reservation.Prefix]; exists {
+			return fmt.Errorf("memory: duplicate reservation prefix %q", reservation.Prefix)
+		}
+		if reservation.MaxItems < 0 || reservation.MaxBytes < 0 {
+			return fmt.Errorf("memory: limits of reservation %q must not be negative", reservation.Prefix)
+		}
+		prefixes[reservation.Prefix] = struct{}{}
+	}
+	if c.CoalesceWindow < 0 {
+		return errors.New("memory: coalesce window must not be negative")
+	}
+	if c.CleanupInterval < 0 {
+		return errors.New("memory: cleanup interval must not be negative")
+	}
+	if c.MaxConcurrentOps < 0 {
+		return errors.New("memory: max concurrent ops must not be negative")
+	}
+	if c.WaitForSlot < 0 {
+		return errors.New("memory: wait for slot must not be negative")
+	}
+	if c.CoalesceBufferSize < 0 {
+		return errors.New("memory: coalesce buffer size must not be negative")
+	}
+
+	return nil
 }
 
 func New() cachemar.Cacher {
-	return &memory{
-		items: make(map[string]Item),
+	return NewWithConfig(&Config{})
+}
+
+// NewWithConfig creates a memory cache configured by config. It panics if the config is invalid.
+func NewWithConfig(config *Config) cachemar.Cacher {
+	if err := config.Validate(); err != nil {
+		panic(err)
+	}
+
+	codec := config.Codec
+	if codec == nil {
+		codec = codecs.GobCodec{}
+	}
+
+	d := &memory{
+		items:     make(map[string]Item),
+		tagKeys:   make(map[string]map[string]struct{}),
+		codec:     codec,
+		createdAt: time.Now(),
+		maxSize:   config.MaxSize,
+		maxBytes:  config.MaxBytes,
+		policy:    config.EvictionPolicy,
+
+		slidingTTL:    config.SlidingTTL,
+		readOptimized: !readsMutate(config),
+		onEvict:       config.OnEvict,
+		onExpire:      config.OnExpire,
+		stop:          make(chan struct{}),
+
+		prefetchDecodeAsync: config.PrefetchDecodeAsync,
+		prefetched:          make(map[string]prefetched),
+	}
+	d.partitions, d.defaultPartition = newPartitions(config.Reservations)
+	d.resetUsage()
+
+	if config.MaxConcurrentOps > 0 {
+		d.slots = make(chan struct{}, config.MaxConcurrentOps)
+		d.waitForSlot = config.WaitForSlot
+	}
+
+	if config.CleanupInterval > 0 {
+		go d.runCleanup(config.CleanupInterval)
+	}
+
+	if config.CoalesceWrites {
+		d.coalescer = newCoalescer(config)
+		go d.runCoalescer()
+	}
+
+	return d
+}
+
+// NewWithJSON creates a memory cache serializing values as JSON.
+func NewWithJSON() cachemar.Cacher {
+	return NewWithConfig(&Config{Codec: codecs.JSONCodec{}})
+}
+
+// NewWithMsgpack creates a memory cache serializing values as MessagePack.
+func NewWithMsgpack() cachemar.Cacher {
+	return NewWithConfig(&Config{Codec: codecs.MsgpackCodec{}})
+}
+
+// lock takes a slot and acquires the mutex unless the context is already done. Within a transaction of
+// this cache the mutex is already held, so nothing is done.
+func (d *memory) lock(ctx context.Context) error {
+	if d.inTransaction(ctx) {
+		return nil
+	}
+
+	if err := d.acquireSlot(ctx); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		d.releaseSlot()
+		return ctx.Err()
+	default:
+		d.mu.Lock()
+		return nil
+	}
+}
+
+// unlock releases the mutex and the slot acquired by lock.
+func (d *memory) unlock(ctx context.Context) {
+	if !d.inTransaction(ctx) {
+		d.release()
+		d.releaseSlot()
+	}
+}
+
+// rlock locks the cache for a read: shared with other reads if they don't change the state, exclusively
+// otherwise.
+func (d *memory) rlock(ctx context.Context) error {
+	if !d.readOptimized {
+		return d.lock(ctx)
+	}
+	if d.inTransaction(ctx) {
+		return nil
+	}
+
+	if err := d.acquireSlot(ctx); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		d.releaseSlot()
+		return ctx.Err()
+	default:
+		d.mu.RLock()
+		return nil
+	}
+}
+
+// runlock releases the lock and the slot acquired by rlock.
+func (d *memory) runlock(ctx context.Context) {
+	if !d.readOptimized {
+		d.unlock(ctx)
+		return
+	}
+	if !d.inTransaction(ctx) {
+		d.mu.RUnlock()
+		d.releaseSlot()
 	}
 }
 
+// readsMutate reports whether reads change the state of a cache configured by config: the expiry of the
+// item with SlidingTTL, the eviction order unless it is insertion order or there is nothing to evict, and
+// the values decompressed ahead with PrefetchDecodeAsync.
+func readsMutate(config *Config) bool {
+	if config.SlidingTTL || config.PrefetchDecodeAsync {
+		return true
+	}
+	if config.EvictionPolicy == FIFO {
+		return false
+	}
+
+	limited := config.MaxSize > 0 || config.MaxBytes > 0
+	for _, reservation := range config.Reservations {
+		limited = limited || reservation.MaxItems > 0 || reservation.MaxBytes > 0
+	}
+
+	return limited
+}
+
+// acquireSlot takes a slot of the concurrency limit, waiting at most waitForSlot for one to free up.
+func (d *memory) acquireSlot(ctx context.Context) error {
+	if d.slots == nil {
+		return nil
+	}
+
+	select {
+	case d.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if d.waitForSlot == 0 {
+		return cachemar.ErrTooManyRequests
+	}
+
+	timer := time.NewTimer(d.waitForSlot)
+	defer timer.Stop()
+
+	select {
+	case d.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return cachemar.ErrTooManyRequests
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *memory) releaseSlot() {
+	if d.slots != nil {
+		<-d.slots
+	}
+}
+
+// checkContext reports the context error every ctxCheckInterval iterations of long-running loops.
+func checkContext(ctx context.Context, iteration int) error {
+	if iteration%ctxCheckInterval != 0 {
+		return nil
+	}
+
+	return ctx.Err()
+}
+
+func (d *memory) Name() string {
+	return cachemar.MemoryCacherName.String()
+}
+
 func uniqueTags(tags []string) []string {
 	tagSet := make(map[string]struct{})
 	for _, tag := range tags {
@@ -44,28 +364,144 @@ func uniqueTags(tags []string) []string {
 }
 
 func (d *memory) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
-	d.mu.Lock()
-	defer d.mu.Unlock()
+	return d.SetOpts(ctx, key, value, cachemar.SetOptions{TTL: ttl, Tags: tags})
+}
 
-	tags = uniqueTags(tags)
-	var buf bytes.Buffer
-	enc := gob.NewEncoder(&buf)
+// MSet encodes the items and then stores them under a single lock.
+func (d *memory) MSet(ctx context.Context, items []cachemar.CacheItem) error {
+	errs := make(cachemar.MultiError, len(items))
+	encoded := make([]Item, len(items))
+	for i, item := range items {
+		encoded[i], errs[i] = d.newItem(item.Value, item.TTL, item.Tags)
+	}
 
-	if err := enc.Encode(value); err != nil {
+	if err := d.lockWrite(ctx); err != nil {
 		return err
 	}
+	defer d.unlock(ctx)
 
-	compressedValue, err := compressData(buf.Bytes())
+	for i, item := range items {
+		if errs[i] == nil {
+			d.setItem(item.Key, encoded[i])
+		}
+	}
+	d.evictLRU()
+
+	return errs.ErrorOrNil()
+}
+
+// SetOpts stores the value as described by the options.
+func (d *memory) SetOpts(ctx context.Context, key string, value interface{}, opts cachemar.SetOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	if opts.Conditional() {
+		written, err := d.setIf(ctx, key, value, opts.TTL, opts.Tags, opts.IfPresent)
+		if err == nil && !written {
+			return cachemar.ErrNotStored
+		}
+		return err
+	}
+
+	item, err := d.newItem(value, opts.TTL, opts.Tags)
 	if err != nil {
 		return err
 	}
 
-	d.items[key] = Item{
+	return d.putItem(ctx, key, item)
+}
+
+// putItem stores the encoded item, buffering the write with CoalesceWrites.
+func (d *memory) putItem(ctx context.Context, key string, item Item) error {
+	if d.coalescer != nil && !d.inTransaction(ctx) {
+		return d.setCoalesced(ctx, key, item)
+	}
+
+	if err := d.lock(ctx); err != nil {
+		return err
+	}
+	defer d.unlock(ctx)
+
+	d.setItem(key, item)
+	d.evictLRU()
+	return nil
+}
+
+// newItem encodes the value into an item. It doesn't need the lock.
+func (d *memory) newItem(value interface{}, ttl time.Duration, tags []string) (Item, error) {
+	data, err := d.codec.Marshal(value)
+	if err != nil {
+		return Item{}, err
+	}
+
+	return newEncodedItem(data, ttl, tags)
+}
+
+// newEncodedItem compresses the data serialized by the codec into an item.
+func newEncodedItem(data []byte, ttl time.Duration, tags []string) (Item, error) {
+	compressedValue, err := compressData(data)
+	if err != nil {
+		return Item{}, err
+	}
+
+	return Item{
 		Value:      compressedValue,
-		Tags:       tags,
+		Tags:       uniqueTags(tags),
 		ExpiryTime: time.Now().Add(ttl),
+		SetAt:      time.Now(),
+		TTL:        ttl,
+	}, nil
+}
+
+// SetIfAbsent stores the value only if the key does not exist or has expired.
+func (d *memory) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	return d.setIf(ctx, key, value, ttl, tags, false)
+}
+
+// SetNX stores the value only if the key is absent or expired, checking and writing under one lock.
+// Tags are not supported for NX operations.
+func (d *memory) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return d.setIf(ctx, key, value, ttl, nil, false)
+}
+
+// SetIfPresent stores the value only if the key exists and has not expired.
+func (d *memory) SetIfPresent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	return d.setIf(ctx, key, value, ttl, tags, true)
+}
+
+// setIf stores the value if the presence of the key matches present, checking and writing under one lock.
+func (d *memory) setIf(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string, present bool) (bool, error) {
+	item, err := d.newItem(value, ttl, tags)
+	if err != nil {
+		return false, err
 	}
-	return nil
+
+	if err := d.lockWrite(ctx); err != nil {
+		return false, err
+	}
+	defer d.unlock(ctx)
+
+	existing, exists := d.items[key]
+	if exists && isExpired(existing, time.Now()) {
+		exists = false
+	}
+	if exists != present {
+		return false, nil
+	}
+
+	d.setItem(key, item)
+	d.evictLRU()
+	return true, nil
+}
+
+// setItem replaces the item stored under key. The caller must hold the lock and evict afterwards.
+func (d *memory) setItem(key string, item Item) {
+	if old, exists := d.items[key]; exists {
+		d.save(key)
+		d.removeTags(key, old.Tags)
+	}
+	d.store(key, item)
+	d.addTags(key, item.Tags)
 }
 
 func compressData(data []byte) ([]byte, error) {
@@ -85,29 +521,308 @@ func compressData(data []byte) ([]byte, error) {
 }
 
 func (d *memory) Get(ctx context.Context, key string, value interface{}) error {
-	d.mu.Lock()
-	defer d.mu.Unlock()
+	_, err := d.GetWithMetadata(ctx, key, value)
+	return err
+}
 
-	item, exists := d.items[key]
-	if !exists || item.ExpiryTime.Before(time.Now()) {
+// GetWithTTL retrieves the value together with its remaining time-to-live. Items of the memory cache
+// always expire.
+func (d *memory) GetWithTTL(ctx context.Context, key string, value interface{}) (time.Duration, error) {
+	metadata, err := d.GetWithMetadata(ctx, key, value)
+	if err != nil {
+		return 0, err
+	}
+	if metadata.TTLRemaining < 0 {
+		return 0, nil
+	}
+
+	return metadata.TTLRemaining, nil
+}
+
+// MGet retrieves the values under a single lock.
+func (d *memory) MGet(ctx context.Context, keys []string, values []interface{}) error {
+	_, err := d.MGetWithTTL(ctx, keys, values)
+	return err
+}
+
+// MGetWithTTL retrieves the values and their remaining time-to-live under a single lock.
+func (d *memory) MGetWithTTL(ctx context.Context, keys []string, values []interface{}) ([]time.Duration, error) {
+	if len(keys) != len(values) {
+		return nil, fmt.Errorf("memory: got %d keys and %d values", len(keys), len(values))
+	}
+
+	if err := d.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer d.runlock(ctx)
+
+	ttls := make([]time.Duration, len(keys))
+	errs := make(cachemar.MultiError, len(keys))
+	for i, key := range keys {
+		item, exists := d.lookup(key)
+		if !exists {
+			errs[i] = cachemar.ErrNotFound
+			continue
+		}
+		item = d.access(key, item)
+
+		if ttl := time.Until(item.ExpiryTime); ttl > 0 {
+			ttls[i] = ttl
+		}
+		if data, ok := d.takePrefetched(key, item); ok {
+			errs[i] = d.unmarshal(key, data, values[i])
+		} else {
+			errs[i] = d.decode(key, item, values[i])
+		}
+	}
+
+	return ttls, errs.ErrorOrNil()
+}
+
+// GetAndDelete retrieves the value and removes it under one lock, so concurrent callers can't both get it.
+func (d *memory) GetAndDelete(ctx context.Context, key string, value interface{}) error {
+	if err := d.lockWrite(ctx); err != nil {
+		return err
+	}
+	defer d.unlock(ctx)
+
+	item, exists := d.lookup(key)
+	if !exists {
 		return cachemar.ErrNotFound
 	}
+	data, prefetched := d.takePrefetched(key, item)
+	d.removeEntry(key)
+
+	if prefetched {
+		return d.unmarshal(key, data, value)
+	}
+
+	return d.decode(key, item, value)
+}
+
+// Touch resets the expiry of the item under the lock. The value is not decoded.
+func (d *memory) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	if err := d.lockWrite(ctx); err != nil {
+		return err
+	}
+	defer d.unlock(ctx)
+
+	item, exists := d.items[key]
+	if !exists || isExpired(item, time.Now()) {
+		return wrapError("Touch", key, cachemar.ErrNotFound)
+	}
+
+	item.ExpiryTime = time.Now().Add(ttl)
+	item.TTL = ttl
+	d.store(key, item)
+
+	return nil
+}
+
+// GetWithMetadata retrieves the value together with its remaining time-to-live.
+func (d *memory) GetWithMetadata(ctx context.Context, key string, value interface{}) (cachemar.Metadata, error) {
+	if err := d.rlock(ctx); err != nil {
+		return cachemar.Metadata{}, err
+	}
+	defer d.runlock(ctx)
+
+	item, exists := d.lookup(key)
+	if !exists {
+		return cachemar.Metadata{}, cachemar.ErrNotFound
+	}
+	item = d.access(key, item)
+
+	metadata := cachemar.Metadata{TTLRemaining: time.Until(item.ExpiryTime)}
+	if data, ok := d.takePrefetched(key, item); ok {
+		return metadata, d.unmarshal(key, data, value)
+	}
+
+	return metadata, d.decode(key, item, value)
+}
+
+// Inspect dumps the stored item. The value can only be decoded without a target type by self-describing
+// codecs such as JSON.
+func (d *memory) Inspect(ctx context.Context, key string) (*cachemar.InspectResult, error) {
+	if err := d.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer d.runlock(ctx)
+
+	item, exists := d.items[key]
+	if !exists || isExpired(item, time.Now()) {
+		return nil, cachemar.ErrNotFound
+	}
+
+	result := &cachemar.InspectResult{
+		Key:          key,
+		RawBytes:     append([]byte(nil), item.Value...),
+		TTLRemaining: time.Until(item.ExpiryTime),
+		Tags:         append([]string(nil), item.Tags...),
+		Driver:       d.Name(),
+		Compressed:   !item.Raw,
+		SizeBytes:    int64(len(item.Value)),
+		SetAt:        item.SetAt,
+	}
+
+	if item.Raw {
+		result.DecodedValue = result.RawBytes
+	} else if err := d.decode(key, item, &result.DecodedValue); err != nil {
+		result.DecodedValue = nil
+	}
+
+	return result, nil
+}
+
+// access marks the item as read: it becomes the most recently used one and, with sliding TTL, its expiry
+// is reset. The caller must hold the lock.
+func (d *memory) access(key string, item Item) Item {
+	if d.readOptimized {
+		return item
+	}
+	d.touch(key)
+
+	if d.slidingTTL && item.TTL > 0 {
+		item.ExpiryTime = time.Now().Add(item.TTL)
+		d.items[key] = item
+	}
+
+	return item
+}
+
+// decode decodes the item stored under key into value.
+func (d *memory) decode(key string, item Item, value interface{}) error {
+	if item.Raw {
+		return cachemar.AssignRaw(item.Value, value)
+	}
 
 	decompressedValue, err := decompressData(item.Value)
+	if err != nil {
+		return wrapError(cachemar.OperationDecompress, key, err)
+	}
+
+	return d.unmarshal(key, decompressedValue, value)
+}
+
+func (d *memory) unmarshal(key string, data []byte, value interface{}) error {
+	if err := d.codec.Unmarshal(data, value); err != nil {
+		return wrapError(cachemar.OperationDeserialize, key, err)
+	}
+
+	return nil
+}
+
+// SetRaw stores a copy of the bytes without encoding or compressing them.
+func (d *memory) SetRaw(ctx context.Context, key string, value []byte, ttl time.Duration, tags []string) error {
+	return d.setRaw(ctx, key, append([]byte(nil), value...), ttl, tags)
+}
+
+// GetRaw retrieves the bytes stored by SetRaw or SetStream, and other values as encoded by the codec.
+func (d *memory) GetRaw(ctx context.Context, key string) ([]byte, error) {
+	if err := d.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer d.runlock(ctx)
+
+	item, exists := d.lookup(key)
+	if !exists {
+		return nil, cachemar.ErrNotFound
+	}
+	item = d.access(key, item)
+
+	if item.Raw {
+		return append([]byte(nil), item.Value...), nil
+	}
+
+	return decompressData(item.Value)
+}
+
+// Format reports the codec, see cachemar.EncodedCacher.
+func (d *memory) Format() string {
+	return cachemar.CodecFormat(d.codec)
+}
+
+// GetEncoded retrieves the value as encoded by the codec. Values stored by SetRaw or SetStream are
+// reported with cachemar.ErrRawValue.
+func (d *memory) GetEncoded(ctx context.Context, key string) ([]byte, error) {
+	if err := d.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer d.runlock(ctx)
+
+	item, exists := d.lookup(key)
+	if !exists {
+		return nil, cachemar.ErrNotFound
+	}
+	item = d.access(key, item)
+
+	if item.Raw {
+		return nil, cachemar.ErrRawValue
+	}
+
+	return decompressData(item.Value)
+}
+
+// SetEncoded stores the data encoded by a codec of the same Format as if its value was given to Set.
+func (d *memory) SetEncoded(ctx context.Context, key string, data []byte, ttl time.Duration, tags []string) error {
+	item, err := newEncodedItem(data, ttl, tags)
 	if err != nil {
 		return err
 	}
 
-	buf := bytes.NewBuffer(decompressedValue)
-	dec := gob.NewDecoder(buf)
+	return d.putItem(ctx, key, item)
+}
 
-	if err := dec.Decode(value); err != nil {
+func (d *memory) setRaw(ctx context.Context, key string, data []byte, ttl time.Duration, tags []string) error {
+	if err := d.lockWrite(ctx); err != nil {
 		return err
 	}
+	defer d.unlock(ctx)
 
+	d.setItem(
+		key, Item{
+			Value:      data,
+			Tags:       uniqueTags(tags),
+			ExpiryTime: time.Now().Add(ttl),
+			Raw:        true,
+			SetAt:      time.Now(),
+			TTL:        ttl,
+		},
+	)
+	d.evictLRU()
 	return nil
 }
 
+// SetStream buffers the content read from r and stores it as raw bytes.
+func (d *memory) SetStream(ctx context.Context, key string, r io.Reader, ttl time.Duration, tags []string) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	return d.setRaw(ctx, key, data, ttl, tags)
+}
+
+// GetStream returns a reader over the content stored by SetStream.
+func (d *memory) GetStream(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := d.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer d.runlock(ctx)
+
+	item, exists := d.lookup(key)
+	if !exists {
+		return nil, cachemar.ErrNotFound
+	}
+
+	if !item.Raw {
+		return nil, wrapError("GetStream", key, cachemar.ErrInvalidType)
+	}
+	item = d.access(key, item)
+
+	// Stored items are replaced rather than mutated, so the reader can share the slice.
+	return io.NopCloser(bytes.NewReader(item.Value)), nil
+}
+
 func decompressData(data []byte) ([]byte, error) {
 	zr, err := gzip.NewReader(bytes.NewBuffer(data))
 	if err != nil {
@@ -127,67 +842,178 @@ func decompressData(data []byte) ([]byte, error) {
 }
 
 func (d *memory) Remove(ctx context.Context, key string) error {
-	d.mu.Lock()
-	defer d.mu.Unlock()
+	if err := d.lockWrite(ctx); err != nil {
+		return err
+	}
+	defer d.unlock(ctx)
 
-	delete(d.items, key)
+	d.removeEntry(key)
 	return nil
 }
 
-func (d *memory) RemoveByTag(ctx context.Context, tag string) error {
-	d.mu.Lock()
-	defer d.mu.Unlock()
+// removeEntry deletes the item stored under key and drops tags left without keys. The caller must hold the lock.
+func (d *memory) removeEntry(key string) {
+	item, exists := d.items[key]
+	if !exists {
+		return
+	}
+	d.save(key)
 
-	for key, item := range d.items {
-		if item.ExpiryTime.Before(time.Now()) {
-			delete(d.items, key)
-			continue
+	if isExpired(item, time.Now()) {
+		d.notify(d.onExpire, key, item)
+	}
+	d.removeTags(key, item.Tags)
+	d.forget(key, item)
+	delete(d.items, key)
+}
+
+// removeTags dissociates the key from the tags. The caller must hold the lock.
+func (d *memory) removeTags(key string, tags []string) {
+	for _, tag := range tags {
+		keys := d.tagKeys[tag]
+		delete(keys, key)
+		if len(keys) == 0 {
+			delete(d.tagKeys, tag)
 		}
-		for _, itemTag := range item.Tags {
-			if itemTag == tag {
-				delete(d.items, key)
-				break
-			}
+	}
+}
+
+// addTags associates the key with the tags. The caller must hold the lock.
+func (d *memory) addTags(key string, tags []string) {
+	for _, tag := range tags {
+		keys, exists := d.tagKeys[tag]
+		if !exists {
+			keys = make(map[string]struct{})
+			d.tagKeys[tag] = keys
 		}
+		keys[key] = struct{}{}
+	}
+}
+
+func (d *memory) RemoveByTag(ctx context.Context, tag string) error {
+	if err := d.lockWrite(ctx); err != nil {
+		return err
+	}
+	defer d.unlock(ctx)
+
+	// Collect the keys first so that the tag index is not modified while it is being iterated.
+	toRemove := make([]string, 0, len(d.tagKeys[tag]))
+
+	i := 0
+	for key := range d.tagKeys[tag] {
+		if err := checkContext(ctx, i); err != nil {
+			return err
+		}
+		i++
+
+		toRemove = append(toRemove, key)
+	}
+
+	for _, key := range toRemove {
+		d.removeEntry(key)
 	}
 	return nil
 }
 
 func (d *memory) RemoveByTags(ctx context.Context, tags []string) error {
-	d.mu.Lock()
-	defer d.mu.Unlock()
+	if err := d.lockWrite(ctx); err != nil {
+		return err
+	}
+	defer d.unlock(ctx)
+
+	toRemove := make([]string, 0)
 
+	i := 0
 	for _, tag := range tags {
-		for key, item := range d.items {
-			for _, itemTag := range item.Tags {
-				if itemTag == tag {
-					delete(d.items, key)
-					break
-				}
+		for key := range d.tagKeys[tag] {
+			if err := checkContext(ctx, i); err != nil {
+				return err
 			}
+			i++
+
+			toRemove = append(toRemove, key)
 		}
 	}
+
+	for _, key := range toRemove {
+		d.removeEntry(key)
+	}
 	return nil
 }
 
 func (d *memory) Exists(ctx context.Context, key string) (bool, error) {
-	d.mu.Lock()
-	defer d.mu.Unlock()
+	if err := d.rlock(ctx); err != nil {
+		return false, err
+	}
+	defer d.runlock(ctx)
 
 	item, exists := d.items[key]
-	if !exists || item.ExpiryTime.Before(time.Now()) {
+	if !exists || isExpired(item, time.Now()) {
 		return false, nil
 	}
 	return true, nil
 }
 
+// Scan calls fn for each unexpired key matching the pattern, using the syntax of filepath.Match.
+// The matching keys are collected under the lock, and fn is called after it is released, so fn may
+// use the cache.
+func (d *memory) Scan(ctx context.Context, pattern string, fn func(key string) error) error {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return fmt.Errorf("memory: invalid pattern %q: %w", pattern, err)
+	}
+
+	keys, err := d.matchKeys(ctx, pattern)
+	if err != nil {
+		return err
+	}
+
+	for i, key := range keys {
+		if err := checkContext(ctx, i); err != nil {
+			return err
+		}
+		if err := fn(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *memory) matchKeys(ctx context.Context, pattern string) ([]string, error) {
+	if err := d.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer d.runlock(ctx)
+
+	now := time.Now()
+	keys := make([]string, 0)
+	i := 0
+	for key, item := range d.items {
+		if err := checkContext(ctx, i); err != nil {
+			return nil, err
+		}
+		i++
+
+		if isExpired(item, now) {
+			continue
+		}
+		if matched, _ := filepath.Match(pattern, key); matched {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
 func (d *memory) Increment(ctx context.Context, key string) error {
-	d.mu.Lock()
-	defer d.mu.Unlock()
+	if err := d.lockWrite(ctx); err != nil {
+		return err
+	}
+	defer d.unlock(ctx)
 
 	item, exists := d.items[key]
-	if !exists || item.ExpiryTime.Before(time.Now()) {
-		return errors.New("key not found or expired")
+	if !exists || isExpired(item, time.Now()) {
+		return wrapError("Increment", key, cachemar.ErrNotFound)
 	}
 
 	// Decompress the value
@@ -198,41 +1024,41 @@ func (d *memory) Increment(ctx context.Context, key string) error {
 
 	// Decode the value into an integer
 	var intValue int
-	buf := bytes.NewBuffer(decompressedValue)
-	dec := gob.NewDecoder(buf)
-	if err := dec.Decode(&intValue); err != nil {
-		return errors.New("value is not an integer")
+	if err := d.codec.Unmarshal(decompressedValue, &intValue); err != nil {
+		return wrapError("Increment", key, cachemar.ErrInvalidType)
 	}
 
 	// Increment the value
 	intValue++
 
 	// Re-encode and compress the value
-	var newBuf bytes.Buffer
-	enc := gob.NewEncoder(&newBuf)
-	if err := enc.Encode(intValue); err != nil {
+	data, err := d.codec.Marshal(intValue)
+	if err != nil {
 		return err
 	}
 
-	compressedValue, err := compressData(newBuf.Bytes())
+	compressedValue, err := compressData(data)
 	if err != nil {
 		return err
 	}
 
 	// Update the item in the cache
 	item.Value = compressedValue
-	d.items[key] = item
+	d.store(key, item)
+	d.evictLRU()
 
 	return nil
 }
 
 func (d *memory) Decrement(ctx context.Context, key string) error {
-	d.mu.Lock()
-	defer d.mu.Unlock()
+	if err := d.lockWrite(ctx); err != nil {
+		return err
+	}
+	defer d.unlock(ctx)
 
 	item, exists := d.items[key]
-	if !exists || item.ExpiryTime.Before(time.Now()) {
-		return errors.New("key not found or expired")
+	if !exists || isExpired(item, time.Now()) {
+		return wrapError("Decrement", key, cachemar.ErrNotFound)
 	}
 
 	// Decompress the value
@@ -243,65 +1069,218 @@ func (d *memory) Decrement(ctx context.Context, key string) error {
 
 	// Decode the value into an integer
 	var intValue int
-	buf := bytes.NewBuffer(decompressedValue)
-	dec := gob.NewDecoder(buf)
-	if err := dec.Decode(&intValue); err != nil {
-		return errors.New("value is not an integer")
+	if err := d.codec.Unmarshal(decompressedValue, &intValue); err != nil {
+		return wrapError("Decrement", key, cachemar.ErrInvalidType)
 	}
 
 	// Decrement the value
 	intValue--
 
 	// Re-encode and compress the value
-	var newBuf bytes.Buffer
-	enc := gob.NewEncoder(&newBuf)
-	if err := enc.Encode(intValue); err != nil {
+	data, err := d.codec.Marshal(intValue)
+	if err != nil {
 		return err
 	}
 
-	compressedValue, err := compressData(newBuf.Bytes())
+	compressedValue, err := compressData(data)
 	if err != nil {
 		return err
 	}
 
 	// Update the item in the cache
 	item.Value = compressedValue
-	d.items[key] = item
+	d.store(key, item)
+	d.evictLRU()
 
 	return nil
 }
 
+// IncrBy adds delta to the int64 value of the key. A missing key starts at zero
+// and is stored for cachemar.DefaultCacheTime.
+func (d *memory) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	if err := d.lockWrite(ctx); err != nil {
+		return 0, err
+	}
+	defer d.unlock(ctx)
+
+	var intValue int64
+
+	item, exists := d.items[key]
+	if !exists || isExpired(item, time.Now()) {
+		d.removeEntry(key)
+		item = Item{ExpiryTime: time.Now().Add(cachemar.DefaultCacheTime)}
+	} else {
+		if item.Raw {
+			return 0, wrapError("IncrBy", key, cachemar.ErrInvalidType)
+		}
+
+		decompressedValue, err := decompressData(item.Value)
+		if err != nil {
+			return 0, err
+		}
+
+		if err := d.codec.Unmarshal(decompressedValue, &intValue); err != nil {
+			return 0, wrapError("IncrBy", key, cachemar.ErrInvalidType)
+		}
+	}
+
+	intValue += delta
+
+	data, err := d.codec.Marshal(intValue)
+	if err != nil {
+		return 0, err
+	}
+
+	compressedValue, err := compressData(data)
+	if err != nil {
+		return 0, err
+	}
+
+	item.Value = compressedValue
+	d.store(key, item)
+	d.evictLRU()
+
+	return intValue, nil
+}
+
+func (d *memory) DecrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	return d.IncrBy(ctx, key, -delta)
+}
+
+// IncrementFloat adds delta to the float64 value of the key. A missing key starts at zero
+// and is stored for cachemar.DefaultCacheTime.
+func (d *memory) IncrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	if err := d.lockWrite(ctx); err != nil {
+		return 0, err
+	}
+	defer d.unlock(ctx)
+
+	var floatValue float64
+
+	item, exists := d.items[key]
+	if !exists || isExpired(item, time.Now()) {
+		d.removeEntry(key)
+		item = Item{ExpiryTime: time.Now().Add(cachemar.DefaultCacheTime)}
+	} else {
+		if item.Raw {
+			return 0, cachemar.ErrInvalidType
+		}
+
+		decompressedValue, err := decompressData(item.Value)
+		if err != nil {
+			return 0, err
+		}
+
+		if err := d.codec.Unmarshal(decompressedValue, &floatValue); err != nil {
+			return 0, cachemar.ErrInvalidType
+		}
+	}
+
+	floatValue += delta
+
+	data, err := d.codec.Marshal(floatValue)
+	if err != nil {
+		return 0, err
+	}
+
+	compressedValue, err := compressData(data)
+	if err != nil {
+		return 0, err
+	}
+
+	item.Value = compressedValue
+	d.store(key, item)
+	d.evictLRU()
+
+	return floatValue, nil
+}
+
+func (d *memory) DecrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	return d.IncrementFloat(ctx, key, -delta)
+}
+
 func (d *memory) GetKeysByTag(ctx context.Context, tag string) ([]string, error) {
-	d.mu.Lock()
-	defer d.mu.Unlock()
+	if err := d.rlock(ctx); err != nil {
+		return nil, err
+	}
+	defer d.runlock(ctx)
 
 	var activeKeys []string
-	for key, item := range d.items {
-		if item.ExpiryTime.Before(time.Now()) {
-			continue
+	now := time.Now()
+	i := 0
+	for key := range d.tagKeys[tag] {
+		if err := checkContext(ctx, i); err != nil {
+			return nil, err
 		}
-		for _, itemTag := range item.Tags {
-			if itemTag == tag {
-				activeKeys = append(activeKeys, key)
-				break
-			}
+		i++
+
+		if isExpired(d.items[key], now) {
+			continue
 		}
+		activeKeys = append(activeKeys, key)
 	}
 	return activeKeys, nil
 }
 
+func (d *memory) CountByTag(ctx context.Context, tag string) (int64, error) {
+	if err := d.rlock(ctx); err != nil {
+		return 0, err
+	}
+	defer d.runlock(ctx)
+
+	var count int64
+	now := time.Now()
+	for key := range d.tagKeys[tag] {
+		if !isExpired(d.items[key], now) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// BackendInfo reports the uptime of the memory cache since it was created.
+func (d *memory) BackendInfo(ctx context.Context) (cachemar.BackendInfo, error) {
+	return cachemar.BackendInfo{
+		Type:          cachemar.MemoryCacherName.String(),
+		UptimeSeconds: int64(time.Since(d.createdAt).Seconds()),
+	}, nil
+}
+
+// Close stops the background cleanup and the coalescing of writes, applying the buffered ones.
 func (d *memory) Close() error {
+	d.closeOnce.Do(
+		func() {
+			close(d.stop)
+		},
+	)
+	d.stopCoalescer()
 	return nil
 }
 
-func (d *memory) Flush() error {
-	d.mu.Lock()
-	defer d.mu.Unlock()
+// Flush removes all items, including the writes still pending with CoalesceWrites.
+func (d *memory) Flush(ctx context.Context) error {
+	if err := d.lockWrite(ctx); err != nil {
+		return err
+	}
+	defer d.unlock(ctx)
+
+	// Within a transaction the flushed items are saved, so a failed transaction restores them.
+	for key := range d.items {
+		d.save(key)
+	}
 
 	d.items = make(map[string]Item)
+	d.tagKeys = make(map[string]map[string]struct{})
+	d.prefetched = make(map[string]prefetched)
+	d.resetUsage()
 	return nil
 }
 
 func (d *memory) Ping() error {
 	return nil
 }
+
+// wrapError attaches the driver, operation and key to err.
+func wrapError(operation, key string, err error) error {
+	return &cachemar.DriverError{Driver: cachemar.MemoryCacherName.String(), Operation: operation, Key: key, Cause: err}
+}
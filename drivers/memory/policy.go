@@ -0,0 +1,176 @@
+package memory
+
+import (
+	"container/list"
+	"fmt"
+)
+
+// EvictionPolicy selects the items evicted when MaxSize, MaxBytes or a reservation limit is exceeded.
+type EvictionPolicy int
+
+const (
+	// LRU evicts the least recently used items.
+	LRU EvictionPolicy = iota
+	// LFU evicts the least frequently used items. Reads and updates count as uses. Of items used equally
+	// often, the one that reached that count first is evicted, which for unread items is the oldest.
+	LFU
+	// FIFO evicts the oldest items, regardless of how they are used.
+	FIFO
+)
+
+func (p EvictionPolicy) String() string {
+	switch p {
+	case LRU:
+		return "LRU"
+	case LFU:
+		return "LFU"
+	case FIFO:
+		return "FIFO"
+	default:
+		return fmt.Sprintf("EvictionPolicy(%d)", int(p))
+	}
+}
+
+// entry is the position of a key in an evictionList.
+type entry struct {
+	key    string
+	elem   *list.Element
+	bucket *list.Element // Frequency bucket of the entry, LFU only.
+}
+
+// evictionList orders keys by when they should be evicted.
+type evictionList interface {
+	// push adds a key that was just stored.
+	push(key string) *entry
+	// access records a use of the key.
+	access(e *entry)
+	remove(e *entry)
+	// victim returns the key to evict next, other than spare unless it is the only one left.
+	victim(spare *entry) (string, bool)
+	len() int
+}
+
+func newEvictionList(policy EvictionPolicy) evictionList {
+	switch policy {
+	case LFU:
+		return &lfuList{buckets: list.New()}
+	case FIFO:
+		return &recencyList{keys: list.New(), fifo: true}
+	default:
+		return &recencyList{keys: list.New()}
+	}
+}
+
+// recencyList orders the keys from the most to the least recently used, or inserted for FIFO.
+type recencyList struct {
+	keys *list.List
+	fifo bool
+}
+
+func (l *recencyList) push(key string) *entry {
+	e := &entry{key: key}
+	e.elem = l.keys.PushFront(e)
+	return e
+}
+
+func (l *recencyList) access(e *entry) {
+	if !l.fifo {
+		l.keys.MoveToFront(e.elem)
+	}
+}
+
+func (l *recencyList) remove(e *entry) {
+	l.keys.Remove(e.elem)
+}
+
+func (l *recencyList) victim(spare *entry) (string, bool) {
+	back := l.keys.Back()
+	if back == nil {
+		return "", false
+	}
+	if back.Value.(*entry) == spare && back.Prev() != nil {
+		back = back.Prev()
+	}
+
+	return back.Value.(*entry).key, true
+}
+
+func (l *recencyList) len() int {
+	return l.keys.Len()
+}
+
+// lfuList keeps the keys in buckets of equal use counts, ordered from the lowest count, so every
+// operation is O(1).
+type lfuList struct {
+	buckets *list.List
+	size    int
+}
+
+// bucket holds the keys used count times, in the order they reached the count.
+type bucket struct {
+	count uint64
+	keys  *list.List
+}
+
+func (l *lfuList) push(key string) *entry {
+	front := l.buckets.Front()
+	if front == nil || front.Value.(*bucket).count != 1 {
+		front = l.buckets.PushFront(&bucket{count: 1, keys: list.New()})
+	}
+
+	e := &entry{key: key, bucket: front}
+	e.elem = front.Value.(*bucket).keys.PushBack(e)
+	l.size++
+
+	return e
+}
+
+func (l *lfuList) access(e *entry) {
+	current := e.bucket.Value.(*bucket)
+
+	next := e.bucket.Next()
+	if next == nil || next.Value.(*bucket).count != current.count+1 {
+		next = l.buckets.InsertAfter(&bucket{count: current.count + 1, keys: list.New()}, e.bucket)
+	}
+
+	l.unlink(e)
+	e.bucket = next
+	e.elem = next.Value.(*bucket).keys.PushBack(e)
+	l.size++
+}
+
+func (l *lfuList) remove(e *entry) {
+	l.unlink(e)
+}
+
+// unlink takes the entry out of its bucket, dropping the bucket once empty.
+func (l *lfuList) unlink(e *entry) {
+	b := e.bucket.Value.(*bucket)
+	b.keys.Remove(e.elem)
+	if b.keys.Len() == 0 {
+		l.buckets.Remove(e.bucket)
+	}
+	l.size--
+}
+
+func (l *lfuList) victim(spare *entry) (string, bool) {
+	front := l.buckets.Front()
+	if front == nil {
+		return "", false
+	}
+
+	victim := front.Value.(*bucket).keys.Front()
+	if victim.Value.(*entry) == spare {
+		if next := victim.Next(); next != nil {
+			victim = next
+		} else if nextBucket := front.Next(); nextBucket != nil {
+			victim = nextBucket.Value.(*bucket).keys.Front()
+		}
+	}
+
+	return victim.Value.(*entry).key, true
+}
+
+func (l *lfuList) len() int {
+	return l.size
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"time"
+)
+
+// prefetched is the decompressed value of an item, ready to be unmarshaled by the next Get.
+type prefetched struct {
+	setAt time.Time // SetAt of the item the data was decompressed from.
+	data  []byte
+}
+
+type prefetchItem struct {
+	key  string
+	item Item
+}
+
+// PrefetchHint moves the keys to the front of the LRU lists so they are unlikely to be evicted before
+// they are read. With Config.PrefetchDecodeAsync, their values are also decompressed in the background.
+func (d *memory) PrefetchHint(ctx context.Context, keys ...string) {
+	if err := d.lock(ctx); err != nil {
+		return
+	}
+
+	var decode []prefetchItem
+	for _, key := range keys {
+		item, exists := d.items[key]
+		if !exists || isExpired(item, time.Now()) {
+			continue
+		}
+		d.touch(key)
+
+		if d.prefetchDecodeAsync && !item.Raw {
+			decode = append(decode, prefetchItem{key: key, item: item})
+		}
+	}
+	d.unlock(ctx)
+
+	if len(decode) > 0 {
+		go d.decompressAhead(decode)
+	}
+}
+
+// decompressAhead decompresses the values and keeps them for items that weren't replaced meanwhile.
+func (d *memory) decompressAhead(items []prefetchItem) {
+	for _, p := range items {
+		data, err := decompressData(p.item.Value)
+		if err != nil {
+			continue
+		}
+
+		d.mu.Lock()
+		if current, exists := d.items[p.key]; exists && sameValue(current, p.item) {
+			d.prefetched[p.key] = prefetched{setAt: p.item.SetAt, data: data}
+		}
+		d.mu.Unlock()
+	}
+}
+
+// sameValue reports whether current still holds the value of the item that was read. Writes such as
+// Increment keep SetAt but always store a new value slice.
+func sameValue(current, read Item) bool {
+	if !current.SetAt.Equal(read.SetAt) || len(current.Value) != len(read.Value) {
+		return false
+	}
+
+	return len(read.Value) == 0 || &current.Value[0] == &read.Value[0]
+}
+
+// takePrefetched returns and drops the prefetched data of the item. The caller must hold the lock.
+func (d *memory) takePrefetched(key string, item Item) ([]byte, bool) {
+	p, exists := d.prefetched[key]
+	if !exists {
+		return nil, false
+	}
+	delete(d.prefetched, key)
+
+	if !p.setAt.Equal(item.SetAt) {
+		return nil, false
+	}
+
+	return p.data, true
+}
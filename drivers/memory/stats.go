@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats holds the counters of a memory cache.
+type Stats struct {
+	// Hits counts the reads that found a live item.
+	Hits uint64
+	// Misses counts the reads that failed with cachemar.ErrNotFound.
+	Misses uint64
+	// Evictions counts the items removed to satisfy MaxSize, MaxBytes or a reservation limit.
+	Evictions uint64
+	// Expirations counts the expired items removed, on read or by the background cleanup.
+	Expirations uint64
+	// CurrentSize is the number of stored items, including expired ones not removed yet.
+	CurrentSize int
+	// CurrentBytes is the total size of the stored values.
+	CurrentBytes int64
+}
+
+// Statter is implemented by the memory cache. Use a type assertion to reach it from a cachemar.Cacher.
+type Statter interface {
+	Stats() Stats
+}
+
+type counters struct {
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	evictions   atomic.Uint64
+	expirations atomic.Uint64
+}
+
+// Stats returns the counters of the cache.
+func (d *memory) Stats() Stats {
+	d.mu.RLock()
+	size, bytes := len(d.items), d.totalBytes
+	d.mu.RUnlock()
+
+	return Stats{
+		Hits:         d.counters.hits.Load(),
+		Misses:       d.counters.misses.Load(),
+		Evictions:    d.counters.evictions.Load(),
+		Expirations:  d.counters.expirations.Load(),
+		CurrentSize:  size,
+		CurrentBytes: bytes,
+	}
+}
+
+// lookup returns the live item stored under key for a read, removing it if it has expired unless reads
+// only hold the read lock, and counts the hit or miss. The caller must hold the lock taken by rlock.
+func (d *memory) lookup(key string) (Item, bool) {
+	item, exists := d.items[key]
+	if exists && isExpired(item, time.Now()) {
+		if !d.readOptimized {
+			d.expire(key)
+		}
+		exists = false
+	}
+
+	if !exists {
+		d.counters.misses.Add(1)
+		return Item{}, false
+	}
+
+	d.counters.hits.Add(1)
+	return item, true
+}
+
+// expire removes an expired item. The caller must hold the lock.
+func (d *memory) expire(key string) {
+	d.removeEntry(key)
+	d.counters.expirations.Add(1)
+}
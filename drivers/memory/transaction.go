@@ -0,0 +1,72 @@
+package memory
+
+import "context"
+
+// txKey marks the context of a transaction of the cache it points to.
+type txKey struct {
+	d *memory
+}
+
+type savedItem struct {
+	item    Item
+	existed bool
+}
+
+// Transaction runs fn holding the lock once for all the operations it performs with the given ctx.
+// If fn fails, the keys it changed are restored.
+func (d *memory) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if d.inTransaction(ctx) {
+		return fn(ctx)
+	}
+
+	if err := d.lockWrite(ctx); err != nil {
+		return err
+	}
+	defer d.unlock(ctx)
+
+	d.tx = make(map[string]savedItem)
+	defer func() {
+		d.tx = nil
+	}()
+	notified := len(d.notifications)
+
+	if err := fn(context.WithValue(ctx, txKey{d: d}, true)); err != nil {
+		// The removals of the transaction are undone, so are their callbacks.
+		d.notifications = d.notifications[:notified]
+		d.rollback()
+		return err
+	}
+
+	return nil
+}
+
+func (d *memory) inTransaction(ctx context.Context) bool {
+	return ctx.Value(txKey{d: d}) != nil
+}
+
+// save records the state of the key before the running transaction first changes it. The caller must hold the lock.
+func (d *memory) save(key string) {
+	if d.tx == nil {
+		return
+	}
+	if _, saved := d.tx[key]; saved {
+		return
+	}
+
+	item, existed := d.items[key]
+	d.tx[key] = savedItem{item: item, existed: existed}
+}
+
+// rollback restores the keys changed by the running transaction. The caller must hold the lock.
+func (d *memory) rollback() {
+	saved := d.tx
+	d.tx = nil
+
+	for key, s := range saved {
+		d.removeEntry(key)
+		if s.existed {
+			d.setItem(key, s.item)
+		}
+	}
+	d.evictLRU()
+}
@@ -0,0 +1,62 @@
+package redis
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/stremovskyy/cachemar/internal/compress"
+)
+
+// CompressionAlgo is the algorithm values are compressed with when compression is enabled.
+// Reads detect the algorithm from the stored data, so it can be changed without flushing the cache.
+type CompressionAlgo int
+
+const (
+	// CompressionGzip compresses with gzip. It is the default.
+	CompressionGzip = CompressionAlgo(compress.Gzip)
+	// CompressionZstd compresses with Zstandard, which is usually much faster than gzip at a similar ratio.
+	CompressionZstd = CompressionAlgo(compress.Zstd)
+	// CompressionLZ4 compresses with the LZ4 frame format, which is the fastest at a lower ratio.
+	CompressionLZ4 = CompressionAlgo(compress.LZ4)
+)
+
+func (a CompressionAlgo) String() string {
+	switch a {
+	case CompressionGzip:
+		return "gzip"
+	case CompressionZstd:
+		return "zstd"
+	case CompressionLZ4:
+		return "lz4"
+	default:
+		return fmt.Sprintf("CompressionAlgo(%d)", int(a))
+	}
+}
+
+func (a CompressionAlgo) valid() bool {
+	return a >= CompressionGzip && a <= CompressionLZ4
+}
+
+// detectCompression reports the algorithm the data was compressed with, if any.
+func detectCompression(data []byte) (CompressionAlgo, bool) {
+	algo, ok := compress.Detect(data)
+	return CompressionAlgo(algo), ok
+}
+
+func compressData(algo CompressionAlgo, data []byte) ([]byte, error) {
+	return compress.Compress(compress.Algo(algo), data)
+}
+
+// decompressData decompresses the data with the algorithm detected from it.
+func decompressData(compressedData []byte) ([]byte, error) {
+	return compress.Decompress(compressedData)
+}
+
+func newCompressWriter(algo CompressionAlgo, w io.Writer) (io.WriteCloser, error) {
+	return compress.NewWriter(compress.Algo(algo), w)
+}
+
+// newDecompressReader returns a reader over the decompressed data, detecting the algorithm from it.
+func newDecompressReader(data []byte) (io.ReadCloser, error) {
+	return compress.NewReader(data)
+}
@@ -0,0 +1,111 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+// HashCacher stores the fields of an object in a Redis hash, so that a single field can be read or updated
+// without re-serializing the whole object. The hash is one cache key: Remove, Touch, Exists and the tags
+// of the key act on all of its fields.
+type HashCacher interface {
+	// HSet stores the value in the field of the hash, creating the hash if needed, and associates the
+	// whole hash with the tags. A positive ttl resets the expiry of the whole hash, otherwise the expiry
+	// is left unchanged. The field, the expiry and the tags are written in one MULTI/EXEC transaction.
+	HSet(ctx context.Context, key, field string, value interface{}, ttl time.Duration, tags []string) error
+
+	// HGet retrieves the field of the hash and unmarshals it into value. ErrNotFound is returned if
+	// the hash or the field does not exist.
+	HGet(ctx context.Context, key, field string, value interface{}) error
+
+	// HGetAll returns all fields of the hash with their values as stored, i.e. encoded by the codec and
+	// possibly compressed. ErrNotFound is returned if the hash does not exist.
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+
+	// HDel removes the fields from the hash. Missing fields are ignored.
+	HDel(ctx context.Context, key string, fields ...string) error
+}
+
+func (d *redisDriver) HSet(ctx context.Context, key, field string, value interface{}, ttl time.Duration, tags []string) error {
+	ctx, cancel := withTimeout(ctx, d.setTimeout)
+	defer cancel()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	finalKey := d.keyWithPrefix(key)
+	data, err := d.encode(finalKey, value)
+	if err != nil {
+		return err
+	}
+
+	pipe, exec := d.writePipeline(ctx)
+	pipe.HSet(ctx, finalKey, field, data)
+	if ttl > 0 {
+		pipe.Expire(ctx, finalKey, ttl)
+	}
+	_ = d.addTagsWith(ctx, pipe, finalKey, tags, ttl)
+
+	if err := exec(); err != nil {
+		return wrapError("HSet", finalKey, err)
+	}
+
+	return nil
+}
+
+func (d *redisDriver) HGet(ctx context.Context, key, field string, value interface{}) error {
+	ctx, cancel := withTimeout(ctx, d.getTimeout)
+	defer cancel()
+
+	finalKey := d.keyWithPrefix(key)
+
+	data, err := d.client.HGet(ctx, finalKey, field).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return wrapError("HGet", finalKey, cachemar.ErrNotFound)
+		}
+		return wrapError("HGet", finalKey, err)
+	}
+
+	return d.decode(data, value)
+}
+
+func (d *redisDriver) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	ctx, cancel := withTimeout(ctx, d.getTimeout)
+	defer cancel()
+
+	finalKey := d.keyWithPrefix(key)
+
+	fields, err := d.client.HGetAll(ctx, finalKey).Result()
+	if err != nil {
+		return nil, wrapError("HGetAll", finalKey, err)
+	}
+	// Redis deletes a hash with its last field, so an empty result means the hash does not exist.
+	if len(fields) == 0 {
+		return nil, wrapError("HGetAll", finalKey, cachemar.ErrNotFound)
+	}
+
+	return fields, nil
+}
+
+func (d *redisDriver) HDel(ctx context.Context, key string, fields ...string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	ctx, cancel := withTimeout(ctx, d.deleteTimeout)
+	defer cancel()
+
+	finalKey := d.keyWithPrefix(key)
+
+	if err := d.cmd(ctx).HDel(ctx, finalKey, fields...).Err(); err != nil {
+		return wrapError("HDel", finalKey, err)
+	}
+
+	return nil
+}
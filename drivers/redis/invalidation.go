@@ -0,0 +1,123 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+// errAlreadySubscribed is returned by Subscribe while the subscriber is running.
+var errAlreadySubscribed = errors.New("invalidation subscriber is already running")
+
+// InvalidationPublisher announces invalidated keys on a Redis pub/sub channel, so that every process
+// running an InvalidationSubscriber drops them from its in-process cache.
+type InvalidationPublisher struct {
+	client  redis.UniversalClient
+	channel string
+}
+
+// NewInvalidationPublisher creates an InvalidationPublisher publishing on the channel with the client.
+func NewInvalidationPublisher(client redis.UniversalClient, channel string) *InvalidationPublisher {
+	return &InvalidationPublisher{client: client, channel: channel}
+}
+
+// Publish announces that the key was invalidated. The key is sent as is, without the prefix of a driver.
+func (p *InvalidationPublisher) Publish(ctx context.Context, key string) error {
+	if err := p.client.Publish(ctx, p.channel, key).Err(); err != nil {
+		return wrapError("Publish", key, err)
+	}
+
+	return nil
+}
+
+// InvalidationSubscriber removes the keys announced by an InvalidationPublisher from a local cache
+// manager, typically the memory layer in front of a shared Redis.
+type InvalidationSubscriber struct {
+	client  redis.UniversalClient
+	local   cachemar.Cacher
+	channel string
+
+	mu     sync.Mutex
+	pubsub *redis.PubSub
+	done   chan struct{}
+}
+
+// NewInvalidationSubscriber creates an InvalidationSubscriber listening on the channel with the client
+// and removing the received keys from memoryCacher.
+func NewInvalidationSubscriber(client redis.UniversalClient, memoryCacher cachemar.Cacher, channel string) *InvalidationSubscriber {
+	return &InvalidationSubscriber{client: client, local: memoryCacher, channel: channel}
+}
+
+// Subscribe subscribes to the channel and returns once the subscription is confirmed. The keys are then
+// removed in the background until ctx is done or Stop is called. Errors of the removals are ignored,
+// as the key is gone or will expire from the local cache manager anyway.
+func (s *InvalidationSubscriber) Subscribe(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pubsub != nil {
+		return errAlreadySubscribed
+	}
+
+	pubsub := s.client.Subscribe(ctx, s.channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return wrapError("Subscribe", s.channel, err)
+	}
+
+	s.pubsub = pubsub
+	s.done = make(chan struct{})
+	go s.listen(ctx, pubsub, s.done)
+
+	return nil
+}
+
+// listen removes the received keys until the subscription is closed or ctx is done.
+func (s *InvalidationSubscriber) listen(ctx context.Context, pubsub *redis.PubSub, done chan struct{}) {
+	defer close(done)
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			_ = s.local.Remove(ctx, msg.Payload)
+		case <-ctx.Done():
+			s.detach(pubsub)
+			_ = pubsub.Close()
+			return
+		}
+	}
+}
+
+// detach forgets the subscription if it is still the running one, so that Subscribe can be called again.
+func (s *InvalidationSubscriber) detach(pubsub *redis.PubSub) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pubsub == pubsub {
+		s.pubsub, s.done = nil, nil
+	}
+}
+
+// Stop closes the subscription and waits for the pending removal to finish. It is a no-op if the
+// subscriber is not running.
+func (s *InvalidationSubscriber) Stop() {
+	s.mu.Lock()
+	pubsub, done := s.pubsub, s.done
+	s.pubsub, s.done = nil, nil
+	s.mu.Unlock()
+
+	if pubsub == nil {
+		return
+	}
+
+	_ = pubsub.Close()
+	<-done
+}
@@ -0,0 +1,76 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+// unlockScript deletes the lock only if it is still held with the token, so that a lock that expired
+// and was acquired by someone else is left alone.
+var unlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// LockKey acquires the lock for the key with SET NX under "lock:{prefix:key}". The lock expires after ttl
+// unless it is released earlier with UnlockKey. It returns cachemar.ErrLockNotAcquired if the lock is held.
+func (d *redisDriver) LockKey(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	ctx, cancel := withTimeout(ctx, d.setTimeout)
+	defer cancel()
+
+	lockKey := d.lockKey(key)
+	token, err := newLockToken()
+	if err != nil {
+		return "", wrapError("LockKey", lockKey, err)
+	}
+
+	acquired, err := d.client.SetNX(ctx, lockKey, token, ttl).Result()
+	if err != nil {
+		return "", wrapError("LockKey", lockKey, err)
+	}
+	if !acquired {
+		return "", wrapError("LockKey", lockKey, cachemar.ErrLockNotAcquired)
+	}
+
+	return token, nil
+}
+
+// UnlockKey releases the lock for the key if it is still held with lockToken. Releasing a lock that
+// expired or is held by someone else is a no-op.
+func (d *redisDriver) UnlockKey(ctx context.Context, key string, lockToken string) error {
+	ctx, cancel := withTimeout(ctx, d.deleteTimeout)
+	defer cancel()
+
+	lockKey := d.lockKey(key)
+	if err := unlockScript.Run(ctx, d.client, []string{lockKey}, lockToken).Err(); err != nil {
+		return wrapError("UnlockKey", lockKey, err)
+	}
+
+	return nil
+}
+
+// lockKey returns the key of the lock. The braces make it a hash tag, so in cluster mode the lock
+// lives in the slot of the prefixed key.
+func (d *redisDriver) lockKey(key string) string {
+	return "lock:{" + d.keyWithPrefix(key) + "}"
+}
+
+// newLockToken returns a random version 4 UUID.
+func newLockToken() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
@@ -2,26 +2,46 @@ package redis
 
 import (
 	"bytes"
-	"compress/gzip"
 	"context"
-	"encoding/json"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/codecs"
+	"github.com/stremovskyy/cachemar/internal/keybuf"
 )
 
 // RedisCacheService is a service for caching data in Redis
 type redisDriver struct {
 	mu       sync.Mutex
-	client   *redis.Client
+	client   redis.UniversalClient
 	prefix   string
 	compress bool // New field to enable/disable Gzip compression
+	algo     CompressionAlgo
+	codec    cachemar.Codec
+	addr     string
+	database int
+
+	getTimeout    time.Duration
+	setTimeout    time.Duration
+	deleteTimeout time.Duration
+
+	retryConnect         bool
+	retryConnectTimeout  time.Duration
+	retryConnectInterval time.Duration
+	logger               cachemar.Logger
+
+	tracer trace.Tracer
 }
 
 type Options struct {
@@ -29,31 +49,291 @@ type Options struct {
 	Password           string
 	Database           int
 	CompressionEnabled bool
-	Prefix             string
+	// CompressionAlgo is the algorithm used when CompressionEnabled is set. Defaults to CompressionGzip.
+	CompressionAlgo CompressionAlgo
+	Prefix          string
+
+	// Username is the ACL user to authenticate as with Password. Empty means the default user.
+	Username string
+
+	// Codec serializes the stored values. Defaults to codecs.JSONCodec. Counters changed with Increment
+	// and Decrement are stored as plain numbers, which only JSON decodes.
+	Codec cachemar.Codec
+
+	// SentinelAddrs are the addresses of the Sentinels monitoring MasterName. When set, DSN is not used.
+	SentinelAddrs []string
+	// MasterName is the name of the master monitored by the Sentinels.
+	MasterName string
+	// ClusterAddrs are the seed addresses of a Redis Cluster. When set, DSN is not used.
+	ClusterAddrs []string
+	// TLSConfig enables TLS for the connections when set.
+	TLSConfig *tls.Config
+
+	// GetTimeout bounds read operations. Zero means only the deadline of the incoming context applies.
+	GetTimeout time.Duration
+	// SetTimeout bounds write operations. Zero means only the deadline of the incoming context applies.
+	SetTimeout time.Duration
+	// DeleteTimeout bounds remove operations. Zero means only the deadline of the incoming context applies.
+	DeleteTimeout time.Duration
+
+	// RetryConnect makes Init wait for Redis to become available instead of failing on the first Ping.
+	RetryConnect bool
+	// RetryConnectTimeout bounds the wait of Init. Defaults to DefaultRetryConnectTimeout.
+	RetryConnectTimeout time.Duration
+	// RetryConnectInterval is the pause between pings in Init. Defaults to DefaultRetryConnectInterval.
+	RetryConnectInterval time.Duration
+	// Logger receives the failed pings of RetryConnect. By default they are discarded.
+	Logger cachemar.Logger
+
+	// RetryCount is how many times an operation failing with a transient network or server error, e.g.
+	// during a failover, is retried. It replaces the retries of the Redis client. Zero keeps the client's.
+	// After a connection error that may have happened once Redis applied a command, only idempotent
+	// commands are retried, so a command such as INCR is never applied twice.
+	RetryCount int
+	// RetryBaseDelay is the delay before the first retry, doubled on every further one and increased by a
+	// random jitter of up to RetryBaseDelay. Defaults to DefaultRetryBaseDelay.
+	RetryBaseDelay time.Duration
+
+	// AutoTracing creates OpenTelemetry spans for tag operations. See WithAutoTracing.
+	AutoTracing bool
+	// Tracer is used by AutoTracing instead of the global tracer provider.
+	Tracer trace.Tracer
+}
+
+const (
+	DefaultRetryConnectTimeout  = 30 * time.Second
+	DefaultRetryConnectInterval = time.Second
+)
+
+// NewSentinelOptions returns options for a master monitored by Sentinel. The password is the one of the
+// master; set the remaining fields, e.g. TLSConfig or Prefix, on the result.
+func NewSentinelOptions(masterName string, sentinelAddrs []string, password string) *Options {
+	return &Options{
+		SentinelAddrs: sentinelAddrs,
+		MasterName:    masterName,
+		Password:      password,
+	}
+}
+
+// WithGetTimeout sets GetTimeout and returns the options for chaining.
+func (o *Options) WithGetTimeout(d time.Duration) *Options {
+	o.GetTimeout = d
+	return o
+}
+
+// WithSetTimeout sets SetTimeout and returns the options for chaining.
+func (o *Options) WithSetTimeout(d time.Duration) *Options {
+	o.SetTimeout = d
+	return o
+}
+
+// WithDeleteTimeout sets DeleteTimeout and returns the options for chaining.
+func (o *Options) WithDeleteTimeout(d time.Duration) *Options {
+	o.DeleteTimeout = d
+	return o
+}
+
+// WithCodec sets Codec and returns the options for chaining.
+func (o *Options) WithCodec(c cachemar.Codec) *Options {
+	o.Codec = c
+	return o
+}
+
+// Validate checks that the options describe a usable Redis connection.
+func (o *Options) Validate() error {
+	if o == nil {
+		return errors.New("redis: options are nil")
+	}
+	if len(o.SentinelAddrs) > 0 && len(o.ClusterAddrs) > 0 {
+		return errors.New("redis: sentinel and cluster addresses are mutually exclusive")
+	}
+	if o.DSN != "" && len(o.ClusterAddrs) > 0 {
+		return errors.New("redis: DSN and cluster addresses are mutually exclusive")
+	}
+	if o.DSN != "" && len(o.SentinelAddrs) > 0 {
+		return errors.New("redis: DSN and sentinel addresses are mutually exclusive")
+	}
+	if o.DSN == "" && len(o.SentinelAddrs) == 0 && len(o.ClusterAddrs) == 0 {
+		return errors.New("redis: DSN is required")
+	}
+	if len(o.SentinelAddrs) > 0 && o.MasterName == "" {
+		return errors.New("redis: master name is required with sentinel addresses")
+	}
+	if !o.CompressionAlgo.valid() {
+		return fmt.Errorf("redis: invalid compression algorithm %v", o.CompressionAlgo)
+	}
+	if o.Database < 0 {
+		return fmt.Errorf("redis: invalid database %d", o.Database)
+	}
+	if len(o.ClusterAddrs) > 0 && o.Database != 0 {
+		return errors.New("redis: cluster mode supports only database 0")
+	}
+	if o.GetTimeout < 0 || o.SetTimeout < 0 || o.DeleteTimeout < 0 {
+		return errors.New("redis: timeouts must not be negative")
+	}
+	if o.RetryCount < 0 || o.RetryBaseDelay < 0 {
+		return errors.New("redis: retry count and base delay must not be negative")
+	}
+	if o.RetryConnectTimeout < 0 || o.RetryConnectInterval < 0 {
+		return errors.New("redis: retry connect durations must not be negative")
+	}
+
+	return nil
 }
 
+// New creates a Redis cache manager. It panics if the options are invalid.
 func New(options *Options) cachemar.Cacher {
-	client := redis.NewClient(
-		&redis.Options{
-			Addr:     options.DSN,
-			Password: options.Password, // Set password if required
-			DB:       options.Database, // Use default database
-		},
-	)
+	if err := options.Validate(); err != nil {
+		panic(err)
+	}
+
+	codec := options.Codec
+	if codec == nil {
+		codec = codecs.JSONCodec{}
+	}
+
+	client := newClient(options)
+	if options.RetryCount > 0 {
+		client.AddHook(newRetryHook(options))
+	}
 
 	return &redisDriver{
 		client:   client,
 		compress: options.CompressionEnabled,
+		algo:     options.CompressionAlgo,
+		codec:    codec,
 		prefix:   options.Prefix,
+		addr:     options.addr(),
+		database: options.Database,
+
+		getTimeout:    options.GetTimeout,
+		setTimeout:    options.SetTimeout,
+		deleteTimeout: options.DeleteTimeout,
+
+		retryConnect:         options.RetryConnect,
+		retryConnectTimeout:  durationOrDefault(options.RetryConnectTimeout, DefaultRetryConnectTimeout),
+		retryConnectInterval: durationOrDefault(options.RetryConnectInterval, DefaultRetryConnectInterval),
+		logger:               options.Logger,
+
+		tracer: newTracer(options),
+	}
+}
+
+func durationOrDefault(d, fallback time.Duration) time.Duration {
+	if d == 0 {
+		return fallback
+	}
+
+	return d
+}
+
+// newClient creates a cluster, Sentinel-backed failover or single node client as configured.
+func newClient(options *Options) redis.UniversalClient {
+	switch {
+	case len(options.ClusterAddrs) > 0:
+		return redis.NewClusterClient(
+			&redis.ClusterOptions{
+				Addrs:                 options.ClusterAddrs,
+				Username:              options.Username,
+				Password:              options.Password,
+				TLSConfig:             options.TLSConfig,
+				MaxRetries:            clientMaxRetries(options),
+				ContextTimeoutEnabled: true,
+			},
+		)
+	case len(options.SentinelAddrs) > 0:
+		return redis.NewFailoverClient(
+			&redis.FailoverOptions{
+				MasterName:            options.MasterName,
+				SentinelAddrs:         options.SentinelAddrs,
+				Username:              options.Username,
+				Password:              options.Password,
+				DB:                    options.Database,
+				TLSConfig:             options.TLSConfig,
+				MaxRetries:            clientMaxRetries(options),
+				ContextTimeoutEnabled: true,
+			},
+		)
+	default:
+		return redis.NewClient(
+			&redis.Options{
+				Addr:      options.DSN,
+				Username:  options.Username,
+				Password:  options.Password, // Set password if required
+				DB:        options.Database, // Use default database
+				TLSConfig: options.TLSConfig,
+				// The retry hook replaces the retries of the client when RetryCount is set.
+				MaxRetries: clientMaxRetries(options),
+				// Respect context deadlines, which carry the per-operation timeouts.
+				ContextTimeoutEnabled: true,
+			},
+		)
+	}
+}
+
+// clientMaxRetries disables the retries of the Redis client when the driver retries on its own.
+func clientMaxRetries(options *Options) int {
+	if options.RetryCount > 0 {
+		return -1
+	}
+
+	return 0
+}
+
+// addr describes the configured servers for BackendInfo.
+func (o *Options) addr() string {
+	switch {
+	case len(o.ClusterAddrs) > 0:
+		return strings.Join(o.ClusterAddrs, ",")
+	case len(o.SentinelAddrs) > 0:
+		return o.MasterName + "@" + strings.Join(o.SentinelAddrs, ",")
+	default:
+		return o.DSN
 	}
 }
 
 func (d *redisDriver) Name() string {
-	return "cache"
+	return cachemar.RedisCacherName.String()
+}
+
+// BackendInfo reports the Redis server version and uptime using INFO server.
+func (d *redisDriver) BackendInfo(ctx context.Context) (cachemar.BackendInfo, error) {
+	info := cachemar.BackendInfo{
+		Type:     cachemar.RedisCacherName.String(),
+		Addr:     d.addr,
+		Database: d.database,
+	}
+
+	server, err := d.client.Info(ctx, "server").Result()
+	if err != nil {
+		return info, wrapError("BackendInfo", "", err)
+	}
+
+	for _, line := range strings.Split(server, "\n") {
+		name, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+
+		switch name {
+		case "redis_version":
+			info.Version = value
+		case "uptime_in_seconds":
+			info.UptimeSeconds, _ = strconv.ParseInt(value, 10, 64)
+		}
+	}
+
+	return info, nil
 }
 
-func (d *redisDriver) Init() error {
-	statusCmd := d.client.Ping(context.Background())
+// Init checks the connection to Redis. With RetryConnect it keeps pinging until Redis is ready or
+// RetryConnectTimeout elapses.
+func (d *redisDriver) Init(ctx context.Context) error {
+	if d.retryConnect {
+		return cachemar.WaitReady(ctx, d, d.retryConnectTimeout, d.retryConnectInterval, d.logger)
+	}
+
+	statusCmd := d.client.Ping(ctx)
 	if err := statusCmd.Err(); err != nil {
 		return err
 	}
@@ -70,200 +350,1069 @@ func (d *redisDriver) Stop() error {
 }
 
 func (d *redisDriver) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
+	return d.SetOpts(ctx, key, value, cachemar.SetOptions{TTL: ttl, Tags: tags})
+}
+
+// SetOpts stores the value as described by the options, with SET NX or SET XX if they are conditional.
+func (d *redisDriver) SetOpts(ctx context.Context, key string, value interface{}, opts cachemar.SetOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	if opts.Conditional() {
+		var written bool
+		var err error
+		if opts.IfAbsent {
+			written, err = d.SetIfAbsent(ctx, key, value, opts.TTL, opts.Tags)
+		} else {
+			written, err = d.SetIfPresent(ctx, key, value, opts.TTL, opts.Tags)
+		}
+		if err == nil && !written {
+			return cachemar.ErrNotStored
+		}
+		return err
+	}
+
+	ttl, tags := opts.TTL, opts.Tags
+	ctx, cancel := withTimeout(ctx, d.setTimeout)
+	defer cancel()
+
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	data, err := json.Marshal(value)
+	finalKey := d.keyWithPrefix(key)
+	data, err := d.encode(finalKey, value)
 	if err != nil {
-		return fmt.Errorf("failed to serialize value: %v", err)
+		return err
 	}
 
-	finalKey := d.keyWithPrefix(key)
+	err = d.cmd(ctx).Set(ctx, finalKey, data, ttl).Err()
+	if err != nil {
+		return wrapError("Set", finalKey, err)
+	}
 
-	// Optionally compress the data using Gzip if compression is enabled
+	return d.addTags(ctx, finalKey, tags, ttl)
+}
+
+// encode serializes the value and compresses it if compression is enabled.
+func (d *redisDriver) encode(finalKey string, value interface{}) ([]byte, error) {
+	data, err := d.codec.Marshal(value)
+	if err != nil {
+		return nil, wrapError("Serialize", finalKey, err)
+	}
+
+	return d.compressEncoded(finalKey, data)
+}
+
+// compressEncoded compresses the serialized data if compression is enabled.
+func (d *redisDriver) compressEncoded(finalKey string, data []byte) ([]byte, error) {
 	if d.compress {
-		compressedData, err := compressData(data)
+		compressedData, err := compressData(d.algo, data)
 		if err != nil {
-			return fmt.Errorf("failed to compress data: %v", err)
+			return nil, wrapError("Compress", finalKey, err)
 		}
 		data = compressedData
 	}
 
-	err = d.client.Set(ctx, finalKey, data, ttl).Err()
+	return data, nil
+}
+
+// MSet stores the items and their tags in a single MULTI/EXEC transaction, or a plain pipeline in cluster
+// mode, where a transaction can't span hash slots. Within Transaction the commands join the running one.
+func (d *redisDriver) MSet(ctx context.Context, items []cachemar.CacheItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	ctx, cancel := withTimeout(ctx, d.setTimeout)
+	defer cancel()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	pipe, exec := d.writePipeline(ctx)
+
+	errs := make(cachemar.MultiError, len(items))
+	finalKeys := make([]string, 0, len(items))
+	for i, item := range items {
+		finalKey := d.keyWithPrefix(item.Key)
+		data, err := d.encode(finalKey, item.Value)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		pipe.Set(ctx, finalKey, data, item.TTL)
+		_ = d.addTagsWith(ctx, pipe, finalKey, item.Tags, item.TTL)
+		finalKeys = append(finalKeys, finalKey)
+	}
+
+	if err := exec(); err != nil {
+		return wrapError("MSet", strings.Join(finalKeys, ","), err)
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// SetIfAbsent stores the value with SET NX. In a transaction the command is only queued, so false is returned.
+func (d *redisDriver) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	return d.setIf(ctx, "SetIfAbsent", key, value, ttl, tags, redis.Cmdable.SetNX)
+}
+
+// SetNX stores the value with SET NX under the prefixed key. Tags are not supported for NX operations.
+func (d *redisDriver) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return d.setIf(ctx, "SetNX", key, value, ttl, nil, redis.Cmdable.SetNX)
+}
+
+// SetIfPresent stores the value with SET XX. In a transaction the command is only queued, so false is returned.
+func (d *redisDriver) SetIfPresent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	return d.setIf(ctx, "SetIfPresent", key, value, ttl, tags, redis.Cmdable.SetXX)
+}
+
+func (d *redisDriver) setIf(
+	ctx context.Context, operation, key string, value interface{}, ttl time.Duration, tags []string,
+	set func(c redis.Cmdable, ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd,
+) (bool, error) {
+	ctx, cancel := withTimeout(ctx, d.setTimeout)
+	defer cancel()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	finalKey := d.keyWithPrefix(key)
+	data, err := d.encode(finalKey, value)
+	if err != nil {
+		return false, err
+	}
+
+	written, err := set(d.cmd(ctx), ctx, finalKey, data, ttl).Result()
 	if err != nil {
-		return fmt.Errorf("failed to set key-value pair in Redis: %v", err)
+		return false, wrapError(operation, finalKey, err)
+	}
+	if !written {
+		return false, nil
 	}
 
-	if len(tags) > 0 {
-		for _, tag := range tags {
-			keyForTags := getTagKey(tag)
+	return true, d.addTags(ctx, finalKey, tags, ttl)
+}
 
-			err = d.client.SAdd(ctx, keyForTags, finalKey).Err()
-			if err != nil {
-				return fmt.Errorf("failed to add key to tag: %v", err)
-			}
+// addTags associates the stored key with the tags.
+func (d *redisDriver) addTags(ctx context.Context, finalKey string, tags []string, ttl time.Duration) error {
+	return d.addTagsWith(ctx, d.cmd(ctx), finalKey, tags, ttl)
+}
+
+// addTagsWith associates the stored key with the tags using c, which may be a pipeline. The tag sets expire
+// with the key; without a positive ttl their expiry is left unchanged, as EXPIRE 0 would delete them.
+func (d *redisDriver) addTagsWith(ctx context.Context, c redis.Cmdable, finalKey string, tags []string, ttl time.Duration) error {
+	if len(tags) == 0 {
+		return nil
+	}
 
-			err = d.client.Expire(ctx, keyForTags, ttl).Err()
+	for _, tag := range tags {
+		keyForTags := getTagKey(tag)
+
+		err := c.SAdd(ctx, keyForTags, finalKey).Err()
+		if err != nil {
+			return wrapError("AddTags", finalKey, err)
+		}
+
+		if ttl > 0 {
+			err = c.Expire(ctx, keyForTags, ttl).Err()
 			if err != nil {
-				return fmt.Errorf("failed to set tag expiration: %v", err)
+				return wrapError("AddTags", finalKey, err)
 			}
 		}
 	}
 
+	// Remember the tags of the key so that Remove can clean up the tag sets.
+	keyTags := getKeyTagsKey(finalKey)
+	err := c.SAdd(ctx, keyTags, tags).Err()
+	if err != nil {
+		return wrapError("AddTags", finalKey, err)
+	}
+
+	if ttl > 0 {
+		err = c.Expire(ctx, keyTags, ttl).Err()
+		if err != nil {
+			return wrapError("AddTags", finalKey, err)
+		}
+	}
+
 	return nil
 }
 
-func compressData(data []byte) ([]byte, error) {
-	var buf bytes.Buffer
-	gz := gzip.NewWriter(&buf)
-	if _, err := gz.Write(data); err != nil {
-		return nil, err
+// SetRaw stores the bytes without serializing or compressing them.
+func (d *redisDriver) SetRaw(ctx context.Context, key string, value []byte, ttl time.Duration, tags []string) error {
+	ctx, cancel := withTimeout(ctx, d.setTimeout)
+	defer cancel()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	finalKey := d.keyWithPrefix(key)
+
+	err := d.cmd(ctx).Set(ctx, finalKey, cachemar.EncodeRaw(value), ttl).Err()
+	if err != nil {
+		return wrapError("SetRaw", finalKey, err)
+	}
+
+	return d.addTags(ctx, finalKey, tags, ttl)
+}
+
+// GetRaw retrieves the bytes stored by SetRaw, and other values as decompressed JSON.
+func (d *redisDriver) GetRaw(ctx context.Context, key string) ([]byte, error) {
+	data, _, err := d.getUndecoded(ctx, "GetRaw", key)
+	return data, err
+}
+
+// getUndecoded retrieves the bytes stored by SetRaw, reporting true, or else the serialized value, decompressed.
+func (d *redisDriver) getUndecoded(ctx context.Context, operation, key string) ([]byte, bool, error) {
+	ctx, cancel := withTimeout(ctx, d.getTimeout)
+	defer cancel()
+
+	data, err := d.client.Get(ctx, d.keyWithPrefix(key)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, false, cachemar.ErrNotFound
+		}
+		return nil, false, wrapError(operation, key, err)
 	}
-	if err := gz.Close(); err != nil {
+
+	if raw, ok := cachemar.DecodeRaw(data); ok {
+		return raw, true, nil
+	}
+
+	if _, compressed := detectCompression(data); compressed {
+		data, err = decompressData(data)
+		if err != nil {
+			return nil, false, wrapError(cachemar.OperationDecompress, key, err)
+		}
+	}
+
+	return data, false, nil
+}
+
+// Format reports the codec, see cachemar.EncodedCacher.
+func (d *redisDriver) Format() string {
+	return cachemar.CodecFormat(d.codec)
+}
+
+// GetEncoded retrieves the value as serialized by the codec, decompressed. Values stored by SetRaw are
+// reported with cachemar.ErrRawValue.
+func (d *redisDriver) GetEncoded(ctx context.Context, key string) ([]byte, error) {
+	data, raw, err := d.getUndecoded(ctx, "GetEncoded", key)
+	if err != nil {
 		return nil, err
 	}
-	return buf.Bytes(), nil
+	if raw {
+		return nil, cachemar.ErrRawValue
+	}
+
+	return data, nil
+}
+
+// SetEncoded stores the data serialized by a codec of the same Format as if its value was given to Set.
+func (d *redisDriver) SetEncoded(ctx context.Context, key string, data []byte, ttl time.Duration, tags []string) error {
+	ctx, cancel := withTimeout(ctx, d.setTimeout)
+	defer cancel()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	finalKey := d.keyWithPrefix(key)
+	data, err := d.compressEncoded(finalKey, data)
+	if err != nil {
+		return err
+	}
+
+	err = d.cmd(ctx).Set(ctx, finalKey, data, ttl).Err()
+	if err != nil {
+		return wrapError("SetEncoded", finalKey, err)
+	}
+
+	return d.addTags(ctx, finalKey, tags, ttl)
+}
+
+// SetStream stores the content read from r as-is, compressing it if compression is enabled.
+func (d *redisDriver) SetStream(ctx context.Context, key string, r io.Reader, ttl time.Duration, tags []string) error {
+	ctx, cancel := withTimeout(ctx, d.setTimeout)
+	defer cancel()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var buf bytes.Buffer
+	if d.compress {
+		w, err := newCompressWriter(d.algo, &buf)
+		if err != nil {
+			return wrapError("Compress", key, err)
+		}
+		if _, err := io.Copy(w, r); err != nil {
+			return wrapError("Compress", key, err)
+		}
+		if err := w.Close(); err != nil {
+			return wrapError("Compress", key, err)
+		}
+	} else if _, err := buf.ReadFrom(r); err != nil {
+		return wrapError("ReadStream", key, err)
+	}
+
+	finalKey := d.keyWithPrefix(key)
+
+	err := d.client.Set(ctx, finalKey, buf.Bytes(), ttl).Err()
+	if err != nil {
+		return wrapError("SetStream", finalKey, err)
+	}
+
+	return d.addTags(ctx, finalKey, tags, ttl)
+}
+
+// GetStream returns a reader over the content stored by SetStream, decompressing it if needed.
+func (d *redisDriver) GetStream(ctx context.Context, key string) (io.ReadCloser, error) {
+	ctx, cancel := withTimeout(ctx, d.getTimeout)
+	defer cancel()
+
+	finalKey := d.keyWithPrefix(key)
+
+	data, err := d.client.Get(ctx, finalKey).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, wrapError("GetStream", finalKey, cachemar.ErrNotFound)
+		}
+		return nil, wrapError("GetStream", finalKey, err)
+	}
+
+	if _, compressed := detectCompression(data); compressed {
+		r, err := newDecompressReader(data)
+		if err != nil {
+			return nil, wrapError(cachemar.OperationDecompress, finalKey, err)
+		}
+		return r, nil
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), nil
 }
 
 func (c *redisDriver) Get(ctx context.Context, key string, value interface{}) error {
+	ctx, cancel := withTimeout(ctx, c.getTimeout)
+	defer cancel()
+
 	finalKey := c.keyWithPrefix(key)
 
-	data, err := c.client.Get(ctx, finalKey).Bytes()
+	cmd := c.cmd(ctx).Get(ctx, finalKey)
+	if c.inTransaction(ctx) {
+		// The value is only known once the transaction is executed.
+		return nil
+	}
+
+	data, err := cmd.Bytes()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return fmt.Errorf("key not found: %s", finalKey)
+			return wrapError("Get", finalKey, cachemar.ErrNotFound)
 		}
-		return fmt.Errorf("failed to get value from Redis: %v", err)
+		return wrapError("Get", finalKey, err)
 	}
 
-	// Check if the data is compressed
-	isCompressed := false
-	if len(data) > 2 {
-		if data[0] == 0x1f && data[1] == 0x8b {
-			isCompressed = true
+	return c.decode(data, value)
+}
+
+// GetWithMetadata retrieves the value together with its remaining time-to-live in a single round trip.
+func (d *redisDriver) GetWithMetadata(ctx context.Context, key string, value interface{}) (cachemar.Metadata, error) {
+	ttl, err := d.getWithPTTL(ctx, "GetWithMetadata", key, value)
+	if err != nil {
+		return cachemar.Metadata{}, err
+	}
+
+	return cachemar.Metadata{TTLRemaining: ttl}, nil
+}
+
+// GetWithTTL retrieves the value together with its remaining time-to-live in a single round trip.
+func (d *redisDriver) GetWithTTL(ctx context.Context, key string, value interface{}) (time.Duration, error) {
+	ttl, err := d.getWithPTTL(ctx, "GetWithTTL", key, value)
+	if err != nil {
+		return 0, err
+	}
+
+	return remainingTTL(ttl), nil
+}
+
+// remainingTTL turns the result of PTTL for an existing key into the TTL returned by GetWithTTL.
+func remainingTTL(pttl time.Duration) time.Duration {
+	switch {
+	case pttl == -1:
+		return cachemar.NoExpiry
+	case pttl < 0:
+		// The key expired between GET and PTTL.
+		return 0
+	default:
+		return pttl
+	}
+}
+
+// getWithPTTL decodes the value and returns the result of PTTL for it, -1 if it does not expire.
+func (d *redisDriver) getWithPTTL(ctx context.Context, operation, key string, value interface{}) (time.Duration, error) {
+	ctx, cancel := withTimeout(ctx, d.getTimeout)
+	defer cancel()
+
+	finalKey := d.keyWithPrefix(key)
+
+	pipe := d.client.Pipeline()
+	getCmd := pipe.Get(ctx, finalKey)
+	ttlCmd := pipe.PTTL(ctx, finalKey)
+	_, _ = pipe.Exec(ctx)
+
+	data, err := getCmd.Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, wrapError(operation, finalKey, cachemar.ErrNotFound)
 		}
+		return 0, wrapError(operation, finalKey, err)
+	}
+
+	ttl, err := ttlCmd.Result()
+	if err != nil {
+		return 0, wrapError(operation, finalKey, err)
+	}
+
+	return ttl, d.decode(data, value)
+}
+
+// Inspect dumps the stored value, its TTL and its tags in a single round trip.
+func (d *redisDriver) Inspect(ctx context.Context, key string) (*cachemar.InspectResult, error) {
+	ctx, cancel := withTimeout(ctx, d.getTimeout)
+	defer cancel()
+
+	finalKey := d.keyWithPrefix(key)
+
+	pipe := d.client.Pipeline()
+	getCmd := pipe.Get(ctx, finalKey)
+	ttlCmd := pipe.PTTL(ctx, finalKey)
+	tagsCmd := pipe.SMembers(ctx, getKeyTagsKey(finalKey))
+	_, _ = pipe.Exec(ctx)
+
+	data, err := getCmd.Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, wrapError("Inspect", finalKey, cachemar.ErrNotFound)
+		}
+		return nil, wrapError("Inspect", finalKey, err)
+	}
+
+	ttl, err := ttlCmd.Result()
+	if err != nil {
+		return nil, wrapError("Inspect", finalKey, err)
+	}
+
+	tags, err := tagsCmd.Result()
+	if err != nil {
+		return nil, wrapError("Inspect", finalKey, err)
 	}
 
-	if isCompressed {
+	_, compressed := detectCompression(data)
+	result := &cachemar.InspectResult{
+		Key:          finalKey,
+		RawBytes:     data,
+		TTLRemaining: ttl,
+		Tags:         tags,
+		Driver:       d.Name(),
+		Compressed:   compressed,
+		SizeBytes:    int64(len(data)),
+	}
+	if err := d.decode(data, &result.DecodedValue); err != nil {
+		result.DecodedValue = nil
+	}
+
+	return result, nil
+}
+
+// decode decompresses and deserializes the stored data into value.
+func (d *redisDriver) decode(data []byte, value interface{}) error {
+	if raw, ok := cachemar.DecodeRaw(data); ok {
+		return cachemar.AssignRaw(raw, value)
+	}
+
+	// Check if the data is compressed
+	if _, compressed := detectCompression(data); compressed {
+		var err error
 		data, err = decompressData(data)
 		if err != nil {
-			return fmt.Errorf("failed to decompress data: %v", err)
+			return wrapError(cachemar.OperationDecompress, "", err)
 		}
 	}
 
-	err = json.Unmarshal(data, value)
+	err := d.codec.Unmarshal(data, value)
+	if err != nil {
+		return wrapError(cachemar.OperationDeserialize, "", err)
+	}
+
+	return nil
+}
+
+// removeFromTagScript removes a key from a tag set and deletes the set once it is empty.
+var removeFromTagScript = redis.NewScript(`
+redis.call('SREM', KEYS[1], ARGV[1])
+if redis.call('SCARD', KEYS[1]) == 0 then
+	redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+func (d *redisDriver) Remove(ctx context.Context, key string) error {
+	ctx, cancel := withTimeout(ctx, d.deleteTimeout)
+	defer cancel()
+
+	finalKey := d.keyWithPrefix(key)
+	if err := d.removeFromTags(ctx, "Remove", finalKey); err != nil {
+		return err
+	}
+
+	err := d.cmd(ctx).Del(ctx, finalKey, getKeyTagsKey(finalKey)).Err()
 	if err != nil {
-		return fmt.Errorf("failed to deserialize value: %v", err)
+		return wrapError("Remove", finalKey, err)
 	}
 
 	return nil
 }
 
-func decompressData(compressedData []byte) ([]byte, error) {
-	gz, err := gzip.NewReader(bytes.NewReader(compressedData))
+// removeFromTags removes the key from the sets of its tags, leaving its own set of tags in place.
+func (d *redisDriver) removeFromTags(ctx context.Context, operation, finalKey string) error {
+	return d.removeFromTagsWith(ctx, d.cmd(ctx), operation, finalKey)
+}
+
+// removeFromTagsWith removes the key from the sets of all its tags with c, which may be a transaction.
+func (d *redisDriver) removeFromTagsWith(ctx context.Context, c redis.Cmdable, operation, finalKey string) error {
+	// In a transaction the tags are still read right away, as the removal depends on them.
+	tags, err := d.client.SMembers(ctx, getKeyTagsKey(finalKey)).Result()
 	if err != nil {
-		return nil, err
+		return wrapError(operation, finalKey, err)
 	}
-	defer gz.Close()
 
-	var buf bytes.Buffer
-	if _, err := io.Copy(&buf, gz); err != nil {
+	_, inTransaction := c.(redis.Pipeliner)
+	for _, tag := range tags {
+		if inTransaction {
+			// EVALSHA can't fall back to EVAL within a transaction.
+			err = removeFromTagScript.Eval(ctx, c, []string{getTagKey(tag)}, finalKey).Err()
+		} else {
+			err = removeFromTagScript.Run(ctx, c, []string{getTagKey(tag)}, finalKey).Err()
+		}
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return wrapError(operation, finalKey, err)
+		}
+	}
+
+	return nil
+}
+
+// MGet retrieves the values with a single MGET. In cluster mode, where MGET can't span hash slots, the keys
+// are read with a pipeline of GETs instead.
+func (d *redisDriver) MGet(ctx context.Context, keys []string, values []interface{}) error {
+	if len(keys) != len(values) {
+		return fmt.Errorf("redis: got %d keys and %d values", len(keys), len(values))
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	ctx, cancel := withTimeout(ctx, d.getTimeout)
+	defer cancel()
+
+	finalKeys := make([]string, len(keys))
+	for i, key := range keys {
+		finalKeys[i] = d.keyWithPrefix(key)
+	}
+
+	results, err := d.mget(ctx, finalKeys)
+	if err != nil {
+		return wrapError("MGet", strings.Join(finalKeys, ","), err)
+	}
+
+	errs := make(cachemar.MultiError, len(keys))
+	for i, result := range results {
+		data, ok := result.(string)
+		if !ok {
+			errs[i] = wrapError("MGet", finalKeys[i], cachemar.ErrNotFound)
+			continue
+		}
+
+		errs[i] = d.decode([]byte(data), values[i])
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// MGetWithTTL retrieves the values together with their remaining time-to-live in a single round trip.
+func (d *redisDriver) MGetWithTTL(ctx context.Context, keys []string, values []interface{}) ([]time.Duration, error) {
+	if len(keys) != len(values) {
+		return nil, fmt.Errorf("redis: got %d keys and %d values", len(keys), len(values))
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := withTimeout(ctx, d.getTimeout)
+	defer cancel()
+
+	finalKeys := make([]string, len(keys))
+	getCmds := make([]*redis.StringCmd, len(keys))
+	ttlCmds := make([]*redis.DurationCmd, len(keys))
+
+	pipe := d.client.Pipeline()
+	for i, key := range keys {
+		finalKeys[i] = d.keyWithPrefix(key)
+		getCmds[i] = pipe.Get(ctx, finalKeys[i])
+		ttlCmds[i] = pipe.PTTL(ctx, finalKeys[i])
+	}
+	_, _ = pipe.Exec(ctx)
+
+	ttls := make([]time.Duration, len(keys))
+	errs := make(cachemar.MultiError, len(keys))
+	for i, getCmd := range getCmds {
+		data, err := getCmd.Bytes()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				err = cachemar.ErrNotFound
+			}
+			errs[i] = wrapError("MGetWithTTL", finalKeys[i], err)
+			continue
+		}
+
+		ttl, err := ttlCmds[i].Result()
+		if err != nil {
+			errs[i] = wrapError("MGetWithTTL", finalKeys[i], err)
+			continue
+		}
+
+		ttls[i] = remainingTTL(ttl)
+		errs[i] = d.decode(data, values[i])
+	}
+
+	return ttls, errs.ErrorOrNil()
+}
+
+// mget returns the values of the keys, nil for missing ones.
+func (d *redisDriver) mget(ctx context.Context, finalKeys []string) ([]interface{}, error) {
+	if !d.isCluster() {
+		return d.client.MGet(ctx, finalKeys...).Result()
+	}
+
+	pipe := d.client.Pipeline()
+	cmds := make([]*redis.StringCmd, len(finalKeys))
+	for i, finalKey := range finalKeys {
+		cmds[i] = pipe.Get(ctx, finalKey)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
 		return nil, err
 	}
 
-	return buf.Bytes(), nil
+	results := make([]interface{}, len(finalKeys))
+	for i, cmd := range cmds {
+		if data, err := cmd.Result(); err == nil {
+			results[i] = data
+		}
+	}
+
+	return results, nil
 }
 
-func (d *redisDriver) Remove(ctx context.Context, key string) error {
+// GetAndDelete retrieves and removes the value atomically with GETDEL, which requires Redis 6.2 or newer.
+// The key is then removed from its tags.
+func (d *redisDriver) GetAndDelete(ctx context.Context, key string, value interface{}) error {
+	ctx, cancel := withTimeout(ctx, d.deleteTimeout)
+	defer cancel()
+
+	finalKey := d.keyWithPrefix(key)
+
+	data, err := d.client.GetDel(ctx, finalKey).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return wrapError("GetAndDelete", finalKey, cachemar.ErrNotFound)
+		}
+		return wrapError("GetAndDelete", finalKey, err)
+	}
+
+	if err := d.removeFromTags(ctx, "GetAndDelete", finalKey); err != nil {
+		return err
+	}
+	if err := d.client.Del(ctx, getKeyTagsKey(finalKey)).Err(); err != nil {
+		return wrapError("GetAndDelete", finalKey, err)
+	}
+
+	return d.decode(data, value)
+}
+
+// Touch resets the expiry of the key with EXPIRE, together with the set of its tags.
+func (d *redisDriver) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	ctx, cancel := withTimeout(ctx, d.setTimeout)
+	defer cancel()
+
 	finalKey := d.keyWithPrefix(key)
 
-	err := d.client.Del(ctx, finalKey).Err()
+	touched, err := d.client.Expire(ctx, finalKey, ttl).Result()
 	if err != nil {
-		return fmt.Errorf("failed to remove key from Redis: %v", err)
+		return wrapError("Touch", finalKey, err)
+	}
+	if !touched {
+		return wrapError("Touch", finalKey, cachemar.ErrNotFound)
+	}
+
+	if err := d.client.Expire(ctx, getKeyTagsKey(finalKey), ttl).Err(); err != nil {
+		return wrapError("Touch", finalKey, err)
 	}
 
 	return nil
 }
 
 func (d *redisDriver) RemoveByTag(ctx context.Context, tag string) error {
+	_, err := d.removeByTagTraced(ctx, tag)
+	return err
+}
+
+// removeByTagTraced removes the keys of a tag within its own span and returns how many were removed.
+func (d *redisDriver) removeByTagTraced(ctx context.Context, tag string) (int, error) {
+	ctx, finish := d.startSpan(ctx, "cachemar.RemoveByTag", attribute.String("tag", tag))
+
+	removed, err := d.removeByTag(ctx, tag)
+	finish(removed, err)
+
+	return removed, err
+}
+
+func (d *redisDriver) removeByTag(ctx context.Context, tag string) (int, error) {
+	ctx, cancel := withTimeout(ctx, d.deleteTimeout)
+	defer cancel()
+
 	keyForTags := getTagKey(tag)
 
 	keys, err := d.client.SMembers(ctx, keyForTags).Result()
 	if err != nil {
-		return fmt.Errorf("failed to get keys associated with tag: %v", err)
+		return 0, wrapError("RemoveByTag", tag, err)
 	}
 
-	for _, key := range keys {
-		err := d.client.Del(ctx, key).Err()
+	// RemoveByTag runs outside of transactions, so the keys are removed from their other tags right away.
+	for i, key := range keys {
+		if err := d.removeFromTagsWith(ctx, d.client, "RemoveByTag", key); err != nil {
+			return i, err
+		}
+
+		err := d.client.Del(ctx, key, getKeyTagsKey(key)).Err()
 		if err != nil {
-			return fmt.Errorf("failed to remove key from Redis: %v", err)
+			return i, wrapError("RemoveByTag", tag, err)
 		}
 	}
 
 	err = d.client.Del(ctx, keyForTags).Err()
 	if err != nil {
-		return fmt.Errorf("failed to remove tag from Redis: %v", err)
+		return len(keys), wrapError("RemoveByTag", tag, err)
 	}
 
-	return nil
+	return len(keys), nil
 }
 func (d *redisDriver) Exists(ctx context.Context, key string) (bool, error) {
+	ctx, cancel := withTimeout(ctx, d.getTimeout)
+	defer cancel()
+
 	finalKey := d.keyWithPrefix(key)
 
 	cmd := d.client.Exists(ctx, finalKey)
 	if err := cmd.Err(); err != nil {
-		return false, fmt.Errorf("failed to check key existence in Redis: %v", err)
+		return false, wrapError("Exists", finalKey, err)
 	}
 	return cmd.Val() > 0, nil
 }
 
 func (d *redisDriver) Increment(ctx context.Context, key string) error {
+	ctx, cancel := withTimeout(ctx, d.setTimeout)
+	defer cancel()
+
 	finalKey := d.keyWithPrefix(key)
 
-	cmd := d.client.Incr(ctx, finalKey)
+	cmd := d.cmd(ctx).Incr(ctx, finalKey)
 	if err := cmd.Err(); err != nil {
-		return fmt.Errorf("failed to increment key value in Redis: %v", err)
+		return wrapError("Increment", finalKey, err)
 	}
 	return nil
 }
 
 func (d *redisDriver) Decrement(ctx context.Context, key string) error {
+	ctx, cancel := withTimeout(ctx, d.setTimeout)
+	defer cancel()
+
 	finalKey := d.keyWithPrefix(key)
 
-	cmd := d.client.Decr(ctx, finalKey)
+	cmd := d.cmd(ctx).Decr(ctx, finalKey)
 	if err := cmd.Err(); err != nil {
-		return fmt.Errorf("failed to decrement key value in Redis: %v", err)
+		return wrapError("Decrement", finalKey, err)
 	}
 	return nil
 }
 
+func (d *redisDriver) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	ctx, cancel := withTimeout(ctx, d.setTimeout)
+	defer cancel()
+
+	finalKey := d.keyWithPrefix(key)
+
+	value, err := d.cmd(ctx).IncrBy(ctx, finalKey, delta).Result()
+	if err != nil {
+		return 0, wrapIntError("IncrBy", finalKey, err)
+	}
+	return value, nil
+}
+
+func (d *redisDriver) DecrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	ctx, cancel := withTimeout(ctx, d.setTimeout)
+	defer cancel()
+
+	finalKey := d.keyWithPrefix(key)
+
+	value, err := d.cmd(ctx).DecrBy(ctx, finalKey, delta).Result()
+	if err != nil {
+		return 0, wrapIntError("DecrBy", finalKey, err)
+	}
+	return value, nil
+}
+
+// wrapIntError wraps an error of INCRBY or DECRBY, reporting a value that is not an integer as ErrInvalidType.
+func wrapIntError(operation, key string, err error) error {
+	if strings.Contains(err.Error(), "not an integer") {
+		return wrapError(operation, key, cachemar.ErrInvalidType)
+	}
+	return wrapError(operation, key, err)
+}
+
+func (d *redisDriver) IncrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	ctx, cancel := withTimeout(ctx, d.setTimeout)
+	defer cancel()
+
+	finalKey := d.keyWithPrefix(key)
+
+	value, err := d.client.IncrByFloat(ctx, finalKey, delta).Result()
+	if err != nil {
+		if strings.Contains(err.Error(), "not a valid float") {
+			return 0, wrapError("IncrementFloat", finalKey, cachemar.ErrInvalidType)
+		}
+		return 0, wrapError("IncrementFloat", finalKey, err)
+	}
+	return value, nil
+}
+
+func (d *redisDriver) DecrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	return d.IncrementFloat(ctx, key, -delta)
+}
+
 func (d *redisDriver) GetKeysByTag(ctx context.Context, tag string) ([]string, error) {
+	ctx, finish := d.startSpan(ctx, "cachemar.GetKeysByTag", attribute.String("tag", tag))
+
+	ctx, cancel := withTimeout(ctx, d.getTimeout)
+	defer cancel()
+
 	keyForTags := getTagKey(tag)
 
 	cmd := d.client.SMembers(ctx, keyForTags)
 	if err := cmd.Err(); err != nil {
-		return nil, fmt.Errorf("failed to get keys associated with tag: %v", err)
+		err = wrapError("GetKeysByTag", tag, err)
+		finish(0, err)
+		return nil, err
 	}
+
+	finish(len(cmd.Val()), nil)
 	return cmd.Val(), nil
 }
 
 func (d *redisDriver) RemoveByTags(ctx context.Context, tags []string) error {
+	ctx, finish := d.startSpan(ctx, "cachemar.RemoveByTags", attribute.StringSlice("tags", tags))
+
+	ctx, cancel := withTimeout(ctx, d.deleteTimeout)
+	defer cancel()
+
+	total := 0
 	for _, tag := range tags {
-		err := d.RemoveByTag(ctx, tag)
+		removed, err := d.removeByTagTraced(ctx, tag)
+		total += removed
+		if err != nil {
+			finish(total, err)
+			return err
+		}
+	}
+
+	finish(total, nil)
+	return nil
+}
+
+func (d *redisDriver) CountByTag(ctx context.Context, tag string) (int64, error) {
+	count, err := d.client.SCard(ctx, getTagKey(tag)).Result()
+	if err != nil {
+		return 0, wrapError("CountByTag", tag, err)
+	}
+
+	return count, nil
+}
+
+// ForceRemoveByTag removes the keys of a tag in batches using SSCAN and pipelined DEL,
+// avoiding a single SMEMBERS call on huge tags.
+func (d *redisDriver) ForceRemoveByTag(ctx context.Context, tag string, batchSize int) error {
+	ctx, cancel := withTimeout(ctx, d.deleteTimeout)
+	defer cancel()
+
+	keyForTags := getTagKey(tag)
+
+	var cursor uint64
+	for {
+		keys, next, err := d.client.SScan(ctx, keyForTags, cursor, "", int64(batchSize)).Result()
 		if err != nil {
-			return fmt.Errorf("failed to remove keys for tag: %v", err)
+			return wrapError("ForceRemoveByTag", tag, err)
 		}
+
+		if len(keys) > 0 {
+			pipe := d.client.Pipeline()
+			for _, key := range keys {
+				pipe.Del(ctx, key)
+			}
+			if _, err := pipe.Exec(ctx); err != nil {
+				return wrapError("ForceRemoveByTag", tag, err)
+			}
+		}
+
+		if next == 0 {
+			break
+		}
+		cursor = next
+	}
+
+	err := d.client.Del(ctx, keyForTags).Err()
+	if err != nil {
+		return wrapError("ForceRemoveByTag", tag, err)
 	}
 
 	return nil
 }
 
 func getTagKey(tag string) string {
-	return fmt.Sprintf("tag:%s", tag)
+	return keybuf.Join(":", "tag", tag)
+}
+
+func getKeyTagsKey(finalKey string) string {
+	return keybuf.Join(":", "keytags", finalKey)
+}
+
+// EffectiveKey returns the key as it is stored in Redis.
+func (d *redisDriver) EffectiveKey(key string) string {
+	return d.keyWithPrefix(key)
+}
+
+// withTimeout bounds the context by the timeout unless it already has a nearer deadline.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= timeout {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, timeout)
 }
 
 func (d *redisDriver) keyWithPrefix(key string) string {
-	return fmt.Sprintf("%s:%s", d.prefix, key)
+	return keybuf.Join(":", d.prefix, key)
+}
+
+// flushBatchSize is the COUNT hint of the SCAN calls of Flush.
+const flushBatchSize = 1000
+
+// Flush removes all keys of the driver. Without a prefix the whole database is flushed with FLUSHDB, on
+// every master in cluster mode. With a prefix only the keys under it are removed, found with SCAN, so
+// other users of the database are left alone.
+func (d *redisDriver) Flush(ctx context.Context) error {
+	ctx, cancel := withTimeout(ctx, d.deleteTimeout)
+	defer cancel()
+
+	return d.forEachMaster(ctx, d.flushNode)
+}
+
+func (d *redisDriver) isCluster() bool {
+	_, ok := d.client.(*redis.ClusterClient)
+	return ok
+}
+
+// forEachMaster runs fn on every master of a cluster, or on the client itself otherwise.
+func (d *redisDriver) forEachMaster(ctx context.Context, fn func(ctx context.Context, client redis.Cmdable) error) error {
+	if cluster, ok := d.client.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(
+			ctx, func(ctx context.Context, client *redis.Client) error {
+				return fn(ctx, client)
+			},
+		)
+	}
+
+	return fn(ctx, d.client)
+}
+
+func (d *redisDriver) flushNode(ctx context.Context, client redis.Cmdable) error {
+	if d.prefix == "" {
+		if err := client.FlushDB(ctx).Err(); err != nil {
+			return wrapError("Flush", "", err)
+		}
+		return nil
+	}
+
+	var cursor uint64
+	for {
+		keys, next, err := client.Scan(ctx, cursor, d.keyWithPrefix("*"), flushBatchSize).Result()
+		if err != nil {
+			return wrapError("Flush", "", err)
+		}
+
+		for _, finalKey := range keys {
+			if err := d.Remove(ctx, strings.TrimPrefix(finalKey, d.keyWithPrefix(""))); err != nil {
+				return err
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// scanBatchSize is the COUNT hint of the SCAN calls of Scan.
+const scanBatchSize = 100
+
+// Scan iterates over the keys matching the pattern under the prefix with SCAN, on every master in
+// cluster mode. The context is checked between pages. Keys may be reported more than once if they
+// are added or removed while scanning, as SCAN guarantees only that keys present throughout are found.
+func (d *redisDriver) Scan(ctx context.Context, pattern string, fn func(key string) error) error {
+	return d.forEachMaster(
+		ctx, func(ctx context.Context, client redis.Cmdable) error {
+			return d.scanNode(ctx, client, pattern, fn)
+		},
+	)
+}
+
+func (d *redisDriver) scanNode(ctx context.Context, client redis.Cmdable, pattern string, fn func(key string) error) error {
+	prefix := d.keyWithPrefix("")
+
+	var cursor uint64
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		keys, next, err := client.Scan(ctx, cursor, d.keyWithPrefix(pattern), scanBatchSize).Result()
+		if err != nil {
+			return wrapError("Scan", pattern, err)
+		}
+
+		for _, finalKey := range keys {
+			if err := fn(strings.TrimPrefix(finalKey, prefix)); err != nil {
+				return err
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
 }
 
 func (d *redisDriver) Close() error {
@@ -274,7 +1423,12 @@ func (d *redisDriver) Ping() error {
 	ctx := context.Background()
 	err := d.client.Ping(ctx).Err()
 	if err != nil {
-		return fmt.Errorf("failed to ping Redis: %v", err)
+		return wrapError("Ping", "", err)
 	}
 	return nil
 }
+
+// wrapError attaches the driver, operation and key to err.
+func wrapError(operation, key string, err error) error {
+	return &cachemar.DriverError{Driver: cachemar.RedisCacherName.String(), Operation: operation, Key: key, Cause: err}
+}
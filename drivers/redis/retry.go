@@ -0,0 +1,167 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultRetryBaseDelay is the delay before the first retry when RetryCount is set without RetryBaseDelay.
+const DefaultRetryBaseDelay = 50 * time.Millisecond
+
+// maxRetryShift caps the exponent of the backoff so that the delay can't overflow.
+const maxRetryShift = 16
+
+// retryableReplies are the prefixes of server errors that go away on their own, e.g. during a failover.
+var retryableReplies = []string{"LOADING", "READONLY", "MASTERDOWN", "TRYAGAIN", "CLUSTERDOWN"}
+
+// idempotentCommands are the commands that can be sent again after a connection error, which may have
+// happened after the server applied them. Reads are always safe; the writes listed here leave the same
+// state when applied twice. Counters, GETDEL, scripts and MULTI/EXEC are missing on purpose.
+var idempotentCommands = map[string]bool{
+	"ping": true, "info": true, "get": true, "mget": true, "exists": true, "ttl": true, "pttl": true,
+	"type": true, "strlen": true, "scan": true, "smembers": true, "sismember": true, "scard": true,
+	"sscan": true, "hget": true, "hmget": true, "hgetall": true, "hexists": true, "hlen": true,
+	"hscan": true, "zrange": true, "zrangebyscore": true, "zscore": true, "zcount": true, "zcard": true,
+	"xrange": true, "xread": true, "xlen": true,
+	"set": true, "mset": true, "del": true, "unlink": true, "expire": true, "pexpire": true,
+	"persist": true, "sadd": true, "srem": true, "hset": true, "hdel": true, "zadd": true, "zrem": true,
+}
+
+// conditionalSetArgs turn SET into a write whose result depends on the value it replaces.
+var conditionalSetArgs = map[string]bool{"nx": true, "xx": true, "get": true}
+
+// retryHook retries commands and pipelines that failed with a transient error, waiting
+// baseDelay * 2^attempt plus a random jitter of up to baseDelay between attempts.
+// It is added to the client as a hook, so it covers every operation of the driver.
+type retryHook struct {
+	count     int
+	baseDelay time.Duration
+}
+
+func newRetryHook(options *Options) *retryHook {
+	return &retryHook{
+		count:     options.RetryCount,
+		baseDelay: durationOrDefault(options.RetryBaseDelay, DefaultRetryBaseDelay),
+	}
+}
+
+func (h *retryHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *retryHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		return h.retry(ctx, isIdempotent(cmd), func() error { return next(ctx, cmd) })
+	}
+}
+
+func (h *retryHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		idempotent := true
+		for _, cmd := range cmds {
+			idempotent = idempotent && isIdempotent(cmd)
+		}
+
+		return h.retry(ctx, idempotent, func() error { return next(ctx, cmds) })
+	}
+}
+
+// retry runs op until it succeeds, fails with an error that is not transient, or the retries are used up.
+// Connection errors are retried only when op is idempotent, because the server may have applied it
+// before the reply got lost. Cancelling the context aborts the wait between attempts.
+func (h *retryHook) retry(ctx context.Context, idempotent bool, op func() error) error {
+	err := op()
+	for attempt := 0; attempt < h.count && isRetryable(err, idempotent); attempt++ {
+		timer := time.NewTimer(h.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		err = op()
+	}
+
+	return err
+}
+
+func (h *retryHook) backoff(attempt int) time.Duration {
+	if attempt > maxRetryShift {
+		attempt = maxRetryShift
+	}
+
+	return h.baseDelay<<attempt + time.Duration(rand.Int63n(int64(h.baseDelay)+1))
+}
+
+// isRetryable reports whether err is a transient network or server error. Failed dials and the server
+// replies in retryableReplies mean that nothing was applied, so they are always retryable. Other
+// connection errors are retryable only for idempotent operations. Misses, context errors and other
+// server replies are returned to the caller right away.
+func isRetryable(err error, idempotent bool) bool {
+	if err == nil || errors.Is(err, redis.Nil) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return idempotent
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return idempotent
+	}
+
+	var reply redis.Error
+	if errors.As(err, &reply) {
+		for _, prefix := range retryableReplies {
+			if strings.HasPrefix(reply.Error(), prefix) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isIdempotent reports whether cmd leaves the same state and reply when the server applies it twice.
+func isIdempotent(cmd redis.Cmder) bool {
+	name := strings.ToLower(cmd.Name())
+	if !idempotentCommands[name] {
+		return false
+	}
+
+	// The flags of SET follow the value; those of ZADD come before the first score.
+	args := cmd.Args()
+	switch {
+	case name == "set" && len(args) > 3:
+		for _, arg := range args[3:] {
+			if flag, ok := arg.(string); ok && conditionalSetArgs[strings.ToLower(flag)] {
+				return false
+			}
+		}
+	case name == "zadd" && len(args) > 2:
+		for _, arg := range args[2:] {
+			flag, ok := arg.(string)
+			if !ok {
+				break
+			}
+			if strings.EqualFold(flag, "incr") {
+				return false
+			}
+		}
+	}
+
+	return true
+}
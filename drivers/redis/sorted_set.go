@@ -0,0 +1,146 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+// ZMember is a member of a sorted set with its score.
+type ZMember struct {
+	Score  float64
+	Member string
+}
+
+// SortedSetCacher stores members ordered by score in a Redis sorted set, e.g. for leaderboards or sliding
+// window rate limits. Members are stored as is, without the codec. Like a hash, the sorted set is one
+// cache key for Remove, Touch, Exists and tags.
+type SortedSetCacher interface {
+	// ZAdd adds the members to the sorted set, or updates the scores of existing ones, and associates the
+	// whole set with the tags. A positive ttl resets the expiry of the whole set, otherwise the expiry is
+	// left unchanged. The members, the expiry and the tags are written in one MULTI/EXEC transaction.
+	ZAdd(ctx context.Context, key string, members []ZMember, ttl time.Duration, tags []string) error
+
+	// ZRange returns the members from rank start to stop, inclusive, ordered by ascending score.
+	// Negative ranks count from the highest score, so 0 and -1 return the whole set.
+	ZRange(ctx context.Context, key string, start, stop int64) ([]ZMember, error)
+
+	// ZScore returns the score of the member. ErrNotFound is returned if the set or the member does not exist.
+	ZScore(ctx context.Context, key, member string) (float64, error)
+
+	// ZRem removes the members from the sorted set. Missing members are ignored.
+	ZRem(ctx context.Context, key string, members ...string) error
+
+	// ZCount returns the number of members with a score between min and max. The bounds use the syntax of
+	// ZCOUNT: "-inf" and "+inf" are unbounded, and a "(" prefix makes a bound exclusive.
+	ZCount(ctx context.Context, key, min, max string) (int64, error)
+}
+
+func (d *redisDriver) ZAdd(ctx context.Context, key string, members []ZMember, ttl time.Duration, tags []string) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	ctx, cancel := withTimeout(ctx, d.setTimeout)
+	defer cancel()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	finalKey := d.keyWithPrefix(key)
+
+	zs := make([]redis.Z, len(members))
+	for i, member := range members {
+		zs[i] = redis.Z{Score: member.Score, Member: member.Member}
+	}
+
+	pipe, exec := d.writePipeline(ctx)
+	pipe.ZAdd(ctx, finalKey, zs...)
+	if ttl > 0 {
+		pipe.Expire(ctx, finalKey, ttl)
+	}
+	_ = d.addTagsWith(ctx, pipe, finalKey, tags, ttl)
+
+	if err := exec(); err != nil {
+		return wrapError("ZAdd", finalKey, err)
+	}
+
+	return nil
+}
+
+func (d *redisDriver) ZRange(ctx context.Context, key string, start, stop int64) ([]ZMember, error) {
+	ctx, cancel := withTimeout(ctx, d.getTimeout)
+	defer cancel()
+
+	finalKey := d.keyWithPrefix(key)
+
+	zs, err := d.client.ZRangeWithScores(ctx, finalKey, start, stop).Result()
+	if err != nil {
+		return nil, wrapError("ZRange", finalKey, err)
+	}
+
+	members := make([]ZMember, len(zs))
+	for i, z := range zs {
+		member, _ := z.Member.(string)
+		members[i] = ZMember{Score: z.Score, Member: member}
+	}
+
+	return members, nil
+}
+
+func (d *redisDriver) ZScore(ctx context.Context, key, member string) (float64, error) {
+	ctx, cancel := withTimeout(ctx, d.getTimeout)
+	defer cancel()
+
+	finalKey := d.keyWithPrefix(key)
+
+	score, err := d.client.ZScore(ctx, finalKey, member).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, wrapError("ZScore", finalKey, cachemar.ErrNotFound)
+		}
+		return 0, wrapError("ZScore", finalKey, err)
+	}
+
+	return score, nil
+}
+
+func (d *redisDriver) ZRem(ctx context.Context, key string, members ...string) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	ctx, cancel := withTimeout(ctx, d.deleteTimeout)
+	defer cancel()
+
+	finalKey := d.keyWithPrefix(key)
+
+	args := make([]interface{}, len(members))
+	for i, member := range members {
+		args[i] = member
+	}
+
+	if err := d.cmd(ctx).ZRem(ctx, finalKey, args...).Err(); err != nil {
+		return wrapError("ZRem", finalKey, err)
+	}
+
+	return nil
+}
+
+func (d *redisDriver) ZCount(ctx context.Context, key, min, max string) (int64, error) {
+	ctx, cancel := withTimeout(ctx, d.getTimeout)
+	defer cancel()
+
+	finalKey := d.keyWithPrefix(key)
+
+	count, err := d.client.ZCount(ctx, finalKey, min, max).Result()
+	if err != nil {
+		return 0, wrapError("ZCount", finalKey, err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,49 @@
+package redis
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/stremovskyy/cachemar/drivers/redis"
+
+// WithAutoTracing makes the driver create spans for tag operations, so the Redis commands they issue
+// share a parent span. Spans come from Tracer or, if it is nil, from the global tracer provider.
+func (o *Options) WithAutoTracing() *Options {
+	o.AutoTracing = true
+	return o
+}
+
+func newTracer(options *Options) trace.Tracer {
+	if !options.AutoTracing {
+		return nil
+	}
+	if options.Tracer != nil {
+		return options.Tracer
+	}
+
+	return otel.Tracer(tracerName)
+}
+
+// startSpan starts a span when auto tracing is enabled. The returned func records the key count and
+// the error, then ends the span.
+func (d *redisDriver) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, func(keys int, err error)) {
+	if d.tracer == nil {
+		return ctx, func(int, error) {}
+	}
+
+	ctx, span := d.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+
+	return ctx, func(keys int, err error) {
+		span.AddEvent("keys", trace.WithAttributes(attribute.Int("count", keys)))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
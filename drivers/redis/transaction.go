@@ -0,0 +1,68 @@
+package redis
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// txKey carries the MULTI/EXEC pipeline of a transaction of the driver it points to.
+type txKey struct {
+	d *redisDriver
+}
+
+// Transaction queues the operations fn performs with the given ctx and runs them with MULTI/EXEC.
+// If fn fails, nothing is sent (DISCARD). Set, SetRaw, Remove, Increment and Decrement take part in the
+// transaction; Get only queues the read and leaves the value unset. Other operations run immediately.
+// Redis does not roll back the other commands of a transaction when one of them fails on EXEC.
+func (d *redisDriver) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if d.inTransaction(ctx) {
+		return fn(ctx)
+	}
+
+	pipe := d.client.TxPipeline()
+	if err := fn(context.WithValue(ctx, txKey{d: d}, pipe)); err != nil {
+		pipe.Discard()
+		return err
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return wrapError("Transaction", "", err)
+	}
+
+	return nil
+}
+
+func (d *redisDriver) inTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{d: d}).(redis.Pipeliner)
+	return ok
+}
+
+// cmd returns the pipeline of the running transaction, or the client outside of one.
+func (d *redisDriver) cmd(ctx context.Context) redis.Cmdable {
+	if pipe, ok := ctx.Value(txKey{d: d}).(redis.Pipeliner); ok {
+		return pipe
+	}
+
+	return d.client
+}
+
+// writePipeline returns the pipeline for a write of several commands: the one of the running transaction,
+// a MULTI/EXEC transaction, or a plain pipeline in cluster mode, where a transaction can't span hash slots.
+// exec sends it, unless it belongs to the running transaction, which sends it on its own.
+func (d *redisDriver) writePipeline(ctx context.Context) (pipe redis.Pipeliner, exec func() error) {
+	switch {
+	case d.inTransaction(ctx):
+		return d.cmd(ctx).(redis.Pipeliner), func() error { return nil }
+	case d.isCluster():
+		pipe = d.client.Pipeline()
+	default:
+		pipe = d.client.TxPipeline()
+	}
+
+	return pipe, func() error {
+		_, err := pipe.Exec(ctx)
+		return err
+	}
+}
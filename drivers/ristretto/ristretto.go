@@ -0,0 +1,541 @@
+// Package ristretto provides an in-memory cache manager backed by Ristretto, which adds admission
+// control with a TinyLFU policy and scales better under concurrency than the memory driver.
+package ristretto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	dgraph "github.com/dgraph-io/ristretto"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/codecs"
+)
+
+const (
+	// DefaultMaxCost is the default total size of the stored values, in bytes.
+	DefaultMaxCost = 100 << 20
+	// DefaultNumCounters is the default number of keys whose access frequency is tracked.
+	DefaultNumCounters = 1e6
+	// DefaultBufferItems is the default size of the buffers of Get calls, as recommended by Ristretto.
+	DefaultBufferItems = 64
+)
+
+// entry is the value stored in Ristretto. It keeps the key and the tags, so that the tag index can be
+// cleaned up when Ristretto evicts or rejects it.
+type entry struct {
+	key  string
+	data []byte
+	tags []string
+}
+
+type ristretto struct {
+	cache *dgraph.Cache
+	codec cachemar.Codec
+
+	// mu serializes the writes, as Ristretto has no compare-and-swap for conditional sets and counters.
+	mu sync.Mutex
+
+	// tagsMu guards tagKeys. It is separate from mu because Ristretto calls the eviction callback while
+	// a write waits for its buffer to be applied.
+	tagsMu  sync.Mutex
+	tagKeys map[string]map[string]struct{}
+}
+
+type Options struct {
+	// MaxCost is the total size of the stored values in bytes. The cost of a value is the length of its
+	// encoded form. Defaults to DefaultMaxCost.
+	MaxCost int64
+	// NumCounters is the number of keys whose access frequency is tracked, ideally ten times the number of
+	// items expected when the cache is full. Defaults to DefaultNumCounters.
+	NumCounters int64
+	// BufferItems is the size of the buffers of Get calls. Defaults to DefaultBufferItems.
+	BufferItems int64
+
+	// Codec serializes the stored values. Defaults to codecs.JSONCodec.
+	Codec cachemar.Codec
+}
+
+// Validate checks that the sizes are not negative. Zero sizes use the defaults.
+func (o *Options) Validate() error {
+	if o == nil {
+		return errors.New("ristretto: options are nil")
+	}
+	if o.MaxCost < 0 || o.NumCounters < 0 || o.BufferItems < 0 {
+		return errors.New("ristretto: sizes must not be negative")
+	}
+
+	return nil
+}
+
+func orDefault(value, fallback int64) int64 {
+	if value == 0 {
+		return fallback
+	}
+
+	return value
+}
+
+// New creates a Ristretto cache manager. It panics if the options are invalid.
+func New(options *Options) cachemar.Cacher {
+	if err := options.Validate(); err != nil {
+		panic(err)
+	}
+
+	codec := options.Codec
+	if codec == nil {
+		codec = codecs.JSONCodec{}
+	}
+
+	d := &ristretto{
+		codec:   codec,
+		tagKeys: make(map[string]map[string]struct{}),
+	}
+
+	cache, err := dgraph.NewCache(&dgraph.Config{
+		NumCounters:        orDefault(options.NumCounters, DefaultNumCounters),
+		MaxCost:            orDefault(options.MaxCost, DefaultMaxCost),
+		BufferItems:        orDefault(options.BufferItems, DefaultBufferItems),
+		IgnoreInternalCost: true,
+		OnEvict:            d.dropped,
+		OnReject:           d.dropped,
+	})
+	if err != nil {
+		// The sizes are never zero here, which is all NewCache checks.
+		panic(fmt.Errorf("ristretto: %w", err))
+	}
+	d.cache = cache
+
+	return d
+}
+
+// dropped removes an entry evicted or rejected by Ristretto from the tag index.
+func (d *ristretto) dropped(item *dgraph.Item) {
+	if e, ok := item.Value.(*entry); ok {
+		d.removeTags(e.key, e.tags)
+	}
+}
+
+func (d *ristretto) Name() string {
+	return cachemar.RistrettoCacherName.String()
+}
+
+// lookup returns the entry stored under key, if it exists and has not expired.
+func (d *ristretto) lookup(key string) (*entry, bool) {
+	value, ok := d.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	e, ok := value.(*entry)
+	return e, ok
+}
+
+// store replaces the entry stored under key and waits until Ristretto applied it, so that it can be read
+// right away. Like any cache, Ristretto may still reject a new key when it is full. The caller must hold mu.
+func (d *ristretto) store(key string, data []byte, ttl time.Duration, tags []string) {
+	if old, ok := d.lookup(key); ok {
+		d.removeTags(key, old.tags)
+	}
+
+	// Ristretto drops values with a negative TTL, and keeps those with a zero TTL forever.
+	if ttl < 0 {
+		ttl = 0
+	}
+
+	tags = uniqueTags(tags)
+	d.addTags(key, tags)
+	d.cache.SetWithTTL(key, &entry{key: key, data: data, tags: tags}, int64(len(data)), ttl)
+	d.cache.Wait()
+}
+
+// remove deletes the entry stored under key. The caller must hold mu.
+func (d *ristretto) remove(key string) {
+	if old, ok := d.lookup(key); ok {
+		d.removeTags(key, old.tags)
+	}
+	d.cache.Del(key)
+}
+
+func uniqueTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]struct{}, len(tags))
+	unique := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if _, ok := seen[tag]; !ok {
+			seen[tag] = struct{}{}
+			unique = append(unique, tag)
+		}
+	}
+
+	return unique
+}
+
+func (d *ristretto) addTags(key string, tags []string) {
+	d.tagsMu.Lock()
+	defer d.tagsMu.Unlock()
+
+	for _, tag := range tags {
+		keys, ok := d.tagKeys[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			d.tagKeys[tag] = keys
+		}
+		keys[key] = struct{}{}
+	}
+}
+
+func (d *ristretto) removeTags(key string, tags []string) {
+	d.tagsMu.Lock()
+	defer d.tagsMu.Unlock()
+
+	for _, tag := range tags {
+		keys := d.tagKeys[tag]
+		delete(keys, key)
+		if len(keys) == 0 {
+			delete(d.tagKeys, tag)
+		}
+	}
+}
+
+// taggedKeys returns the keys associated with any of the tags.
+func (d *ristretto) taggedKeys(tags []string) []string {
+	d.tagsMu.Lock()
+	defer d.tagsMu.Unlock()
+
+	var keys []string
+	for _, tag := range tags {
+		for key := range d.tagKeys[tag] {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+func (d *ristretto) encode(key string, value interface{}) ([]byte, error) {
+	data, err := d.codec.Marshal(value)
+	if err != nil {
+		return nil, wrapError("Serialize", key, err)
+	}
+
+	return data, nil
+}
+
+func (d *ristretto) decode(key string, e *entry, value interface{}) error {
+	if err := d.codec.Unmarshal(e.data, value); err != nil {
+		return wrapError(cachemar.OperationDeserialize, key, err)
+	}
+
+	return nil
+}
+
+func (d *ristretto) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
+	return d.SetOpts(ctx, key, value, cachemar.SetOptions{TTL: ttl, Tags: tags})
+}
+
+func (d *ristretto) MSet(ctx context.Context, items []cachemar.CacheItem) error {
+	errs := make(cachemar.MultiError, len(items))
+	for i, item := range items {
+		errs[i] = d.Set(ctx, item.Key, item.Value, item.TTL, item.Tags)
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// SetOpts stores the value as described by the options.
+func (d *ristretto) SetOpts(ctx context.Context, key string, value interface{}, opts cachemar.SetOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	if opts.Conditional() {
+		written, err := d.setIf(ctx, key, value, opts.TTL, opts.Tags, opts.IfPresent)
+		if err == nil && !written {
+			return cachemar.ErrNotStored
+		}
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := d.encode(key, value)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.store(key, data, opts.TTL, opts.Tags)
+	return nil
+}
+
+func (d *ristretto) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	return d.setIf(ctx, key, value, ttl, tags, false)
+}
+
+func (d *ristretto) SetIfPresent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	return d.setIf(ctx, key, value, ttl, tags, true)
+}
+
+// setIf stores the value if the presence of the key matches present.
+func (d *ristretto) setIf(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string, present bool) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	data, err := d.encode(key, value)
+	if err != nil {
+		return false, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, exists := d.lookup(key); exists != present {
+		return false, nil
+	}
+
+	d.store(key, data, ttl, tags)
+	return true, nil
+}
+
+func (d *ristretto) Get(ctx context.Context, key string, value interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	e, ok := d.lookup(key)
+	if !ok {
+		return wrapError("Get", key, cachemar.ErrNotFound)
+	}
+
+	return d.decode(key, e, value)
+}
+
+// GetWithTTL retrieves the value together with its remaining time-to-live.
+func (d *ristretto) GetWithTTL(ctx context.Context, key string, value interface{}) (time.Duration, error) {
+	if err := d.Get(ctx, key, value); err != nil {
+		return 0, err
+	}
+
+	ttl, ok := d.cache.GetTTL(key)
+	switch {
+	case !ok:
+		return 0, nil
+	case ttl == 0:
+		return cachemar.NoExpiry, nil
+	default:
+		return ttl, nil
+	}
+}
+
+func (d *ristretto) MGet(ctx context.Context, keys []string, values []interface{}) error {
+	if len(keys) != len(values) {
+		return fmt.Errorf("ristretto: got %d keys and %d values", len(keys), len(values))
+	}
+
+	errs := make(cachemar.MultiError, len(keys))
+	for i, key := range keys {
+		errs[i] = d.Get(ctx, key, values[i])
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// GetAndDelete retrieves the value and removes it under the write lock, so concurrent callers can't both get it.
+func (d *ristretto) GetAndDelete(ctx context.Context, key string, value interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	e, ok := d.lookup(key)
+	if !ok {
+		return wrapError("GetAndDelete", key, cachemar.ErrNotFound)
+	}
+	d.remove(key)
+
+	return d.decode(key, e, value)
+}
+
+// Touch stores the entry again with the new ttl. The value is not decoded.
+func (d *ristretto) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	e, ok := d.lookup(key)
+	if !ok {
+		return wrapError("Touch", key, cachemar.ErrNotFound)
+	}
+	d.store(key, e.data, ttl, e.tags)
+
+	return nil
+}
+
+func (d *ristretto) Remove(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.remove(key)
+	return nil
+}
+
+func (d *ristretto) RemoveByTag(ctx context.Context, tag string) error {
+	return d.RemoveByTags(ctx, []string{tag})
+}
+
+func (d *ristretto) RemoveByTags(ctx context.Context, tags []string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, key := range d.taggedKeys(tags) {
+		d.remove(key)
+	}
+	return nil
+}
+
+func (d *ristretto) Exists(ctx context.Context, key string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	_, ok := d.lookup(key)
+	return ok, nil
+}
+
+func (d *ristretto) Increment(ctx context.Context, key string) error {
+	_, err := d.IncrBy(ctx, key, 1)
+	return err
+}
+
+func (d *ristretto) Decrement(ctx context.Context, key string) error {
+	_, err := d.IncrBy(ctx, key, -1)
+	return err
+}
+
+// IncrBy adds delta to the int64 value of the key. A missing key starts at zero
+// and is stored for cachemar.DefaultCacheTime.
+func (d *ristretto) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	var value int64
+	err := d.update(ctx, "IncrBy", key, &value, func() { value += delta })
+	return value, err
+}
+
+func (d *ristretto) DecrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	return d.IncrBy(ctx, key, -delta)
+}
+
+// IncrementFloat adds delta to the float64 value of the key. A missing key starts at zero
+// and is stored for cachemar.DefaultCacheTime.
+func (d *ristretto) IncrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	var value float64
+	err := d.update(ctx, "IncrementFloat", key, &value, func() { value += delta })
+	return value, err
+}
+
+func (d *ristretto) DecrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	return d.IncrementFloat(ctx, key, -delta)
+}
+
+// update decodes the value of the key into value, applies fn and stores the result, keeping the remaining
+// time-to-live and the tags of the key. It returns ErrInvalidType if the value can't be decoded.
+func (d *ristretto) update(ctx context.Context, operation, key string, value interface{}, fn func()) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	ttl, tags := cachemar.DefaultCacheTime, []string(nil)
+	if e, ok := d.lookup(key); ok {
+		if err := d.codec.Unmarshal(e.data, value); err != nil {
+			return wrapError(operation, key, cachemar.ErrInvalidType)
+		}
+		if remaining, ok := d.cache.GetTTL(key); ok {
+			ttl = remaining
+		}
+		tags = e.tags
+	}
+
+	fn()
+
+	data, err := d.encode(key, value)
+	if err != nil {
+		return err
+	}
+	d.store(key, data, ttl, tags)
+
+	return nil
+}
+
+func (d *ristretto) GetKeysByTag(ctx context.Context, tag string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, key := range d.taggedKeys([]string{tag}) {
+		if _, ok := d.lookup(key); ok {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+// Flush removes all entries.
+func (d *ristretto) Flush(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Clear calls the eviction callback, which takes tagsMu.
+	d.cache.Clear()
+
+	d.tagsMu.Lock()
+	d.tagKeys = make(map[string]map[string]struct{})
+	d.tagsMu.Unlock()
+
+	return nil
+}
+
+func (d *ristretto) Ping() error {
+	return nil
+}
+
+// Close stops the goroutines of Ristretto. The cache manager must not be used afterwards.
+func (d *ristretto) Close() error {
+	d.cache.Close()
+	return nil
+}
+
+// wrapError attaches the driver, operation and key to err.
+func wrapError(operation, key string, err error) error {
+	return &cachemar.DriverError{Driver: cachemar.RistrettoCacherName.String(), Operation: operation, Key: key, Cause: err}
+}
@@ -0,0 +1,221 @@
+// Package dsn parses connection URLs into driver options.
+package dsn
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/stremovskyy/cachemar/drivers/redis"
+)
+
+const (
+	defaultRedisPort    = "6379"
+	defaultSentinelPort = "26379"
+)
+
+// ParseRedisURL parses a Redis connection URL into driver options.
+//
+// Supported forms:
+//
+//	redis://[[user]:password@]host[:port][/db][?db=0&prefix=app&compression=true]
+//	rediss://...                                  (as redis://, with TLS)
+//	redis+sentinel://[[user]:password@]host1:26379,host2:26379/masterName[?db=0&prefix=app]
+//	rediss+sentinel://...                         (as redis+sentinel://, with TLS)
+//	redis+cluster://node1:7000,node2:7001[?password=secret&prefix=app]
+//	rediss+cluster://...                          (as redis+cluster://, with TLS)
+//
+// The password may be given either as URL user info or as the password query parameter. A user in the
+// user info is the ACL user to authenticate as.
+func ParseRedisURL(raw string) (*redis.Options, error) {
+	scheme, rest, ok := strings.Cut(raw, "://")
+	if !ok {
+		// The URL is left out of the error, as it may hold a password.
+		return nil, fmt.Errorf("dsn: missing scheme")
+	}
+
+	base, mode, _ := strings.Cut(strings.ToLower(scheme), "+")
+	if base != "redis" && base != "rediss" {
+		return nil, fmt.Errorf("dsn: unsupported scheme %q", scheme)
+	}
+	if mode != "" && mode != "sentinel" && mode != "cluster" {
+		return nil, fmt.Errorf("dsn: unsupported scheme %q", scheme)
+	}
+
+	// Host lists such as "a:1,b:2" are not valid URL hosts, so split them off before parsing.
+	hostEnd := strings.IndexAny(rest, "/?")
+	if hostEnd < 0 {
+		hostEnd = len(rest)
+	}
+	hostPart := rest[:hostEnd]
+
+	userInfo := ""
+	if at := strings.LastIndex(hostPart, "@"); at >= 0 {
+		userInfo, hostPart = hostPart[:at], hostPart[at+1:]
+	}
+
+	u, err := url.Parse("redis://placeholder" + rest[hostEnd:])
+	if err != nil {
+		// url.Error quotes the URL, whose password parameter must not end up in the error.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("dsn: invalid url: %v", err)
+	}
+
+	options := &redis.Options{}
+
+	if base == "rediss" {
+		options.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	if userInfo != "" {
+		username, password, err := parseUserInfo(userInfo)
+		if err != nil {
+			return nil, err
+		}
+		options.Username = username
+		options.Password = password
+	}
+
+	defaultPort := defaultRedisPort
+	if mode == "sentinel" {
+		defaultPort = defaultSentinelPort
+	}
+	addrs, err := parseHosts(hostPart, defaultPort)
+	if err != nil {
+		return nil, err
+	}
+
+	path := strings.Trim(u.Path, "/")
+	switch mode {
+	case "sentinel":
+		if path == "" {
+			return nil, fmt.Errorf("dsn: master name is required for %s", scheme)
+		}
+		options.SentinelAddrs = addrs
+		options.MasterName = path
+	case "cluster":
+		if path != "" {
+			return nil, fmt.Errorf("dsn: unexpected path %q for %s", path, scheme)
+		}
+		options.ClusterAddrs = addrs
+	default:
+		if len(addrs) > 1 {
+			return nil, fmt.Errorf("dsn: %s accepts a single host, got %d", scheme, len(addrs))
+		}
+		options.DSN = addrs[0]
+		if path != "" {
+			db, err := parseDatabase(path)
+			if err != nil {
+				return nil, err
+			}
+			options.Database = db
+		}
+	}
+
+	if err := applyQuery(options, u.Query()); err != nil {
+		return nil, err
+	}
+
+	if err := options.Validate(); err != nil {
+		return nil, fmt.Errorf("dsn: %v", err)
+	}
+
+	return options, nil
+}
+
+// parseUserInfo extracts the user and the password from "[user]:password" or a bare "password" user info.
+func parseUserInfo(userInfo string) (username, password string, err error) {
+	username, password, ok := strings.Cut(userInfo, ":")
+	if !ok {
+		username, password = "", userInfo
+	}
+
+	if username, err = url.PathUnescape(username); err != nil {
+		return "", "", fmt.Errorf("dsn: invalid user: %v", err)
+	}
+	if password, err = url.PathUnescape(password); err != nil {
+		return "", "", errors.New("dsn: invalid escape in password")
+	}
+
+	return username, password, nil
+}
+
+// parseHosts splits a comma-separated host list, adding defaultPort where it is missing.
+func parseHosts(hostPart, defaultPort string) ([]string, error) {
+	if hostPart == "" {
+		return nil, fmt.Errorf("dsn: missing host")
+	}
+
+	hosts := strings.Split(hostPart, ",")
+	addrs := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		if host == "" {
+			return nil, fmt.Errorf("dsn: empty host in %q", hostPart)
+		}
+
+		h, port, err := net.SplitHostPort(host)
+		if err != nil {
+			// No port given; anything else is malformed.
+			if strings.Contains(host, ":") && !strings.HasPrefix(host, "[") {
+				return nil, fmt.Errorf("dsn: invalid host %q: %v", host, err)
+			}
+			h, port = strings.Trim(host, "[]"), defaultPort
+		}
+		if h == "" {
+			return nil, fmt.Errorf("dsn: missing host name in %q", host)
+		}
+		if _, err := strconv.ParseUint(port, 10, 16); err != nil {
+			return nil, fmt.Errorf("dsn: invalid port %q in %q", port, host)
+		}
+
+		addrs = append(addrs, net.JoinHostPort(h, port))
+	}
+
+	return addrs, nil
+}
+
+func parseDatabase(value string) (int, error) {
+	db, err := strconv.Atoi(value)
+	if err != nil || db < 0 {
+		return 0, fmt.Errorf("dsn: invalid database %q", value)
+	}
+
+	return db, nil
+}
+
+// applyQuery sets the options carried in the query string. Unknown parameters are rejected.
+func applyQuery(options *redis.Options, query url.Values) error {
+	for name, values := range query {
+		value := values[len(values)-1]
+
+		switch name {
+		case "db":
+			db, err := parseDatabase(value)
+			if err != nil {
+				return err
+			}
+			options.Database = db
+		case "password":
+			options.Password = value
+		case "prefix":
+			options.Prefix = value
+		case "compression":
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("dsn: invalid compression %q", value)
+			}
+			options.CompressionEnabled = enabled
+		default:
+			return fmt.Errorf("dsn: unknown parameter %q", name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+// Package echo provides Echo middleware that caches responses in a cachemar.Manager.
+package echo
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	labstack "github.com/labstack/echo/v4"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+const keyPrefix = "echo:"
+
+// EchoCacheConfig configures the caching middleware.
+type EchoCacheConfig struct {
+	// TTL is the time-to-live of the cached responses. Defaults to cachemar.DefaultCacheTime.
+	TTL time.Duration
+	// Skipper bypasses the cache for the request when it returns true. Accepts an echo middleware.Skipper.
+	Skipper func(c labstack.Context) bool
+	// KeyGenerator returns the cache key of the request. Defaults to the method, path and query string.
+	KeyGenerator func(c labstack.Context) string
+	// TagGenerator returns the tags of the cached response, so related responses can be invalidated together.
+	TagGenerator func(c labstack.Context) []string
+	// StatusCodes are the response status codes that are cached. Defaults to 200.
+	StatusCodes []int
+}
+
+// CachedResponse is a response as it is stored in the cache.
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	writer io.Writer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	return r.writer.Write(data)
+}
+
+// Cache returns middleware that serves cached responses and caches the response of the handler on a miss.
+func Cache(manager cachemar.Manager, config EchoCacheConfig) labstack.MiddlewareFunc {
+	if config.TTL <= 0 {
+		config.TTL = cachemar.DefaultCacheTime
+	}
+	if config.KeyGenerator == nil {
+		config.KeyGenerator = defaultKey
+	}
+	if len(config.StatusCodes) == 0 {
+		config.StatusCodes = []int{http.StatusOK}
+	}
+
+	statusCodes := make(map[int]struct{}, len(config.StatusCodes))
+	for _, code := range config.StatusCodes {
+		statusCodes[code] = struct{}{}
+	}
+
+	return func(next labstack.HandlerFunc) labstack.HandlerFunc {
+		return func(c labstack.Context) error {
+			if config.Skipper != nil && config.Skipper(c) {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			key := config.KeyGenerator(c)
+			resp := c.Response()
+
+			var cached CachedResponse
+			if err := manager.Get(ctx, key, &cached); err == nil {
+				header := resp.Header()
+				for name, values := range cached.Header {
+					header[name] = values
+				}
+				resp.WriteHeader(cached.StatusCode)
+				_, err = resp.Write(cached.Body)
+				resp.Committed = true
+				return err
+			}
+
+			var body bytes.Buffer
+			original := resp.Writer
+			resp.Writer = &responseRecorder{ResponseWriter: original, writer: io.MultiWriter(original, &body)}
+			defer func() { resp.Writer = original }()
+
+			if err := next(c); err != nil {
+				return err
+			}
+
+			if _, ok := statusCodes[resp.Status]; !ok {
+				return nil
+			}
+
+			var tags []string
+			if config.TagGenerator != nil {
+				tags = config.TagGenerator(c)
+			}
+
+			cached = CachedResponse{
+				StatusCode: resp.Status,
+				Header:     resp.Header().Clone(),
+				Body:       body.Bytes(),
+			}
+
+			// A failing cache must not fail the request, the response has been written already.
+			_ = manager.Set(ctx, key, cached, config.TTL, tags)
+
+			return nil
+		}
+	}
+}
+
+func defaultKey(c labstack.Context) string {
+	return keyPrefix + c.Request().Method + ":" + c.Request().URL.RequestURI()
+}
@@ -1,5 +1,165 @@
 package cachemar
 
-import "errors"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+)
 
-var ErrNotFound = errors.New("not found")
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrInvalidKey = errors.New("invalid key")
+
+	ErrLockNotAcquired = errors.New("lock not acquired")
+	ErrTagTooLarge     = errors.New("tag too large")
+	ErrInvalidType     = errors.New("invalid type")
+	ErrReadOnly        = errors.New("cache manager is read-only")
+	ErrTooManyRequests = errors.New("too many concurrent requests")
+	ErrNotStored       = errors.New("value not stored")
+)
+
+// Operations of a DriverError caused by a stored value that can't be decoded.
+const (
+	OperationDecompress  = "Decompress"
+	OperationDeserialize = "Deserialize"
+)
+
+// DriverError describes a failed driver operation. Cause keeps the underlying error, so errors.Is and errors.As
+// see through it to sentinels such as ErrNotFound and to the client library's own error types.
+type DriverError struct {
+	Driver    string
+	Operation string
+	Key       string
+	Cause     error
+}
+
+func (e *DriverError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("%s: %s: %v", e.Driver, e.Operation, e.Cause)
+	}
+
+	return fmt.Sprintf("%s: %s %q: %v", e.Driver, e.Operation, e.Key, e.Cause)
+}
+
+func (e *DriverError) Unwrap() error {
+	return e.Cause
+}
+
+// IsNotFound reports whether err means the key does not exist.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsDecodeError reports whether err is caused by a stored value that could not be decompressed or deserialized,
+// e.g. one written in an older serialization format.
+func IsDecodeError(err error) bool {
+	var driverErr *DriverError
+	if !errors.As(err, &driverErr) {
+		return false
+	}
+
+	return driverErr.Operation == OperationDecompress || driverErr.Operation == OperationDeserialize
+}
+
+// typeMismatches are the messages of the gob and msgpack errors caused by decoding a value into a target
+// of another type, which neither package exports as an error type.
+var typeMismatches = []string{
+	"gob: decoding into local type",
+	"gob: type mismatch",
+	"gob: attempt to decode into a non-pointer",
+	"msgpack: invalid code=",
+	"msgpack: Decode(non-pointer",
+}
+
+// isTypeMismatch reports whether err is a decode error caused by the target rather than by the stored
+// value, e.g. a string read into an int. The value is intact and may be read with the right type.
+func isTypeMismatch(err error) bool {
+	if errors.Is(err, ErrInvalidType) || errors.Is(err, ErrRawValue) {
+		return true
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	var invalidErr *json.InvalidUnmarshalError
+	if errors.As(err, &typeErr) || errors.As(err, &invalidErr) {
+		return true
+	}
+
+	for _, msg := range typeMismatches {
+		if strings.Contains(err.Error(), msg) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsTimeout reports whether err is caused by an exceeded deadline or a network timeout.
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var timeout interface{ Timeout() bool }
+	return errors.As(err, &timeout) && timeout.Timeout()
+}
+
+// IsConnectionError reports whether err is caused by a failed or broken connection to the backend.
+func IsConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// MultiError holds the errors of a batch operation, one entry per input item. Successful items have a nil entry.
+type MultiError []error
+
+func (m MultiError) Error() string {
+	messages := make([]string, 0, len(m))
+	for i, err := range m {
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("item %d: %v", i, err))
+		}
+	}
+
+	return fmt.Sprintf("errors: [%s]", strings.Join(messages, "; "))
+}
+
+// Unwrap returns the non-nil errors so errors.Is and errors.As can inspect them.
+func (m MultiError) Unwrap() []error {
+	errs := make([]error, 0, len(m))
+	for _, err := range m {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// ErrorOrNil returns nil if no item failed.
+func (m MultiError) ErrorOrNil() error {
+	for _, err := range m {
+		if err != nil {
+			return m
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package cachemar
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// lockTTLFactor makes the distributed lock expire slightly before the cached value.
+	lockTTLFactor = 0.9
+	// minLockTTL bounds the lock of short-lived and non-expiring values, so the lock of a crashed
+	// holder always expires.
+	minLockTTL = time.Second
+	// lockPollInterval is how often a caller waiting on another instance checks the cache.
+	lockPollInterval = 50 * time.Millisecond
+)
+
+// GetOrCreate retrieves the value stored under key. On a miss only one create call per key is made
+// across all goroutines; its result is stored and decoded into value.
+func (c *manager) GetOrCreate(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string, create func(ctx context.Context) (interface{}, error)) (err error) {
+	key, err = c.prepareKey(key)
+	if err != nil {
+		return err
+	}
+
+	cacher, done := c.active()
+	defer done(&err)
+
+	return c.getOrCreate(ctx, cacher, key, value, ttl, c.prepareTags(tags), create)
+}
+
+// GetOrSet retrieves the value stored under key. On a miss the result of loader is stored with Set
+// and decoded into value. It behaves like GetOrCreate.
+func (c *manager) GetOrSet(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string, loader func(ctx context.Context) (interface{}, error)) error {
+	return c.GetOrCreate(ctx, key, value, ttl, tags, loader)
+}
+
+// getOrCreate stores the created value in cacher, so the key and the tags must be prepared for it. On a miss
+// in read-only mode, ErrReadOnly is returned without calling create.
+func (c *manager) getOrCreate(ctx context.Context, cacher Cacher, key string, value interface{}, ttl time.Duration, tags []string, create func(ctx context.Context) (interface{}, error)) error {
+	if err := cacher.Get(ctx, key, value); err == nil {
+		return nil
+	}
+
+	if c.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	load := func() (interface{}, error) {
+		if c.locker == nil {
+			return nil, createAndSet(ctx, cacher, key, ttl, tags, create)
+		}
+
+		return nil, c.lockedCreate(ctx, cacher, key, ttl, tags, create)
+	}
+
+	var err error
+	if c.singleFlight {
+		_, err, _ = c.group.Do(key, load)
+	} else {
+		_, err = load()
+	}
+	if err != nil {
+		return err
+	}
+
+	return cacher.Get(ctx, key, value)
+}
+
+// lockedCreate guards create with the distributed lock so that only one instance populates the key.
+func (c *manager) lockedCreate(ctx context.Context, cacher Cacher, key string, ttl time.Duration, tags []string, create func(ctx context.Context) (interface{}, error)) error {
+	lockTTL := time.Duration(float64(ttl) * lockTTLFactor)
+	if lockTTL < minLockTTL {
+		lockTTL = minLockTTL
+	}
+
+	token, err := c.locker.LockKey(ctx, key, lockTTL)
+	if errors.Is(err, ErrLockNotAcquired) {
+		if waitForKey(ctx, cacher, key, lockTTL) {
+			return nil
+		}
+
+		return createAndSet(ctx, cacher, key, ttl, tags, create)
+	}
+	if err != nil {
+		return err
+	}
+	defer c.locker.UnlockKey(ctx, key, token)
+
+	// Another instance may have populated the key while we were acquiring the lock.
+	if exists, err := cacher.Exists(ctx, key); err == nil && exists {
+		return nil
+	}
+
+	return createAndSet(ctx, cacher, key, ttl, tags, create)
+}
+
+// waitForKey polls the cache until the key appears or the timeout elapses.
+func waitForKey(ctx context.Context, cacher Cacher, key string, timeout time.Duration) bool {
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-deadline.C:
+			return false
+		case <-ticker.C:
+			if exists, err := cacher.Exists(ctx, key); err == nil && exists {
+				return true
+			}
+		}
+	}
+}
+
+func createAndSet(ctx context.Context, cacher Cacher, key string, ttl time.Duration, tags []string, create func(ctx context.Context) (interface{}, error)) error {
+	created, err := create(ctx)
+	if err != nil {
+		return err
+	}
+
+	return cacher.Set(ctx, key, created, ttl, tags)
+}
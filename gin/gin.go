@@ -0,0 +1,121 @@
+// Package gin provides Gin middleware that caches responses in a cachemar.Manager.
+package gin
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"time"
+
+	gingonic "github.com/gin-gonic/gin"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+const (
+	keyPrefix        = "gin:"
+	cacheHeader      = "X-Cache"
+	cacheHeaderHit   = "HIT"
+	cacheHeaderMiss  = "MISS"
+	noCacheDirective = "no-cache"
+)
+
+// CachedResponse is a response as it is stored in the cache.
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+type responseRecorder struct {
+	gingonic.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// Cache returns middleware that caches successful GET and HEAD responses by method, path and sorted
+// query parameters. The cached responses are associated with tags, so related pages can be invalidated together.
+func Cache(manager cachemar.Manager, ttl time.Duration, tags ...string) gingonic.HandlerFunc {
+	return handler(
+		manager, ttl, tags, func(c *gingonic.Context) string {
+			// Encode sorts the parameters by name, so their order does not affect the key.
+			return keyPrefix + c.Request.Method + ":" + c.Request.URL.Path + "?" + c.Request.URL.Query().Encode()
+		},
+	)
+}
+
+// CacheByParam returns middleware that caches successful GET and HEAD responses of the route per method and
+// value of a path parameter.
+func CacheByParam(manager cachemar.Manager, paramName string, ttl time.Duration, tags ...string) gingonic.HandlerFunc {
+	return handler(
+		manager, ttl, tags, func(c *gingonic.Context) string {
+			return keyPrefix + c.Request.Method + ":" + c.FullPath() + ":" + paramName + "=" + c.Param(paramName)
+		},
+	)
+}
+
+func handler(manager cachemar.Manager, ttl time.Duration, tags []string, key func(c *gingonic.Context) string) gingonic.HandlerFunc {
+	return func(c *gingonic.Context) {
+		if !cacheable(c.Request) || bypass(c.Request) {
+			c.Next()
+			return
+		}
+
+		cacheKey := key(c)
+
+		var cached CachedResponse
+		if err := manager.Get(c.Request.Context(), cacheKey, &cached); err == nil {
+			header := c.Writer.Header()
+			for name, values := range cached.Header {
+				header[name] = values
+			}
+			c.Header(cacheHeader, cacheHeaderHit)
+			c.Data(cached.StatusCode, cached.Header.Get("Content-Type"), cached.Body)
+			c.Abort()
+			return
+		}
+
+		c.Header(cacheHeader, cacheHeaderMiss)
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		if status < http.StatusOK || status >= http.StatusMultipleChoices {
+			return
+		}
+
+		header := recorder.Header().Clone()
+		header.Del(cacheHeader)
+
+		cached = CachedResponse{
+			StatusCode: status,
+			Header:     header,
+			Body:       recorder.body.Bytes(),
+		}
+
+		// A failing cache must not fail the request, the response has been written already.
+		_ = manager.Set(c.Request.Context(), cacheKey, cached, ttl, tags)
+	}
+}
+
+// cacheable reports whether the request method is safe to answer from the cache.
+func cacheable(r *http.Request) bool {
+	return r.Method == http.MethodGet || r.Method == http.MethodHead
+}
+
+// bypass reports whether the client asked not to be served from the cache.
+func bypass(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Cache-Control"), noCacheDirective) ||
+		strings.Contains(r.Header.Get("Pragma"), noCacheDirective)
+}
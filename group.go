@@ -0,0 +1,62 @@
+package cachemar
+
+import (
+	"context"
+	"fmt"
+)
+
+// Transactor is implemented by cache managers that can run a group of operations atomically.
+type Transactor interface {
+	// Transaction runs fn as a transaction. Only the operations fn performs with the ctx it is given take part.
+	// If fn fails, their effects are rolled back.
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// CacheGroup collects operations to run together as a transaction. See Group.
+type CacheGroup struct {
+	cacher Cacher
+	ops    []func(ctx context.Context) error
+}
+
+// Group creates a CacheGroup running its operations in a transaction of c. Operations must call the
+// cache with the ctx they are given, so that they join the transaction. The guarantees depend on the
+// cache manager: the memory driver holds its lock once for all operations and restores the changed keys on
+// failure, while Redis queues them in MULTI/EXEC and reads (Get) are pending until the group is executed.
+// Cache managers that don't implement Transactor run the operations one by one without rollback.
+func Group(c Cacher) *CacheGroup {
+	return &CacheGroup{cacher: c}
+}
+
+// Add appends an operation to the group.
+func (g *CacheGroup) Add(fn func(ctx context.Context) error) *CacheGroup {
+	g.ops = append(g.ops, fn)
+	return g
+}
+
+// Execute runs the operations in order, stopping at the first failure.
+func (g *CacheGroup) Execute(ctx context.Context) error {
+	run := func(ctx context.Context) error {
+		for i, op := range g.ops {
+			if err := op(ctx); err != nil {
+				return fmt.Errorf("group operation %d failed: %w", i, err)
+			}
+		}
+
+		return nil
+	}
+
+	if transactor, ok := As[Transactor](g.cacher); ok {
+		return transactor.Transaction(ctx, run)
+	}
+
+	return run(ctx)
+}
+
+// Transaction runs fn in a transaction of the current cache manager if it supports them, or directly otherwise.
+func (c *manager) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if transactor, ok := As[Transactor](c.Current()); ok {
+		return transactor.Transaction(ctx, fn)
+	}
+
+	return fn(ctx)
+}
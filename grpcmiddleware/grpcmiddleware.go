@@ -0,0 +1,134 @@
+// Package grpcmiddleware provides a gRPC server interceptor that caches unary responses in a cachemar.Manager.
+package grpcmiddleware
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+const keyPrefix = "grpc:"
+
+// GRPCCacheOptions configures the caching interceptor.
+type GRPCCacheOptions struct {
+	// TTL is the time-to-live of the cached responses. Defaults to cachemar.DefaultCacheTime.
+	TTL time.Duration
+	// Methods are the full names of the methods whose responses are cached, e.g. "/pkg.Service/Method".
+	// Only the listed methods are cached, so that mutations are never served from the cache. Nothing is
+	// cached when empty.
+	Methods []string
+	// TagExtractor returns the tags of the cached response, so related responses can be invalidated together.
+	TagExtractor func(ctx context.Context, method string) []string
+	// KeyExtractor returns the cache key of the request. Defaults to the method name and the encoded request.
+	KeyExtractor func(ctx context.Context, method string, req interface{}) string
+}
+
+// CachedResponse is a response as it is stored in the cache.
+type CachedResponse struct {
+	// Type is the full name of the response message type.
+	Type string
+	// Data is the wire encoding of the response message.
+	Data []byte
+}
+
+// UnaryServerInterceptor returns an interceptor that serves cached responses without invoking the handler
+// and caches the response of the handler on a miss. Requests and responses must be proto messages.
+func UnaryServerInterceptor(manager cachemar.Manager, opts GRPCCacheOptions) grpc.UnaryServerInterceptor {
+	if opts.TTL <= 0 {
+		opts.TTL = cachemar.DefaultCacheTime
+	}
+	if opts.KeyExtractor == nil {
+		opts.KeyExtractor = defaultKey
+	}
+
+	methods := make(map[string]struct{}, len(opts.Methods))
+	for _, method := range opts.Methods {
+		methods[method] = struct{}{}
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if _, ok := methods[info.FullMethod]; !ok {
+			return handler(ctx, req)
+		}
+
+		key := opts.KeyExtractor(ctx, info.FullMethod, req)
+		if key == "" {
+			return handler(ctx, req)
+		}
+
+		var cached CachedResponse
+		if err := manager.Get(ctx, key, &cached); err == nil {
+			if resp, err := decode(&cached); err == nil {
+				return resp, nil
+			}
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+
+		msg, ok := resp.(proto.Message)
+		if !ok {
+			return resp, nil
+		}
+
+		data, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+		if err != nil {
+			return resp, nil
+		}
+
+		var tags []string
+		if opts.TagExtractor != nil {
+			tags = opts.TagExtractor(ctx, info.FullMethod)
+		}
+
+		cached = CachedResponse{
+			Type: string(msg.ProtoReflect().Descriptor().FullName()),
+			Data: data,
+		}
+
+		// A failing cache must not fail the call, the response is returned either way.
+		_ = manager.Set(ctx, key, cached, opts.TTL, tags)
+
+		return resp, nil
+	}
+}
+
+// defaultKey builds the key from the method name and the deterministic wire encoding of the request.
+// Requests that are not proto messages get an empty key and are not cached.
+func defaultKey(_ context.Context, method string, req interface{}) string {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return ""
+	}
+
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return ""
+	}
+
+	return keyPrefix + method + ":" + base64.StdEncoding.EncodeToString(data)
+}
+
+func decode(cached *CachedResponse) (proto.Message, error) {
+	messageType, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(cached.Type))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find message type %s: %v", cached.Type, err)
+	}
+
+	msg := messageType.New().Interface()
+	if err := proto.Unmarshal(cached.Data, msg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal message: %v", err)
+	}
+
+	return msg, nil
+}
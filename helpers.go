@@ -2,6 +2,7 @@ package cachemar
 
 import (
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 )
@@ -13,3 +14,11 @@ func HashKey(prefix string, object interface{}) string {
 
 	return prefix + ":" + hashStr
 }
+
+func HashKeySHA256(prefix string, object interface{}) string {
+	str := fmt.Sprintf("%v", object)
+	hash := sha256.Sum256([]byte(str))
+	hashStr := hex.EncodeToString(hash[:])
+
+	return prefix + ":" + hashStr
+}
@@ -0,0 +1,106 @@
+// Package httphandler provides net/http middleware that caches responses in a cachemar.Manager.
+package httphandler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+const keyPrefix = "http:"
+
+// HTTPCacheOptions configures the caching middleware.
+type HTTPCacheOptions struct {
+	// TTL is the time-to-live of the cached responses. Defaults to cachemar.DefaultCacheTime.
+	TTL time.Duration
+	// Methods are the request methods whose responses are cached. Defaults to GET and HEAD.
+	Methods []string
+	// TagExtractor returns the tags of the cached response, so related responses can be invalidated together.
+	TagExtractor func(*http.Request) []string
+	// KeyExtractor returns the cache key of the request. Defaults to the method, path and query string.
+	KeyExtractor func(*http.Request) string
+	// SkipIf bypasses the cache for the request when it returns true.
+	SkipIf func(*http.Request) bool
+}
+
+// CachedResponse is a response as it is stored in the cache.
+type CachedResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Cache returns middleware that serves cached responses and caches successful responses on a miss.
+// Only responses with status 200 are cached.
+func Cache(manager cachemar.Manager, opts HTTPCacheOptions) func(http.Handler) http.Handler {
+	if opts.TTL <= 0 {
+		opts.TTL = cachemar.DefaultCacheTime
+	}
+	if len(opts.Methods) == 0 {
+		opts.Methods = []string{http.MethodGet, http.MethodHead}
+	}
+	if opts.KeyExtractor == nil {
+		opts.KeyExtractor = defaultKey
+	}
+
+	methods := make(map[string]struct{}, len(opts.Methods))
+	for _, method := range opts.Methods {
+		methods[method] = struct{}{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if _, ok := methods[r.Method]; !ok || (opts.SkipIf != nil && opts.SkipIf(r)) {
+					next.ServeHTTP(w, r)
+					return
+				}
+
+				key := opts.KeyExtractor(r)
+
+				var cached CachedResponse
+				if err := manager.Get(r.Context(), key, &cached); err == nil {
+					writeResponse(w, &cached)
+					return
+				}
+
+				rec := httptest.NewRecorder()
+				next.ServeHTTP(rec, r)
+
+				cached = CachedResponse{
+					StatusCode: rec.Code,
+					Header:     rec.Header().Clone(),
+					Body:       rec.Body.Bytes(),
+				}
+
+				if cached.StatusCode == http.StatusOK {
+					var tags []string
+					if opts.TagExtractor != nil {
+						tags = opts.TagExtractor(r)
+					}
+
+					// A failing cache must not fail the request, the response is served either way.
+					_ = manager.Set(r.Context(), key, cached, opts.TTL, tags)
+				}
+
+				writeResponse(w, &cached)
+			},
+		)
+	}
+}
+
+func defaultKey(r *http.Request) string {
+	return keyPrefix + r.Method + ":" + r.URL.RequestURI()
+}
+
+func writeResponse(w http.ResponseWriter, resp *CachedResponse) {
+	header := w.Header()
+	for name, values := range resp.Header {
+		header[name] = values
+	}
+
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(resp.Body)
+}
@@ -0,0 +1,66 @@
+package cachemar
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// InspectResult is the full metadata of a stored key, for debugging.
+type InspectResult struct {
+	// Key is the key as stored in the backend.
+	Key string
+	// RawBytes are the stored bytes, before decompression and decoding.
+	RawBytes []byte
+	// DecodedValue is the value decoded without a target type, or nil if it can't be decoded that way.
+	DecodedValue interface{}
+	// TTLRemaining is the remaining time-to-live. Zero or negative if unknown or the key does not expire.
+	TTLRemaining time.Duration
+	Tags         []string
+	// Driver is the name of the cache manager the key was found in.
+	Driver     string
+	Compressed bool
+	SizeBytes  int64
+	// SetAt is when the value was stored, or zero if the backend does not track it.
+	SetAt time.Time
+}
+
+// Inspector is implemented by cache managers that can dump the full metadata of a key.
+type Inspector interface {
+	Inspect(ctx context.Context, key string) (*InspectResult, error)
+}
+
+// Inspect dumps the full metadata of the key in the current cache manager. Cache managers that don't
+// implement Inspector only report the decoded value.
+func (c *manager) Inspect(ctx context.Context, key string) (*InspectResult, error) {
+	key, err := c.prepareKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return inspect(ctx, c.currentName(), c.Current(), key)
+}
+
+func inspect(ctx context.Context, name string, cacher Cacher, key string) (*InspectResult, error) {
+	if cacher == nil {
+		return nil, fmt.Errorf("cache manager %q is not registered", name)
+	}
+
+	if inspector, ok := As[Inspector](cacher); ok {
+		result, err := inspector.Inspect(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if result.Driver == "" {
+			result.Driver = name
+		}
+		return result, nil
+	}
+
+	result := &InspectResult{Key: key, Driver: name}
+	if err := cacher.Get(ctx, key, &result.DecodedValue); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
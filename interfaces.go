@@ -2,6 +2,7 @@ package cachemar
 
 import (
 	"context"
+	"io"
 	"time"
 )
 
@@ -10,9 +11,39 @@ type Cacher interface {
 	// Set stores a key-value pair in the cache with the specified ttl (time-to-live) duration and tags.
 	Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error
 
+	// MSet stores multiple items at once. A returned MultiError holds the error of each item by index.
+	MSet(ctx context.Context, items []CacheItem) error
+
+	// SetOpts stores a key-value pair as described by the options. ErrNotStored is returned if the value
+	// was not stored because the key was present with IfAbsent, or absent with IfPresent.
+	SetOpts(ctx context.Context, key string, value interface{}, opts SetOptions) error
+
+	// SetIfAbsent stores a key-value pair like Set, only if the key does not exist yet.
+	// It reports whether the value was stored.
+	SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error)
+
+	// SetIfPresent stores a key-value pair like Set, only if the key already exists.
+	// It reports whether the value was stored.
+	SetIfPresent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error)
+
 	// Get retrieves a value based on its key from the cache, and unmarshals it into the provided variable.
 	Get(ctx context.Context, key string, value interface{}) error
 
+	// GetWithTTL retrieves a value like Get and returns its remaining time-to-live: NoExpiry if the value
+	// does not expire, or zero if it has just expired.
+	GetWithTTL(ctx context.Context, key string, value interface{}) (time.Duration, error)
+
+	// MGet retrieves the values of multiple keys at once, unmarshaling each into the value at the same index.
+	// If some keys fail, e.g. with ErrNotFound, a MultiError holds the error of each key by index.
+	MGet(ctx context.Context, keys []string, values []interface{}) error
+
+	// GetAndDelete retrieves a value like Get and removes it, so that it is consumed only once.
+	GetAndDelete(ctx context.Context, key string, value interface{}) error
+
+	// Touch resets the expiry of an existing key to ttl without reading its value.
+	// ErrNotFound is returned if the key does not exist.
+	Touch(ctx context.Context, key string, ttl time.Duration) error
+
 	// Remove deletes a key-value pair from the cache using the key.
 	Remove(ctx context.Context, key string) error
 
@@ -31,19 +62,118 @@ type Cacher interface {
 	// Decrement decrements the integer value of a key in the cache by one.
 	Decrement(ctx context.Context, key string) error
 
+	// IncrBy adds delta to the integer value of a key and returns the new value.
+	// A missing key is treated as zero. ErrInvalidType is returned if the value is not an integer.
+	IncrBy(ctx context.Context, key string, delta int64) (int64, error)
+
+	// DecrBy subtracts delta from the integer value of a key and returns the new value.
+	DecrBy(ctx context.Context, key string, delta int64) (int64, error)
+
+	// IncrementFloat adds delta to the floating-point value of a key and returns the new value.
+	// A missing key is treated as zero. ErrInvalidType is returned if the value is not a float.
+	IncrementFloat(ctx context.Context, key string, delta float64) (float64, error)
+
+	// DecrementFloat subtracts delta from the floating-point value of a key and returns the new value.
+	DecrementFloat(ctx context.Context, key string, delta float64) (float64, error)
+
 	// GetKeysByTag retrieves all keys associated with a given tag.
 	GetKeysByTag(ctx context.Context, tag string) ([]string, error)
+
+	// Flush removes all key-value pairs from the cache.
+	Flush(ctx context.Context) error
+
 	// Ping checks if the cache manager is up and running.
 	Ping() error
 	// Close closes the cache manager.
 	Close() error
 }
 
+// Named is implemented by cache managers that have an intrinsic name, e.g. the driver they are backed by.
+type Named interface {
+	// Name returns the intrinsic name of the cache manager.
+	Name() string
+}
+
+// EffectiveKeyer is implemented by cache managers that rewrite keys before storing them, e.g. by adding a prefix.
+type EffectiveKeyer interface {
+	// EffectiveKey returns the key as it is stored in the backend.
+	EffectiveKey(key string) string
+}
+
+// Metadata describes a stored value.
+type Metadata struct {
+	// TTLRemaining is the remaining time-to-live of the value. Zero or negative if the value does not expire.
+	TTLRemaining time.Duration
+}
+
+// MetadataGetter is implemented by cache managers that can report the metadata of a value together with the value.
+type MetadataGetter interface {
+	// GetWithMetadata retrieves a value like Get and returns its metadata.
+	GetWithMetadata(ctx context.Context, key string, value interface{}) (Metadata, error)
+}
+
+// BatchTTLGetter is implemented by cache managers that can report the remaining time-to-live of several
+// values together with the values, e.g. in a single round trip.
+type BatchTTLGetter interface {
+	// MGetWithTTL retrieves the values like MGet and returns their remaining time-to-live as GetWithTTL does.
+	MGetWithTTL(ctx context.Context, keys []string, values []interface{}) ([]time.Duration, error)
+}
+
+// StreamCacher is implemented by cache managers that can store large values from a stream
+// without the caller holding them in memory as a whole.
+type StreamCacher interface {
+	// SetStream stores the content read from r under the key with the specified ttl and tags.
+	SetStream(ctx context.Context, key string, r io.Reader, ttl time.Duration, tags []string) error
+
+	// GetStream returns a reader over the content stored under the key. The caller must close it.
+	GetStream(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// Prefetcher is implemented by cache managers that can prepare keys the caller is about to read,
+// e.g. the next page of a sequentially read list.
+type Prefetcher interface {
+	// PrefetchHint marks the keys as about to be read. It returns without waiting and without values.
+	PrefetchHint(ctx context.Context, keys ...string)
+}
+
+// Scanner is implemented by cache managers that can list their keys.
+type Scanner interface {
+	// Scan calls fn for each key matching the glob pattern, e.g. "user:*". Keys are passed without the
+	// prefix of the cache manager. Scanning stops at the first error returned by fn, which is returned.
+	Scan(ctx context.Context, pattern string, fn func(key string) error) error
+}
+
+// ExtendedCacher is implemented by cache managers with additional atomic primitives.
+type ExtendedCacher interface {
+	Cacher
+
+	// SetNX stores the value only if the key does not exist, as one atomic operation, and reports
+	// whether it was stored. It is suitable as a simple lock. Tags are not supported for NX operations;
+	// use SetIfAbsent to store a tagged value.
+	SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
+}
+
 // Manager is an interface that defines all operations a cache  manager should support.
 type Manager interface {
 	// Register adds a cache manager to the  manager and assigns it a name.
 	Register(name string, manager Cacher)
 
+	// WrapAll replaces every registered cache manager with the result of wrapFn, e.g. to add tracing.
+	// Cache managers registered later are not wrapped.
+	WrapAll(wrapFn func(Cacher) Cacher)
+
+	// Namespace returns a manager that scopes every key and tag to "prefix:" and otherwise shares this one.
+	Namespace(prefix string) Manager
+
+	// BroadcastSet stores a key-value pair in ALL cache managers. Partial failures are joined into one error.
+	BroadcastSet(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error
+
+	// BroadcastRemove removes a key from ALL cache managers. Partial failures are joined into one error.
+	BroadcastRemove(ctx context.Context, key string) error
+
+	// Locker returns the distributed lock of the manager, if the current cache manager or the options provide one.
+	Locker() (Locker, bool)
+
 	// Use retrieves a registered cache manager by its name.
 	Use(name string) Cacher
 
@@ -59,6 +189,52 @@ type Manager interface {
 	// Close closes ALL cache managers.
 	Close() error
 
+	// SetTTL stores a key-value pair like Set, with a named TTL such as TTLLong.
+	SetTTL(ctx context.Context, key string, value interface{}, ttl TTL, tags []string) error
+
+	// SetMany stores multiple items, using a batch operation when the current cache manager supports it.
+	// A returned MultiError holds the error of each item by index.
+	SetMany(ctx context.Context, items ...CacheItem) error
+
+	// GetOrCreate retrieves a value and, on a miss, populates it with a single create call per key.
+	GetOrCreate(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string, create func(ctx context.Context) (interface{}, error)) error
+
+	// GetOrSet retrieves a value and, on a miss, stores the result of loader and decodes it into value.
+	// Concurrent calls for the same key share one loader call unless disabled with WithSingleFlight(false).
+	GetOrSet(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string, loader func(ctx context.Context) (interface{}, error)) error
+
+	// GetPER retrieves a value and recomputes it with loader before it expires, with a probability
+	// growing as the expiry approaches (probabilistic early expiration). A beta of zero means DefaultPERBeta.
+	GetPER(ctx context.Context, key string, value interface{}, beta float64, loader PERLoader) error
+
+	// GetKeyVersion returns the version counter stored under VersionKey(baseKey), or zero if it was never set.
+	// Combine it with VersionedKey to address the content of the current version.
+	GetKeyVersion(ctx context.Context, baseKey string) (uint64, error)
+
+	// WatchKeyVersion sends each new version of baseKey, polled every check interval, until ctx is done.
+	WatchKeyVersion(ctx context.Context, baseKey string) (<-chan uint64, error)
+
+	// ForceRemoveByTag removes all keys associated with the tag in batches, bypassing the tag circuit breaker.
+	ForceRemoveByTag(ctx context.Context, tag string, batchSize int) error
+
+	// AllBackendInfo returns the backend info of all registered cache managers that provide it.
+	AllBackendInfo(ctx context.Context) (map[string]BackendInfo, error)
+
+	// Inspect dumps the full metadata of a key for debugging: raw bytes, decoded value, TTL, tags and more.
+	Inspect(ctx context.Context, key string) (*InspectResult, error)
+
+	// EffectiveKey returns the fully-qualified key the current cache manager stores for key. Useful for debugging.
+	EffectiveKey(key string) string
+
+	// Stats returns counters of the manager's own activity.
+	Stats() Stats
+
+	// SetReadOnly toggles the read-only mode, in which all writes are rejected with ErrReadOnly.
+	SetReadOnly(readOnly bool)
+
+	// IsReadOnly reports whether the manager rejects writes.
+	IsReadOnly() bool
+
 	// Chain creates a new ChainedManager that can be used to chain multiple cache managers together.
 	Chain() ChainedManager
 
@@ -66,6 +242,16 @@ type Manager interface {
 	Cacher
 }
 
+// GetResult is the result of a single key of ChainedManager.GetBatchWithSource.
+type GetResult struct {
+	// Value is the decoded value, or nil if the key was not found.
+	Value interface{}
+	// Driver is the name of the cache manager that served the key, or empty if the key was not found.
+	Driver string
+	// TTLRemaining is the remaining time-to-live of the key, if known.
+	TTLRemaining time.Duration
+}
+
 // ChainedManager is a cache manager that allows multiple cache managers to be chained together.
 type ChainedManager interface {
 	Manager
@@ -74,4 +260,40 @@ type ChainedManager interface {
 	AddToChain(name string)
 	RemoveFromChain(name string)
 	Override(names ...string) ChainedManager
+
+	// SetReadThrough enables or disables copying values found by Get in a later cache manager of the chain
+	// into the earlier ones, for promoteTTL or, if it is zero, for the remaining TTL of the value.
+	SetReadThrough(enabled bool, promoteTTL time.Duration)
+
+	// SetLayerTTLScale multiplies the TTL of values written to the named cache manager of the chain by factor.
+	// Factors above 1 are valid, e.g. for backup layers. A factor of zero means the minimum layer TTL.
+	SetLayerTTLScale(name string, factor float64)
+
+	// SetMinLayerTTL sets the TTL of layers whose TTL scale is zero.
+	SetMinLayerTTL(ttl time.Duration)
+
+	// SetAsync marks the named cache manager of the chain as async: Set, Remove and RemoveByTag are queued
+	// for it instead of awaited. Close waits for the queued operations.
+	SetAsync(name string, async bool)
+
+	// SetAsyncWorkers sets the number of workers running the operations of async cache managers.
+	SetAsyncWorkers(n int)
+
+	// SetAsyncErrorHandler sets the handler of errors of async operations. By default they are logged.
+	SetAsyncErrorHandler(handler AsyncErrorHandler)
+
+	// GetBatchWithSource retrieves the keys from the chain and reports which cache manager served each of them.
+	// factory must return a pointer to decode a value into.
+	GetBatchWithSource(ctx context.Context, keys []string, factory func() interface{}) (map[string]GetResult, error)
+
+	// SetWarmWorkers sets the number of concurrent workers used to warm the chain.
+	SetWarmWorkers(n int)
+
+	// WarmChainFromTail copies the given keys from the last cache manager in the chain into all earlier ones.
+	// Keys missing from the last cache manager are skipped. Values are copied serialized between cache managers
+	// of the same EncodedCacher format, and decoded into an interface{} otherwise.
+	WarmChainFromTail(ctx context.Context, keys []string) error
+
+	// WarmChainByTag warms the chain with all keys the last cache manager has associated with the tag.
+	WarmChainByTag(ctx context.Context, tag string) error
 }
@@ -0,0 +1,144 @@
+// Package compress compresses stored values with gzip, Zstandard or LZ4, and detects the algorithm from
+// the magic bytes on read, so that drivers can mix compressed and uncompressed entries.
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+)
+
+// Algo is a compression algorithm.
+type Algo int
+
+const (
+	Gzip Algo = iota
+	Zstd
+	LZ4
+)
+
+// ErrNotCompressed is returned by NewReader for data without known magic bytes.
+var ErrNotCompressed = errors.New("data is not compressed")
+
+// The magic bytes each algorithm starts its output with.
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	lz4Magic  = []byte{0x04, 0x22, 0x4d, 0x18}
+)
+
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+)
+
+// zstdCodecs returns the shared zstd encoder and decoder, which are safe for concurrent EncodeAll and DecodeAll.
+func zstdCodecs() (*zstd.Encoder, *zstd.Decoder) {
+	zstdOnce.Do(
+		func() {
+			// Neither call fails without options.
+			zstdEncoder, _ = zstd.NewWriter(nil)
+			zstdDecoder, _ = zstd.NewReader(nil)
+		},
+	)
+
+	return zstdEncoder, zstdDecoder
+}
+
+// Detect reports the algorithm the data was compressed with, if any.
+func Detect(data []byte) (Algo, bool) {
+	switch {
+	case len(data) > len(gzipMagic) && bytes.HasPrefix(data, gzipMagic):
+		return Gzip, true
+	case len(data) > len(zstdMagic) && bytes.HasPrefix(data, zstdMagic):
+		return Zstd, true
+	case len(data) > len(lz4Magic) && bytes.HasPrefix(data, lz4Magic):
+		return LZ4, true
+	default:
+		return 0, false
+	}
+}
+
+// Compress compresses the data with the algorithm.
+func Compress(algo Algo, data []byte) ([]byte, error) {
+	if algo == Zstd {
+		encoder, _ := zstdCodecs()
+		return encoder.EncodeAll(data, nil), nil
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(algo, &buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress decompresses the data with the algorithm detected from it.
+func Decompress(data []byte) ([]byte, error) {
+	if algo, _ := Detect(data); algo == Zstd {
+		_, decoder := zstdCodecs()
+		return decoder.DecodeAll(data, nil)
+	}
+
+	r, err := NewReader(data)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, r); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// NewWriter returns a writer compressing to w with the algorithm.
+func NewWriter(algo Algo, w io.Writer) (io.WriteCloser, error) {
+	switch algo {
+	case Gzip:
+		return gzip.NewWriter(w), nil
+	case Zstd:
+		return zstd.NewWriter(w)
+	case LZ4:
+		return lz4.NewWriter(w), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression %d", int(algo))
+	}
+}
+
+// NewReader returns a reader over the decompressed data, detecting the algorithm from it.
+func NewReader(data []byte) (io.ReadCloser, error) {
+	algo, ok := Detect(data)
+	if !ok {
+		return nil, ErrNotCompressed
+	}
+
+	switch algo {
+	case Zstd:
+		r, err := zstd.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		return r.IOReadCloser(), nil
+	case LZ4:
+		return io.NopCloser(lz4.NewReader(bytes.NewReader(data))), nil
+	default:
+		return gzip.NewReader(bytes.NewReader(data))
+	}
+}
@@ -0,0 +1,40 @@
+// Package keybuf builds cache keys from pooled buffers to keep allocations off the hot path.
+package keybuf
+
+import "sync"
+
+// bufferSize covers typical keys without growing.
+const bufferSize = 128
+
+var pool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 0, bufferSize)
+		return &buf
+	},
+}
+
+// Join returns the parts joined by sep, allocating only the resulting string.
+//
+// strings.Builder is not pooled on purpose: its String shares the buffer and Reset drops it, so a pooled
+// builder never reuses memory. The bytes are copied out here instead, which makes reusing the buffer safe.
+func Join(sep string, parts ...string) string {
+	bufPtr := pool.Get().(*[]byte)
+	buf := (*bufPtr)[:0]
+
+	for i, part := range parts {
+		if i > 0 {
+			buf = append(buf, sep...)
+		}
+		buf = append(buf, part...)
+	}
+
+	key := string(buf)
+
+	// Oversized buffers are dropped so a single huge key doesn't pin memory in the pool.
+	if cap(buf) <= 4*bufferSize {
+		*bufPtr = buf
+		pool.Put(bufPtr)
+	}
+
+	return key
+}
@@ -0,0 +1,169 @@
+// Package invalidation persists invalidation events so that nodes which were offline
+// when an invalidation happened can replay it on startup.
+package invalidation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+const (
+	OpRemove      = "remove"
+	OpRemoveByTag = "remove_by_tag"
+
+	// logTTL is the time-to-live of the stored log. It is refreshed on every write.
+	logTTL = 7 * 24 * time.Hour
+)
+
+// Event is a single invalidation recorded in the log.
+type Event struct {
+	Key       string `json:"key,omitempty"`
+	Tag       string `json:"tag,omitempty"`
+	Op        string `json:"op"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// InvalidationLog records invalidation events in a shared cacher and applies them to a local one.
+//
+// The events are stored as a single list under logKey, newest first, and trimmed to maxLen entries.
+// Since cachemar.Cacher has no list operations the list is updated with a read-modify-write,
+// so concurrent writers on different nodes may lose events.
+type InvalidationLog struct {
+	mu     sync.Mutex
+	store  cachemar.Cacher
+	local  cachemar.Cacher
+	logKey string
+	maxLen int
+}
+
+// Log creates an InvalidationLog stored in cacher under logKey, keeping at most maxLen events.
+// Invalidations are applied to cacher as well, unless WithLocal sets a different target.
+func Log(cacher cachemar.Cacher, logKey string, maxLen int) *InvalidationLog {
+	return &InvalidationLog{
+		store:  cacher,
+		local:  cacher,
+		logKey: logKey,
+		maxLen: maxLen,
+	}
+}
+
+// WithLocal sets the cacher invalidations are applied to.
+func (l *InvalidationLog) WithLocal(local cachemar.Cacher) *InvalidationLog {
+	l.local = local
+	return l
+}
+
+// Remove removes the key from the local cacher and records the event.
+func (l *InvalidationLog) Remove(ctx context.Context, key string) error {
+	if err := l.local.Remove(ctx, key); err != nil {
+		return err
+	}
+
+	return l.push(ctx, Event{Key: key, Op: OpRemove, Timestamp: time.Now().UnixNano()})
+}
+
+// RemoveByTag removes the tagged keys from the local cacher and records the event.
+func (l *InvalidationLog) RemoveByTag(ctx context.Context, tag string) error {
+	if err := l.local.RemoveByTag(ctx, tag); err != nil {
+		return err
+	}
+
+	return l.push(ctx, Event{Tag: tag, Op: OpRemoveByTag, Timestamp: time.Now().UnixNano()})
+}
+
+// ReplayLog applies all events recorded since the given time to the local cacher, oldest first.
+func (l *InvalidationLog) ReplayLog(ctx context.Context, since time.Time) error {
+	events, err := l.events(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := len(events) - 1; i >= 0; i-- {
+		event := events[i]
+		if event.Timestamp < since.UnixNano() {
+			continue
+		}
+
+		switch event.Op {
+		case OpRemove:
+			err = l.local.Remove(ctx, event.Key)
+		case OpRemoveByTag:
+			err = l.local.RemoveByTag(ctx, event.Tag)
+		default:
+			err = fmt.Errorf("unknown invalidation op %q", event.Op)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to replay invalidation event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// TrimLog keeps only the keepLast most recent events.
+func (l *InvalidationLog) TrimLog(ctx context.Context, keepLast int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	events, err := l.events(ctx)
+	if err != nil {
+		return err
+	}
+
+	return l.save(ctx, trim(events, keepLast))
+}
+
+func (l *InvalidationLog) push(ctx context.Context, event Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	events, err := l.events(ctx)
+	if err != nil {
+		return err
+	}
+
+	events = append([]Event{event}, events...)
+
+	return l.save(ctx, trim(events, l.maxLen))
+}
+
+func (l *InvalidationLog) events(ctx context.Context) ([]Event, error) {
+	exists, err := l.store.Exists(ctx, l.logKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read invalidation log: %w", err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	var events []Event
+	if err := l.store.Get(ctx, l.logKey, &events); err != nil {
+		if errors.Is(err, cachemar.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read invalidation log: %w", err)
+	}
+
+	return events, nil
+}
+
+func (l *InvalidationLog) save(ctx context.Context, events []Event) error {
+	if err := l.store.Set(ctx, l.logKey, events, logTTL, nil); err != nil {
+		return fmt.Errorf("failed to write invalidation log: %w", err)
+	}
+
+	return nil
+}
+
+func trim(events []Event, maxLen int) []Event {
+	if maxLen > 0 && len(events) > maxLen {
+		return events[:maxLen]
+	}
+
+	return events
+}
@@ -0,0 +1,89 @@
+package cachemar
+
+import (
+	"fmt"
+	"strings"
+)
+
+// keySeparator joins the segments of keys built by KeyBuilder.
+const keySeparator = ":"
+
+type segmentKind int
+
+const (
+	prefixSegment segmentKind = iota
+	entitySegment
+	idSegment
+	fieldSegment
+)
+
+type keySegment struct {
+	kind  segmentKind
+	value string
+}
+
+// KeyBuilder builds structured keys such as "app:user:42:profile". It is immutable: every method
+// returns a new builder, so a partially built one can be reused as a base.
+type KeyBuilder struct {
+	segments []keySegment
+}
+
+func (b KeyBuilder) with(kind segmentKind, value string) *KeyBuilder {
+	segments := make([]keySegment, len(b.segments), len(b.segments)+1)
+	copy(segments, b.segments)
+
+	return &KeyBuilder{segments: append(segments, keySegment{kind: kind, value: value})}
+}
+
+// Prefix appends a namespace segment, e.g. the application name.
+func (b KeyBuilder) Prefix(p string) *KeyBuilder {
+	return b.with(prefixSegment, p)
+}
+
+// Entity appends an entity name segment, e.g. "user".
+func (b KeyBuilder) Entity(name string) *KeyBuilder {
+	return b.with(entitySegment, name)
+}
+
+// ID appends an entity identifier segment.
+func (b KeyBuilder) ID(id interface{}) *KeyBuilder {
+	return b.with(idSegment, fmt.Sprint(id))
+}
+
+// Field appends a field segment, e.g. "profile".
+func (b KeyBuilder) Field(field string) *KeyBuilder {
+	return b.with(fieldSegment, field)
+}
+
+// Build joins the segments with ":".
+func (b KeyBuilder) Build() string {
+	values := make([]string, 0, len(b.segments))
+	for _, segment := range b.segments {
+		values = append(values, segment.value)
+	}
+
+	return strings.Join(values, keySeparator)
+}
+
+// MustBuild is like Build but panics if the key is empty.
+func (b KeyBuilder) MustBuild() string {
+	key := b.Build()
+	if key == "" {
+		panic("cachemar: empty key")
+	}
+
+	return key
+}
+
+// Tags returns the key up to every entity and ID segment, so all keys of an entity type or of a single
+// entity can be invalidated together. For "app:user:42:profile" these are "app:user" and "app:user:42".
+func (b KeyBuilder) Tags() []string {
+	tags := make([]string, 0)
+	for i, segment := range b.segments {
+		if segment.kind == entitySegment || segment.kind == idSegment {
+			tags = append(tags, KeyBuilder{segments: b.segments[:i+1]}.Build())
+		}
+	}
+
+	return tags
+}
@@ -0,0 +1,63 @@
+package cachemar
+
+import "strings"
+
+// KeyTransformer normalizes a cache key before it is forwarded to a cache manager.
+type KeyTransformer interface {
+	// Transform returns the normalized key.
+	Transform(key string) string
+}
+
+type keyTransformer struct {
+	transformers []func(string) string
+}
+
+// NewKeyTransformer returns a KeyTransformer applying the given transformers in sequence.
+func NewKeyTransformer(transformers ...func(string) string) KeyTransformer {
+	return &keyTransformer{transformers: transformers}
+}
+
+func (t *keyTransformer) Transform(key string) string {
+	for _, transform := range t.transformers {
+		key = transform(key)
+	}
+
+	return key
+}
+
+// LowerCaseKey converts the key to lower case.
+func LowerCaseKey(key string) string {
+	return strings.ToLower(key)
+}
+
+// SnakeCaseKey replaces spaces and hyphens with underscores.
+func SnakeCaseKey(key string) string {
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(key)
+}
+
+// TrimKey trims leading and trailing whitespace.
+func TrimKey(key string) string {
+	return strings.TrimSpace(key)
+}
+
+// TruncateKey returns a transformer truncating keys to n bytes.
+func TruncateKey(n int) func(string) string {
+	return func(key string) string {
+		if len(key) > n {
+			return key[:n]
+		}
+
+		return key
+	}
+}
+
+// HashLongKeys returns a transformer replacing keys longer than threshold with their SHA-256 hash.
+func HashLongKeys(threshold int) func(string) string {
+	return func(key string) string {
+		if len(key) > threshold {
+			return HashKeySHA256("sha256", key)
+		}
+
+		return key
+	}
+}
@@ -0,0 +1,52 @@
+package cachemar
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// KeyValidator checks a cache key before it is forwarded to a cache manager.
+type KeyValidator interface {
+	// Validate returns an error if the key must not be used.
+	Validate(key string) error
+}
+
+type piiPattern struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// piiSafeKeyValidator rejects keys that look like they embed personal data.
+type piiSafeKeyValidator struct {
+	patterns []piiPattern
+}
+
+// NewPIISafeKeyValidator returns a KeyValidator that rejects keys containing
+// email addresses, long numeric sequences (SSN, card numbers) or an "@" sign.
+func NewPIISafeKeyValidator() KeyValidator {
+	return &piiSafeKeyValidator{
+		patterns: []piiPattern{
+			{name: "email address", pattern: regexp.MustCompile("[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9-]+(\\.[a-zA-Z0-9-]+)*")},
+			{name: "numeric sequence", pattern: regexp.MustCompile(`[0-9]{10,}`)},
+			{name: "@ sign", pattern: regexp.MustCompile(`@`)},
+		},
+	}
+}
+
+// Validate names the pattern the key matched, but not the key, so the personal data stays out of the errors.
+func (v *piiSafeKeyValidator) Validate(key string) error {
+	for _, p := range v.patterns {
+		if p.pattern.MatchString(key) {
+			return fmt.Errorf("%w: key contains %s", ErrInvalidKey, p.name)
+		}
+	}
+
+	return nil
+}
+
+// NoopKeyValidator accepts every key. Useful in tests and environments that don't need PII protection.
+type NoopKeyValidator struct{}
+
+func (NoopKeyValidator) Validate(key string) error {
+	return nil
+}
@@ -0,0 +1,32 @@
+package cachemar
+
+import (
+	"context"
+	"time"
+)
+
+// Locker is a distributed lock used to coordinate work across processes.
+type Locker interface {
+	// LockKey acquires the lock for key and returns a token identifying the holder.
+	// It returns ErrLockNotAcquired if the lock is held by someone else.
+	LockKey(ctx context.Context, key string, ttl time.Duration) (lockToken string, err error)
+
+	// UnlockKey releases the lock for key if it is still held with lockToken.
+	UnlockKey(ctx context.Context, key string, lockToken string) error
+}
+
+// Locker returns the locker set with WithDistributedSingleflight or, if there is none, the current cache
+// manager if it implements Locker, like the Redis driver.
+func (c *manager) Locker() (Locker, bool) {
+	if c.locker != nil {
+		return c.locker, true
+	}
+
+	locker, ok := As[Locker](c.Current())
+	return locker, ok
+}
+
+// Locker returns the locker of the manager of the chain.
+func (c *chained) Locker() (Locker, bool) {
+	return c.m.Locker()
+}
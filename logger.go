@@ -0,0 +1,18 @@
+package cachemar
+
+// Logger receives the diagnostics of the manager. Fields are alternating keys and values, as in
+// log/slog, e.g. "operation", "Get", "key", key. See contrib/logging for adapters.
+type Logger interface {
+	// Debug logs an event that is expected in normal operation, such as a miss in a chained cache manager.
+	Debug(msg string, fields ...interface{})
+
+	// Error logs a failure the manager recovered from, such as an unavailable cache manager in a chain.
+	Error(msg string, fields ...interface{})
+}
+
+// noopLogger discards everything. It is the default Logger.
+type noopLogger struct{}
+
+func (noopLogger) Debug(string, ...interface{}) {}
+
+func (noopLogger) Error(string, ...interface{}) {}
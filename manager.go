@@ -2,30 +2,93 @@ package cachemar
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"sync/atomic"
 	"time"
+
+	"golang.org/x/sync/singleflight"
 )
 
 // manager is an implementation of the Manager interface.
 type manager struct {
-	managers      map[string]Cacher // A map to store registered cache managers with their names as keys.
-	current       string            // The name of the current cache manager being used.
-	chainInstance ChainedManager    // The chained manager instance.
+	managers       map[string]Cacher  // A map to store registered cache managers with their names as keys.
+	current        string             // The name of the current cache manager being used.
+	chainInstance  ChainedManager     // The chained manager instance.
+	keyValidator   KeyValidator       // Optional validator applied to every key.
+	keyTransformer KeyTransformer     // Optional transformer normalizing every key.
+	globalPrefix   string             // Optional prefix prepended to every key and tag.
+	maxTagSize     int                // Tags with more keys are rejected by tag operations. Zero disables the check.
+	group          singleflight.Group // Coalesces concurrent GetOrCreate calls for the same key.
+	singleFlight   bool               // Whether group is used. Enabled by default.
+	locker         Locker             // Optional distributed lock used by GetOrCreate.
+	readOnly       atomic.Bool        // Rejects all writes with ErrReadOnly when set.
+	perDeltas      perDeltas          // Duration of the last GetPER load of each key.
+	checkInterval  time.Duration      // How often background checks such as WatchKeyVersion poll.
+	logger         Logger             // Receives diagnostics. Defaults to noopLogger.
+	parent         *manager           // The manager a namespace was created from, which holds the current cache manager and the read-only mode.
+
+	autoRemoveCorrupt bool          // Get removes entries that can't be decoded.
+	corruptRemoved    atomic.Uint64 // Number of entries removed by autoRemoveCorrupt.
+
+	circuitFallback  string       // Cache manager used while the circuit of the current one is open. Empty disables the breaker.
+	circuitState     atomic.Int32 // The circuitState of the current cache manager.
+	circuitSince     atomic.Int64 // Unix nanoseconds of the last state change or ping, which the check interval counts from.
+	halfOpenInFlight atomic.Bool  // Whether the probe of the half-open circuit is running.
+	failures         *failureRing // Recent failures of the current cache manager. Nil without a failure threshold.
+
+	onCircuitOpen     func(primaryCacher string) // Optional callbacks of the circuit state transitions.
+	onCircuitClose    func(primaryCacher string)
+	onCircuitHalfOpen func(primaryCacher string)
 }
 
 // New creates and returns a new instance of the manager.
 func New() Manager {
-	return &manager{
-		managers: make(map[string]Cacher),
+	return NewWithOptions()
+}
+
+// NewWithOptions creates and returns a new instance of the manager configured with the given options.
+func NewWithOptions(opts ...Option) Manager {
+	m := &manager{
+		managers:      make(map[string]Cacher),
+		checkInterval: DefaultCheckInterval,
+		singleFlight:  true,
+		logger:        noopLogger{},
+	}
+
+	for _, opt := range opts {
+		opt(m)
 	}
+
+	return m
 }
 
 // Register adds a cache manager to the manager  and assigns it a name.
 func (c *manager) Register(name string, manager Cacher) {
+	if c.parent != nil {
+		c.parent.Register(name, manager)
+		return
+	}
+
+	if named, ok := As[Named](manager); ok && named.Name() != name {
+		c.logger.Error(
+			"cachemar: registering cache manager under conflicting name",
+			"operation", "Register", "cacher", name, "driver", named.Name(),
+		)
+	}
+
 	c.managers[name] = manager
 	c.current = name
 }
 
+// WrapAll replaces every registered cache manager with the result of wrapFn.
+func (c *manager) WrapAll(wrapFn func(Cacher) Cacher) {
+	for name, manager := range c.managers {
+		c.managers[name] = wrapFn(manager)
+	}
+}
+
 // Use retrieves a registered cache manager by its name. Returns nil if the manager is not found.
 func (c *manager) Use(name string) Cacher {
 	manager, ok := c.managers[name]
@@ -38,57 +101,427 @@ func (c *manager) Use(name string) Cacher {
 
 // Current retrieves the current cache manager being used by the manager .
 func (c *manager) Current() Cacher {
-	return c.managers[c.current]
+	return c.managers[c.currentName()]
+}
+
+// currentName returns the name of the current cache manager, which namespaces take from their parent.
+func (c *manager) currentName() string {
+	if c.parent != nil {
+		return c.parent.current
+	}
+
+	return c.current
 }
 
 // SetCurrent sets the current cache manager the manager  should use.
 func (c *manager) SetCurrent(name string) {
+	if c.parent != nil {
+		c.parent.SetCurrent(name)
+		return
+	}
+
 	c.current = name
 }
 
 // Set forwards the "Set" operation to the current cache manager.
-func (c *manager) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
-	return c.Current().Set(ctx, key, value, ttl, tags)
+func (c *manager) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (err error) {
+	if c.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	key, err = c.prepareKey(key)
+	if err != nil {
+		return err
+	}
+
+	cacher, done := c.active()
+	defer done(&err)
+
+	return cacher.Set(ctx, key, value, ttl, c.prepareTags(tags))
+}
+
+// SetOpts forwards the "SetOpts" operation to the current cache manager.
+func (c *manager) SetOpts(ctx context.Context, key string, value interface{}, opts SetOptions) (err error) {
+	if c.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	key, err = c.prepareKey(key)
+	if err != nil {
+		return err
+	}
+	opts.Tags = c.prepareTags(opts.Tags)
+
+	cacher, done := c.active()
+	defer done(&err)
+
+	return cacher.SetOpts(ctx, key, value, opts)
+}
+
+// SetIfAbsent forwards the "SetIfAbsent" operation to the current cache manager.
+func (c *manager) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (written bool, err error) {
+	if c.IsReadOnly() {
+		return false, ErrReadOnly
+	}
+
+	key, err = c.prepareKey(key)
+	if err != nil {
+		return false, err
+	}
+
+	cacher, done := c.active()
+	defer done(&err)
+
+	return cacher.SetIfAbsent(ctx, key, value, ttl, c.prepareTags(tags))
+}
+
+// SetIfPresent forwards the "SetIfPresent" operation to the current cache manager.
+func (c *manager) SetIfPresent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (written bool, err error) {
+	if c.IsReadOnly() {
+		return false, ErrReadOnly
+	}
+
+	key, err = c.prepareKey(key)
+	if err != nil {
+		return false, err
+	}
+
+	cacher, done := c.active()
+	defer done(&err)
+
+	return cacher.SetIfPresent(ctx, key, value, ttl, c.prepareTags(tags))
+}
+
+// SetMany forwards the items to the current cache manager's "MSet".
+func (c *manager) SetMany(ctx context.Context, items ...CacheItem) error {
+	if c.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	errs := make(MultiError, len(items))
+	prepared := make([]CacheItem, 0, len(items))
+	indexes := make([]int, 0, len(items))
+
+	for i, item := range items {
+		key, err := c.prepareKey(item.Key)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		item.Key = key
+		item.Tags = c.prepareTags(item.Tags)
+		prepared = append(prepared, item)
+		indexes = append(indexes, i)
+	}
+
+	cacher, done := c.active()
+	err := cacher.MSet(ctx, prepared)
+	done(&err)
+
+	var batchErrs MultiError
+	isMulti := errors.As(err, &batchErrs) && len(batchErrs) == len(prepared)
+	for j, i := range indexes {
+		if isMulti {
+			errs[i] = batchErrs[j]
+		} else {
+			errs[i] = err
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// MSet stores the items like SetMany.
+func (c *manager) MSet(ctx context.Context, items []CacheItem) error {
+	return c.SetMany(ctx, items...)
 }
 
 // Get forwards the "Get" operation to the current cache manager.
-func (c *manager) Get(ctx context.Context, key string, value interface{}) error {
-	return c.Current().Get(ctx, key, value)
+func (c *manager) Get(ctx context.Context, key string, value interface{}) (err error) {
+	key, err = c.prepareKey(key)
+	if err != nil {
+		return err
+	}
+
+	cacher, done := c.active()
+	defer done(&err)
+
+	return c.removeCorrupt(ctx, cacher, key, cacher.Get(ctx, key, value))
+}
+
+// GetWithTTL forwards the "GetWithTTL" operation to the current cache manager.
+func (c *manager) GetWithTTL(ctx context.Context, key string, value interface{}) (ttl time.Duration, err error) {
+	key, err = c.prepareKey(key)
+	if err != nil {
+		return 0, err
+	}
+
+	cacher, done := c.active()
+	defer done(&err)
+
+	ttl, err = cacher.GetWithTTL(ctx, key, value)
+	return ttl, c.removeCorrupt(ctx, cacher, key, err)
+}
+
+// GetAndDelete forwards the "GetAndDelete" operation to the current cache manager.
+func (c *manager) GetAndDelete(ctx context.Context, key string, value interface{}) (err error) {
+	if c.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	key, err = c.prepareKey(key)
+	if err != nil {
+		return err
+	}
+
+	cacher, done := c.active()
+	defer done(&err)
+
+	return cacher.GetAndDelete(ctx, key, value)
+}
+
+// Touch forwards the "Touch" operation to the current cache manager.
+func (c *manager) Touch(ctx context.Context, key string, ttl time.Duration) (err error) {
+	if c.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	key, err = c.prepareKey(key)
+	if err != nil {
+		return err
+	}
+
+	cacher, done := c.active()
+	defer done(&err)
+
+	return cacher.Touch(ctx, key, ttl)
+}
+
+// removeCorrupt removes the entry under key if err says its value can't be decoded and the manager was
+// created with WithAutoRemoveCorruptEntries, turning err into ErrNotFound. Other errors, including values
+// that don't fit the target, are returned as is.
+func (c *manager) removeCorrupt(ctx context.Context, cacher Cacher, key string, err error) error {
+	if !c.autoRemoveCorrupt || c.IsReadOnly() || !IsDecodeError(err) || isTypeMismatch(err) {
+		return err
+	}
+
+	if removeErr := cacher.Remove(ctx, key); removeErr != nil {
+		c.logger.Error("cachemar: failed to remove corrupt entry", "operation", "Remove", "key", key, "error", removeErr)
+		return err
+	}
+	c.corruptRemoved.Add(1)
+	c.logger.Debug("cachemar: removed corrupt entry", "operation", "Remove", "key", key, "error", err)
+
+	return ErrNotFound
+}
+
+// logLayerError logs the error of a cache manager in a chain that the chain recovers from by trying the next one.
+func (c *manager) logLayerError(op, key, cacherName string, err error) {
+	if IsNotFound(err) {
+		c.logger.Debug("cachemar: cache manager missed", "operation", op, "key", key, "cacher", cacherName)
+		return
+	}
+
+	c.logger.Error("cachemar: cache manager failed", "operation", op, "key", key, "cacher", cacherName, "error", err)
 }
 
 // Remove forwards the "Remove" operation to the current cache manager.
-func (c *manager) Remove(ctx context.Context, key string) error {
-	return c.Current().Remove(ctx, key)
+func (c *manager) Remove(ctx context.Context, key string) (err error) {
+	if c.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	key, err = c.prepareKey(key)
+	if err != nil {
+		return err
+	}
+
+	cacher, done := c.active()
+	defer done(&err)
+
+	return cacher.Remove(ctx, key)
+}
+
+// Flush forwards the "Flush" operation to the current cache manager.
+func (c *manager) Flush(ctx context.Context) (err error) {
+	if c.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	cacher, done := c.active()
+	defer done(&err)
+
+	return cacher.Flush(ctx)
 }
 
 // RemoveByTag forwards the "RemoveByTag" operation to the current cache manager.
-func (c *manager) RemoveByTag(ctx context.Context, tag string) error {
-	return c.Current().RemoveByTag(ctx, tag)
+func (c *manager) RemoveByTag(ctx context.Context, tag string) (err error) {
+	if c.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	tag = c.prepareTag(tag)
+	cacher, done := c.active()
+	defer done(&err)
+
+	if err := c.checkTagSize(ctx, cacher, tag); err != nil {
+		return err
+	}
+
+	return cacher.RemoveByTag(ctx, tag)
 }
 
 // RemoveByTags forwards the "RemoveByTags" operation to the current cache manager.
-func (c *manager) RemoveByTags(ctx context.Context, tags []string) error {
-	return c.Current().RemoveByTags(ctx, tags)
+func (c *manager) RemoveByTags(ctx context.Context, tags []string) (err error) {
+	if c.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	tags = c.prepareTags(tags)
+	cacher, done := c.active()
+	defer done(&err)
+
+	for _, tag := range tags {
+		if err := c.checkTagSize(ctx, cacher, tag); err != nil {
+			return err
+		}
+	}
+
+	return cacher.RemoveByTags(ctx, tags)
 }
 
 // Exists forwards the "Exists" operation to the current cache manager.
-func (c *manager) Exists(ctx context.Context, key string) (bool, error) {
-	return c.Current().Exists(ctx, key)
+func (c *manager) Exists(ctx context.Context, key string) (exists bool, err error) {
+	key, err = c.prepareKey(key)
+	if err != nil {
+		return false, err
+	}
+
+	cacher, done := c.active()
+	defer done(&err)
+
+	return cacher.Exists(ctx, key)
 }
 
 // Increment forwards the "Increment" operation to the current cache manager.
-func (c *manager) Increment(ctx context.Context, key string) error {
-	return c.Current().Increment(ctx, key)
+func (c *manager) Increment(ctx context.Context, key string) (err error) {
+	if c.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	key, err = c.prepareKey(key)
+	if err != nil {
+		return err
+	}
+
+	cacher, done := c.active()
+	defer done(&err)
+
+	return cacher.Increment(ctx, key)
 }
 
 // Decrement forwards the "Decrement" operation to the current cache manager.
-func (c *manager) Decrement(ctx context.Context, key string) error {
-	return c.Current().Decrement(ctx, key)
+func (c *manager) Decrement(ctx context.Context, key string) (err error) {
+	if c.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	key, err = c.prepareKey(key)
+	if err != nil {
+		return err
+	}
+
+	cacher, done := c.active()
+	defer done(&err)
+
+	return cacher.Decrement(ctx, key)
+}
+
+// IncrBy forwards the "IncrBy" operation to the current cache manager.
+func (c *manager) IncrBy(ctx context.Context, key string, delta int64) (value int64, err error) {
+	if c.IsReadOnly() {
+		return 0, ErrReadOnly
+	}
+
+	key, err = c.prepareKey(key)
+	if err != nil {
+		return 0, err
+	}
+
+	cacher, done := c.active()
+	defer done(&err)
+
+	return cacher.IncrBy(ctx, key, delta)
+}
+
+// DecrBy forwards the "DecrBy" operation to the current cache manager.
+func (c *manager) DecrBy(ctx context.Context, key string, delta int64) (value int64, err error) {
+	if c.IsReadOnly() {
+		return 0, ErrReadOnly
+	}
+
+	key, err = c.prepareKey(key)
+	if err != nil {
+		return 0, err
+	}
+
+	cacher, done := c.active()
+	defer done(&err)
+
+	return cacher.DecrBy(ctx, key, delta)
+}
+
+// IncrementFloat forwards the "IncrementFloat" operation to the current cache manager.
+func (c *manager) IncrementFloat(ctx context.Context, key string, delta float64) (value float64, err error) {
+	if c.IsReadOnly() {
+		return 0, ErrReadOnly
+	}
+
+	key, err = c.prepareKey(key)
+	if err != nil {
+		return 0, err
+	}
+
+	cacher, done := c.active()
+	defer done(&err)
+
+	return cacher.IncrementFloat(ctx, key, delta)
+}
+
+// DecrementFloat forwards the "DecrementFloat" operation to the current cache manager.
+func (c *manager) DecrementFloat(ctx context.Context, key string, delta float64) (value float64, err error) {
+	if c.IsReadOnly() {
+		return 0, ErrReadOnly
+	}
+
+	key, err = c.prepareKey(key)
+	if err != nil {
+		return 0, err
+	}
+
+	cacher, done := c.active()
+	defer done(&err)
+
+	return cacher.DecrementFloat(ctx, key, delta)
 }
 
 // GetKeysByTag forwards the "GetKeysByTag" operation to the current cache manager.
-func (c *manager) GetKeysByTag(ctx context.Context, tag string) ([]string, error) {
-	return c.Current().GetKeysByTag(ctx, tag)
+func (c *manager) GetKeysByTag(ctx context.Context, tag string) (keys []string, err error) {
+	tag = c.prepareTag(tag)
+	cacher, done := c.active()
+	defer done(&err)
+
+	if err := c.checkTagSize(ctx, cacher, tag); err != nil {
+		return nil, err
+	}
+
+	keys, err = cacher.GetKeysByTag(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.stripGlobalPrefix(cacher, keys), nil
 }
 
 // Ping forwards the "Ping" operation to the current cache manager.
@@ -109,8 +542,12 @@ func (c *manager) Ping() error {
 	return nil
 }
 
-// Close forwards the "Close" operation to the current cache manager.
+// Close waits for the pending async writes of the chain, and closes ALL cache managers.
 func (d *manager) Close() error {
+	if chain, ok := d.chainInstance.(*chained); ok {
+		chain.pool.close()
+	}
+
 	errors := make([]error, 0)
 
 	for _, manager := range d.managers {
@@ -127,6 +564,25 @@ func (d *manager) Close() error {
 	return nil
 }
 
+// SetReadOnly toggles the read-only mode, in which all writes are rejected with ErrReadOnly.
+func (c *manager) SetReadOnly(readOnly bool) {
+	if c.parent != nil {
+		c.parent.SetReadOnly(readOnly)
+		return
+	}
+
+	c.readOnly.Store(readOnly)
+}
+
+// IsReadOnly reports whether the manager rejects writes.
+func (c *manager) IsReadOnly() bool {
+	if c.parent != nil {
+		return c.parent.IsReadOnly()
+	}
+
+	return c.readOnly.Load()
+}
+
 // Chain returns a ChainedManager instance.
 func (c *manager) Chain() ChainedManager {
 	if c.chainInstance == nil {
@@ -134,3 +590,94 @@ func (c *manager) Chain() ChainedManager {
 	}
 	return c.chainInstance
 }
+
+// prepareKey validates the key and applies the configured key transformer.
+func (c *manager) prepareKey(key string) (string, error) {
+	if c.keyValidator != nil {
+		if err := c.keyValidator.Validate(key); err != nil {
+			return "", err
+		}
+	}
+
+	if c.keyTransformer != nil {
+		key = c.keyTransformer.Transform(key)
+	}
+
+	return c.withGlobalPrefix(key), nil
+}
+
+// prepareTag applies the global prefix to a tag.
+func (c *manager) prepareTag(tag string) string {
+	return c.withGlobalPrefix(tag)
+}
+
+// prepareTags applies the global prefix to all tags.
+func (c *manager) prepareTags(tags []string) []string {
+	if c.globalPrefix == "" || len(tags) == 0 {
+		return tags
+	}
+
+	prepared := make([]string, len(tags))
+	for i, tag := range tags {
+		prepared[i] = c.prepareTag(tag)
+	}
+
+	return prepared
+}
+
+func (c *manager) withGlobalPrefix(s string) string {
+	if c.globalPrefix == "" {
+		return s
+	}
+
+	return c.globalPrefix + ":" + s
+}
+
+// stripGlobalPrefix removes the global prefix from keys returned by cacher. Drivers such as Redis return the keys
+// with their own prefix in front of the global one, so both are removed, as EffectiveKeyer reports them.
+func (c *manager) stripGlobalPrefix(cacher Cacher, keys []string) []string {
+	if c.globalPrefix == "" {
+		return keys
+	}
+
+	prefix := c.globalPrefix + ":"
+	if effective, ok := As[EffectiveKeyer](cacher); ok {
+		prefix = effective.EffectiveKey(prefix)
+	}
+
+	for i, key := range keys {
+		keys[i] = strings.TrimPrefix(key, prefix)
+	}
+
+	return keys
+}
+
+// stripDriverPrefix removes the prefix cacher stores the keys under, as EffectiveKeyer reports it, from keys
+// returned by cacher, leaving them as cacher is given them.
+func stripDriverPrefix(cacher Cacher, keys []string) []string {
+	effective, ok := As[EffectiveKeyer](cacher)
+	if !ok {
+		return keys
+	}
+
+	prefix := effective.EffectiveKey("")
+	for i, key := range keys {
+		keys[i] = strings.TrimPrefix(key, prefix)
+	}
+
+	return keys
+}
+
+// EffectiveKey returns the fully-qualified key the current cache manager stores for key.
+func (c *manager) EffectiveKey(key string) string {
+	if c.keyTransformer != nil {
+		key = c.keyTransformer.Transform(key)
+	}
+	key = c.withGlobalPrefix(key)
+
+	if effective, ok := As[EffectiveKeyer](c.Current()); ok {
+		return effective.EffectiveKey(key)
+	}
+
+	return key
+}
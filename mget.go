@@ -0,0 +1,134 @@
+package cachemar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// mgetInto retrieves the prepared keys from cacher into the values at indexes with a single MGet, and records
+// the error of each key in errs at its index. An error not specific to the keys is recorded for all of them.
+// The error of the MGet is returned.
+func mgetInto(ctx context.Context, cacher Cacher, keys []string, values []interface{}, indexes []int, errs MultiError) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	batchValues := make([]interface{}, len(indexes))
+	for j, i := range indexes {
+		batchValues[j] = values[i]
+	}
+
+	err := cacher.MGet(ctx, keys, batchValues)
+
+	var batchErrs MultiError
+	isMulti := errors.As(err, &batchErrs) && len(batchErrs) == len(keys)
+	for j, i := range indexes {
+		if isMulti {
+			errs[i] = batchErrs[j]
+		} else {
+			errs[i] = err
+		}
+	}
+
+	return err
+}
+
+// mgetWithTTL retrieves the prepared keys from cacher with their remaining time-to-live, in one batch if
+// cacher implements BatchTTLGetter, or else with one GetWithTTL per key. Errors are returned as by MGet.
+func mgetWithTTL(ctx context.Context, cacher Cacher, keys []string, values []interface{}) ([]time.Duration, error) {
+	if getter, ok := As[BatchTTLGetter](cacher); ok {
+		return getter.MGetWithTTL(ctx, keys, values)
+	}
+
+	ttls := make([]time.Duration, len(keys))
+	errs := make(MultiError, len(keys))
+	for i, key := range keys {
+		ttls[i], errs[i] = cacher.GetWithTTL(ctx, key, values[i])
+	}
+
+	return ttls, errs.ErrorOrNil()
+}
+
+// MGet prepares the keys and forwards them to the current cache manager's "MGet".
+func (c *manager) MGet(ctx context.Context, keys []string, values []interface{}) error {
+	if len(keys) != len(values) {
+		return fmt.Errorf("mget: got %d keys and %d values", len(keys), len(values))
+	}
+
+	errs := make(MultiError, len(keys))
+	prepared := make([]string, 0, len(keys))
+	indexes := make([]int, 0, len(keys))
+
+	for i, key := range keys {
+		key, err := c.prepareKey(key)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		prepared = append(prepared, key)
+		indexes = append(indexes, i)
+	}
+
+	// Without a key to get, no cache manager is taken, so a call that does nothing never runs the probe
+	// of the circuit breaker.
+	if len(prepared) > 0 {
+		cacher, done := c.active()
+		err := mgetInto(ctx, cacher, prepared, values, indexes, errs)
+		done(&err)
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// MGet retrieves each key from the first cache manager of the chain, or the fallback, that has it, with one
+// MGet per cache manager for the keys still missing.
+func (c *chained) MGet(ctx context.Context, keys []string, values []interface{}) error {
+	if len(keys) != len(values) {
+		return fmt.Errorf("mget: got %d keys and %d values", len(keys), len(values))
+	}
+
+	errs := make(MultiError, len(keys))
+	prepared := make([]string, 0, len(keys))
+	indexes := make([]int, 0, len(keys))
+
+	for i, key := range keys {
+		key, err := c.m.prepareKey(key)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		prepared = append(prepared, key)
+		indexes = append(indexes, i)
+	}
+
+	sources := c.chain
+	if c.fallback != "" {
+		sources = append(append([]string{}, c.chain...), c.fallback)
+	}
+
+	for _, managerName := range sources {
+		mgetInto(ctx, c.m.managers[managerName], prepared, values, indexes, errs)
+
+		var missingKeys []string
+		var missingIndexes []int
+		for j, i := range indexes {
+			if errs[i] != nil {
+				missingKeys = append(missingKeys, prepared[j])
+				missingIndexes = append(missingIndexes, i)
+			}
+		}
+		prepared, indexes = missingKeys, missingIndexes
+	}
+
+	if c.fallback == "" {
+		for _, i := range indexes {
+			errs[i] = fmt.Errorf("value %w in any cache manager", ErrNotFound)
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
@@ -0,0 +1,38 @@
+// Package must provides panicking helpers around cachemar.Cacher for use in tests and init code,
+// where an error represents a programming mistake rather than a condition to handle.
+package must
+
+import (
+	"context"
+	"time"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+// MustGet retrieves the value stored under key and panics if it can't be retrieved.
+func MustGet[T any](ctx context.Context, c cachemar.Cacher, key string) T {
+	var value T
+	if err := c.Get(ctx, key, &value); err != nil {
+		panic(err)
+	}
+
+	return value
+}
+
+// MustSet stores the value under key and panics on error.
+func MustSet(ctx context.Context, c cachemar.Cacher, key string, value interface{}, ttl time.Duration, tags []string) {
+	if err := c.Set(ctx, key, value, ttl, tags); err != nil {
+		panic(err)
+	}
+}
+
+// RecoverGet behaves like MustGet but recovers from the panic and returns defaultVal instead.
+func RecoverGet[T any](ctx context.Context, c cachemar.Cacher, key string, defaultVal T) (value T) {
+	defer func() {
+		if r := recover(); r != nil {
+			value = defaultVal
+		}
+	}()
+
+	return MustGet[T](ctx, c, key)
+}
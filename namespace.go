@@ -0,0 +1,33 @@
+package cachemar
+
+// Namespace returns a manager that prepends "prefix:" to every key and tag before forwarding the operation
+// to the current cache manager, so that subsystems sharing a cache can't overwrite each other's keys.
+// Keys returned by GetKeysByTag are stripped of the prefix again. Namespaces nest, and inherit the options
+// of c. They share the registered cache managers, the current one and the read-only mode with c, so
+// Register, SetCurrent and SetReadOnly on a namespace change c as well.
+func (c *manager) Namespace(prefix string) Manager {
+	parent := c
+	if c.parent != nil {
+		parent = c.parent
+	}
+
+	return &manager{
+		managers:          c.managers,
+		parent:            parent,
+		keyValidator:      c.keyValidator,
+		keyTransformer:    c.keyTransformer,
+		globalPrefix:      c.withGlobalPrefix(prefix),
+		maxTagSize:        c.maxTagSize,
+		singleFlight:      c.singleFlight,
+		locker:            c.locker,
+		checkInterval:     c.checkInterval,
+		logger:            c.logger,
+		autoRemoveCorrupt: c.autoRemoveCorrupt,
+	}
+}
+
+// Namespace returns a namespace of the manager of the chain. Its operations use the current cache manager,
+// not the chain.
+func (c *chained) Namespace(prefix string) Manager {
+	return c.m.Namespace(prefix)
+}
@@ -0,0 +1,119 @@
+package cachemar
+
+import "time"
+
+// Option configures optional behaviour of the manager.
+type Option func(*manager)
+
+// WithKeyValidator sets a validator that every key is checked against before it is forwarded to a cache manager.
+func WithKeyValidator(v KeyValidator) Option {
+	return func(m *manager) {
+		m.keyValidator = v
+	}
+}
+
+// WithKeyTransformer sets a transformer that normalizes every key before it is forwarded to a cache manager.
+func WithKeyTransformer(kt KeyTransformer) Option {
+	return func(m *manager) {
+		m.keyTransformer = kt
+	}
+}
+
+// WithDistributedSingleflight coordinates GetOrCreate across manager instances using the given Locker.
+func WithDistributedSingleflight(locker Locker) Option {
+	return func(m *manager) {
+		m.locker = locker
+	}
+}
+
+// WithSingleFlight controls whether concurrent GetOrSet and GetOrCreate calls for the same key share
+// one loader call. It is enabled by default; disabling it lets every caller that misses run the loader.
+func WithSingleFlight(enabled bool) Option {
+	return func(m *manager) {
+		m.singleFlight = enabled
+	}
+}
+
+// WithLogger sets the logger the manager reports its diagnostics to. By default they are discarded.
+func WithLogger(l Logger) Option {
+	return func(m *manager) {
+		if l != nil {
+			m.logger = l
+		}
+	}
+}
+
+// WithGlobalKeyPrefix prepends "prefix:" to every key and tag before it is forwarded to a cache manager,
+// which then applies its own prefix on top.
+func WithGlobalKeyPrefix(prefix string) Option {
+	return func(m *manager) {
+		m.globalPrefix = prefix
+	}
+}
+
+// WithTagCircuitBreaker rejects RemoveByTag, RemoveByTags and GetKeysByTag with ErrTagTooLarge
+// for tags with more than maxTagSize keys. Use ForceRemoveByTag to remove such tags in batches.
+func WithTagCircuitBreaker(maxTagSize int) Option {
+	return func(m *manager) {
+		m.maxTagSize = maxTagSize
+	}
+}
+
+// WithCheckInterval sets how often background checks, such as WatchKeyVersion, poll. Defaults to DefaultCheckInterval.
+func WithCheckInterval(interval time.Duration) Option {
+	return func(m *manager) {
+		if interval > 0 {
+			m.checkInterval = interval
+		}
+	}
+}
+
+// WithAutoRemoveCorruptEntries makes Get remove entries whose value can't be decoded, e.g. after the
+// serialization format changed, and report them as ErrNotFound. Removed entries are counted in Stats.
+// Only entries that fail to decompress or to parse are removed; a value that doesn't fit the type Get
+// decodes into is kept and its error returned, since the caller may have passed the wrong target.
+func WithAutoRemoveCorruptEntries() Option {
+	return func(m *manager) {
+		m.autoRemoveCorrupt = true
+	}
+}
+
+// WithCircuitBreaker routes operations to the fallback cache manager while the current one is unavailable.
+// The current cache manager is pinged every check interval, and its circuit opens when the ping fails.
+// After the check interval, the circuit becomes half-open and a single operation probes the current cache
+// manager: the circuit closes if it succeeds, or opens for another interval if it fails. Only connection
+// errors and timeouts count as failures. Chains are not affected.
+func WithCircuitBreaker(fallback string) Option {
+	return func(m *manager) {
+		m.circuitFallback = fallback
+	}
+}
+
+// WithCircuitBreakerThreshold also opens the circuit of WithCircuitBreaker when failureCount operations on
+// the current cache manager fail within window, even if it still answers pings, e.g. when it is overloaded.
+func WithCircuitBreakerThreshold(failureCount int, window time.Duration) Option {
+	return func(m *manager) {
+		if failureCount > 0 && window > 0 {
+			m.failures = newFailureRing(failureCount, window)
+		}
+	}
+}
+
+// WithCircuitBreakerCallbacks sets the functions called with the name of the current cache manager when
+// its circuit opens, closes or becomes half-open. Any of them may be nil. They are called synchronously,
+// sometimes from the goroutine of an operation, so they must not block.
+func WithCircuitBreakerCallbacks(onOpen, onClose, onHalfOpen func(primaryCacher string)) Option {
+	return func(m *manager) {
+		m.onCircuitOpen = onOpen
+		m.onCircuitClose = onClose
+		m.onCircuitHalfOpen = onHalfOpen
+	}
+}
+
+// WithReadOnly starts the manager in read-only mode, in which all writes are rejected with ErrReadOnly.
+// The mode can be toggled at runtime with Manager.SetReadOnly.
+func WithReadOnly() Option {
+	return func(m *manager) {
+		m.readOnly.Store(true)
+	}
+}
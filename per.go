@@ -0,0 +1,121 @@
+package cachemar
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// PERLoader loads a value for GetPER and returns it with the ttl and tags to store it with.
+type PERLoader func(ctx context.Context) (interface{}, time.Duration, []string, error)
+
+// GetPER retrieves the value stored under key. On a miss, and with a probability growing as the value
+// approaches its expiry, the value is loaded synchronously and stored, so callers never see a miss
+// caused by expiry. A value is recomputed early when -delta * beta * log(rand()) exceeds its remaining
+// time-to-live, where delta is how long the last load of the key took in this manager. Higher beta
+// means earlier recomputation. Early recomputation requires a current cache manager implementing
+// MetadataGetter; otherwise values are only loaded on a miss. In read-only mode, found values are
+// returned without recomputation, and misses return ErrReadOnly.
+func (c *manager) GetPER(ctx context.Context, key string, value interface{}, beta float64, loader PERLoader) (err error) {
+	key, err = c.prepareKey(key)
+	if err != nil {
+		return err
+	}
+
+	cacher, done := c.active()
+	defer done(&err)
+
+	return c.getPER(
+		ctx, cacher, key, value, beta, func(ctx context.Context) (interface{}, time.Duration, []string, error) {
+			loaded, ttl, tags, err := loader(ctx)
+			return loaded, ttl, c.prepareTags(tags), err
+		},
+	)
+}
+
+func (c *manager) getPER(ctx context.Context, cacher Cacher, key string, value interface{}, beta float64, loader PERLoader) error {
+	if beta <= 0 {
+		beta = DefaultPERBeta
+	}
+
+	// In read-only mode, a value due for recomputation is still returned, as it can't be replaced.
+	found, fresh := c.perLookup(ctx, cacher, key, value, beta)
+	if fresh || (found && c.IsReadOnly()) {
+		return nil
+	}
+	if c.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	start := time.Now()
+	loaded, ttl, tags, err := loader(ctx)
+	if err != nil {
+		return err
+	}
+	c.perDeltas.store(key, time.Since(start))
+
+	if err := cacher.Set(ctx, key, loaded, ttl, tags); err != nil {
+		return err
+	}
+
+	return cacher.Get(ctx, key, value)
+}
+
+// perLookup reports whether the value was found, and whether it does not need to be recomputed early.
+func (c *manager) perLookup(ctx context.Context, cacher Cacher, key string, value interface{}, beta float64) (found, fresh bool) {
+	getter, ok := As[MetadataGetter](cacher)
+	if !ok {
+		found = cacher.Get(ctx, key, value) == nil
+		return found, found
+	}
+
+	metadata, err := getter.GetWithMetadata(ctx, key, value)
+	if err != nil {
+		return false, false
+	}
+
+	delta, ok := c.perDeltas.load(key)
+	if !ok || metadata.TTLRemaining <= 0 {
+		return true, true
+	}
+
+	gap := -float64(delta) * beta * math.Log(rand.Float64())
+	return true, gap < float64(metadata.TTLRemaining)
+}
+
+// maxPERDeltas bounds the number of keys whose load duration GetPER remembers.
+const maxPERDeltas = 10000
+
+// perDeltas holds the duration of the last GetPER load of each key. Once it is full, an arbitrary key is
+// forgotten for every new one, which only stops that key from being recomputed early until its next load.
+type perDeltas struct {
+	mu     sync.Mutex
+	deltas map[string]time.Duration
+}
+
+func (d *perDeltas) store(key string, delta time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.deltas == nil {
+		d.deltas = make(map[string]time.Duration)
+	}
+
+	if _, exists := d.deltas[key]; !exists && len(d.deltas) >= maxPERDeltas {
+		for k := range d.deltas {
+			delete(d.deltas, k)
+			break
+		}
+	}
+	d.deltas[key] = delta
+}
+
+func (d *perDeltas) load(key string) (time.Duration, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delta, ok := d.deltas[key]
+	return delta, ok
+}
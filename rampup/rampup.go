@@ -0,0 +1,249 @@
+// Package rampup provides a Cacher that gradually shifts reads from one cache to another during a migration.
+package rampup
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+type rampUp struct {
+	old        cachemar.Cacher
+	new        cachemar.Cacher
+	percentage func() int
+}
+
+// New creates a Cacher that writes to both old and new, and serves each read from new with the probability
+// returned by percentage (0–100), or from old otherwise. percentage is called on every read, so the split
+// can be adjusted at runtime, e.g. from a feature flag.
+func New(old, new cachemar.Cacher, percentage func() int) cachemar.Cacher {
+	return &rampUp{
+		old:        old,
+		new:        new,
+		percentage: percentage,
+	}
+}
+
+// pick returns the cache serving the current read.
+func (r *rampUp) pick() cachemar.Cacher {
+	if rand.Intn(100) < r.percentage() {
+		return r.new
+	}
+
+	return r.old
+}
+
+// write runs op on both caches so they stay in sync.
+func (r *rampUp) write(ctx context.Context, op func(ctx context.Context, c cachemar.Cacher) error) error {
+	errors := make([]error, 0)
+
+	if err := op(ctx, r.old); err != nil {
+		errors = append(errors, fmt.Errorf("old: %w", err))
+	}
+	if err := op(ctx, r.new); err != nil {
+		errors = append(errors, fmt.Errorf("new: %w", err))
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("errors: %v", errors)
+	}
+	return nil
+}
+
+func (r *rampUp) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
+	return r.write(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		return c.Set(ctx, key, value, ttl, tags)
+	})
+}
+
+func (r *rampUp) MSet(ctx context.Context, items []cachemar.CacheItem) error {
+	return r.write(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		return c.MSet(ctx, items)
+	})
+}
+
+// SetOpts stores the value like Set. Conditional options are checked on old only, and the value is then
+// set in new unconditionally.
+func (r *rampUp) SetOpts(ctx context.Context, key string, value interface{}, opts cachemar.SetOptions) error {
+	if !opts.Conditional() {
+		return r.write(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+			return c.SetOpts(ctx, key, value, opts)
+		})
+	}
+
+	if err := r.old.SetOpts(ctx, key, value, opts); err != nil {
+		return err
+	}
+
+	return r.new.Set(ctx, key, value, opts.TTL, opts.Tags)
+}
+
+// SetIfAbsent stores the value if the key is absent from old, which stays authoritative during the
+// migration, and then sets it in new.
+func (r *rampUp) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	written, err := r.old.SetIfAbsent(ctx, key, value, ttl, tags)
+	if err != nil || !written {
+		return false, err
+	}
+
+	return true, r.new.Set(ctx, key, value, ttl, tags)
+}
+
+// SetIfPresent stores the value if the key is present in old, which stays authoritative during the
+// migration, and then sets it in new.
+func (r *rampUp) SetIfPresent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	written, err := r.old.SetIfPresent(ctx, key, value, ttl, tags)
+	if err != nil || !written {
+		return false, err
+	}
+
+	return true, r.new.Set(ctx, key, value, ttl, tags)
+}
+
+func (r *rampUp) Get(ctx context.Context, key string, value interface{}) error {
+	return r.pick().Get(ctx, key, value)
+}
+
+func (r *rampUp) GetWithTTL(ctx context.Context, key string, value interface{}) (time.Duration, error) {
+	return r.pick().GetWithTTL(ctx, key, value)
+}
+
+func (r *rampUp) MGet(ctx context.Context, keys []string, values []interface{}) error {
+	return r.pick().MGet(ctx, keys, values)
+}
+
+// GetAndDelete takes the value from the cache serving the read, and removes it from the other one.
+func (r *rampUp) GetAndDelete(ctx context.Context, key string, value interface{}) error {
+	source, other := r.old, r.new
+	if r.pick() == r.new {
+		source, other = r.new, r.old
+	}
+
+	if err := source.GetAndDelete(ctx, key, value); err != nil {
+		return err
+	}
+	if err := other.Remove(ctx, key); err != nil && !cachemar.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Touch resets the expiry in the old cache, and in the new one if it already has the key.
+func (r *rampUp) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	if err := r.old.Touch(ctx, key, ttl); err != nil {
+		return err
+	}
+	if err := r.new.Touch(ctx, key, ttl); err != nil && !cachemar.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (r *rampUp) Remove(ctx context.Context, key string) error {
+	return r.write(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		return c.Remove(ctx, key)
+	})
+}
+
+func (r *rampUp) RemoveByTag(ctx context.Context, tag string) error {
+	return r.write(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		return c.RemoveByTag(ctx, tag)
+	})
+}
+
+func (r *rampUp) RemoveByTags(ctx context.Context, tags []string) error {
+	return r.write(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		return c.RemoveByTags(ctx, tags)
+	})
+}
+
+func (r *rampUp) Flush(ctx context.Context) error {
+	return r.write(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		return c.Flush(ctx)
+	})
+}
+
+func (r *rampUp) Exists(ctx context.Context, key string) (bool, error) {
+	return r.pick().Exists(ctx, key)
+}
+
+func (r *rampUp) Increment(ctx context.Context, key string) error {
+	return r.write(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		return c.Increment(ctx, key)
+	})
+}
+
+func (r *rampUp) Decrement(ctx context.Context, key string) error {
+	return r.write(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		return c.Decrement(ctx, key)
+	})
+}
+
+// IncrBy applies the delta to both caches and returns the value of the one a read would be served from.
+func (r *rampUp) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	var oldValue, newValue int64
+
+	err := r.write(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		value, err := c.IncrBy(ctx, key, delta)
+		if c == r.new {
+			newValue = value
+		} else {
+			oldValue = value
+		}
+		return err
+	})
+
+	if r.pick() == r.new {
+		return newValue, err
+	}
+	return oldValue, err
+}
+
+func (r *rampUp) DecrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	return r.IncrBy(ctx, key, -delta)
+}
+
+// IncrementFloat applies the delta to both caches and returns the value of the one a read would be served from.
+func (r *rampUp) IncrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	var oldValue, newValue float64
+
+	err := r.write(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		value, err := c.IncrementFloat(ctx, key, delta)
+		if c == r.new {
+			newValue = value
+		} else {
+			oldValue = value
+		}
+		return err
+	})
+
+	if r.pick() == r.new {
+		return newValue, err
+	}
+	return oldValue, err
+}
+
+func (r *rampUp) DecrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	return r.IncrementFloat(ctx, key, -delta)
+}
+
+func (r *rampUp) GetKeysByTag(ctx context.Context, tag string) ([]string, error) {
+	return r.pick().GetKeysByTag(ctx, tag)
+}
+
+func (r *rampUp) Ping() error {
+	return r.write(context.Background(), func(_ context.Context, c cachemar.Cacher) error {
+		return c.Ping()
+	})
+}
+
+func (r *rampUp) Close() error {
+	return r.write(context.Background(), func(_ context.Context, c cachemar.Cacher) error {
+		return c.Close()
+	})
+}
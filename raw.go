@@ -0,0 +1,71 @@
+package cachemar
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+)
+
+// rawPrefix marks values stored by RawCacher.SetRaw. It can't start a JSON document or a gzip stream.
+var rawPrefix = []byte{0xFF, 0x00}
+
+// ErrRawValue is returned by Get when the value was stored raw and can't be decoded into the target.
+var ErrRawValue = errors.New("value is stored raw, use GetRaw or a *[]byte")
+
+// RawGetter is implemented by cache managers that can return stored values without decoding them.
+type RawGetter interface {
+	// GetRaw retrieves the bytes stored by SetRaw as given, and other values in their serialized form.
+	// ErrNotFound is returned for missing keys.
+	GetRaw(ctx context.Context, key string) ([]byte, error)
+}
+
+// RawCacher is implemented by cache managers that can store already serialized bytes without encoding them.
+type RawCacher interface {
+	// SetRaw stores the bytes as-is with the specified ttl and tags.
+	SetRaw(ctx context.Context, key string, value []byte, ttl time.Duration, tags []string) error
+
+	RawGetter
+}
+
+// EncodedCacher is implemented by cache managers that can copy values in their serialized form, e.g. to warm
+// a chain without knowing the types of the values. Values may only be copied between cache managers of the
+// same Format.
+type EncodedCacher interface {
+	// Format identifies how the values are serialized, see CodecFormat.
+	Format() string
+
+	// GetEncoded retrieves the value as serialized by the codec, uncompressed. ErrNotFound is returned for
+	// missing keys and ErrRawValue for the bytes stored by SetRaw, which are not serialized.
+	GetEncoded(ctx context.Context, key string) ([]byte, error)
+
+	// SetEncoded stores a value serialized in the same Format as if its decoded value was given to Set.
+	SetEncoded(ctx context.Context, key string, data []byte, ttl time.Duration, tags []string) error
+}
+
+// EncodeRaw prefixes the bytes with the raw value marker.
+func EncodeRaw(value []byte) []byte {
+	data := make([]byte, 0, len(rawPrefix)+len(value))
+	data = append(data, rawPrefix...)
+	return append(data, value...)
+}
+
+// DecodeRaw strips the raw value marker from the data. It reports false if the data is not a raw value.
+func DecodeRaw(data []byte) ([]byte, bool) {
+	if !bytes.HasPrefix(data, rawPrefix) {
+		return nil, false
+	}
+
+	return data[len(rawPrefix):], true
+}
+
+// AssignRaw stores the raw bytes in value, which must be a *[]byte.
+func AssignRaw(data []byte, value interface{}) error {
+	target, ok := value.(*[]byte)
+	if !ok {
+		return ErrRawValue
+	}
+
+	*target = append([]byte(nil), data...)
+	return nil
+}
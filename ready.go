@@ -0,0 +1,35 @@
+package cachemar
+
+import (
+	"context"
+	"time"
+)
+
+// WaitReady pings the cacher every interval until it answers, the timeout elapses or ctx is done.
+// It returns the last Ping error if the cacher never became ready in time. Failed attempts are
+// reported to logger, which may be nil.
+func WaitReady(ctx context.Context, cacher Cacher, timeout, interval time.Duration, logger Logger) error {
+	if logger == nil {
+		logger = noopLogger{}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for attempt := 1; ; attempt++ {
+		err := cacher.Ping()
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-ticker.C:
+			logger.Debug("cachemar: ping failed, retrying", "operation", "Ping", "attempt", attempt, "interval", interval, "error", err)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Package recover provides a Cacher that turns panics of the wrapped cache manager into errors.
+package recover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+// ErrPanic is returned by DefaultRecoveryHandler when the wrapped cache manager panics.
+var ErrPanic = errors.New("cache manager panicked")
+
+// RecoveryHandler converts a recovered panic of the operation on the key into the error returned by the operation.
+type RecoveryHandler func(key string, op string, recovered interface{}) error
+
+// DefaultRecoveryHandler wraps the recovered value and the stack trace in an ErrPanic error.
+func DefaultRecoveryHandler(key string, op string, recovered interface{}) error {
+	return fmt.Errorf("%w: %s %q: %v\n%s", ErrPanic, op, key, recovered, debug.Stack())
+}
+
+type recovering struct {
+	wrapped cachemar.Cacher
+	handler RecoveryHandler
+}
+
+// New wraps the cache manager so that a panic in any of its methods is recovered and passed to handler.
+// The error returned by handler becomes the result of the method. A nil handler means DefaultRecoveryHandler.
+func New(wrapped cachemar.Cacher, handler func(key string, op string, recovered interface{}) error) cachemar.Cacher {
+	if handler == nil {
+		handler = DefaultRecoveryHandler
+	}
+
+	return &recovering{
+		wrapped: wrapped,
+		handler: handler,
+	}
+}
+
+// guard must be deferred directly by every method so that recover can stop the panic.
+func (r *recovering) guard(key, op string, err *error) {
+	if recovered := recover(); recovered != nil {
+		*err = r.handler(key, op, recovered)
+	}
+}
+
+func (r *recovering) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (err error) {
+	defer r.guard(key, "Set", &err)
+	return r.wrapped.Set(ctx, key, value, ttl, tags)
+}
+
+func (r *recovering) MSet(ctx context.Context, items []cachemar.CacheItem) (err error) {
+	defer r.guard("", "MSet", &err)
+	return r.wrapped.MSet(ctx, items)
+}
+
+func (r *recovering) SetOpts(ctx context.Context, key string, value interface{}, opts cachemar.SetOptions) (err error) {
+	defer r.guard(key, "SetOpts", &err)
+	return r.wrapped.SetOpts(ctx, key, value, opts)
+}
+
+func (r *recovering) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (written bool, err error) {
+	defer r.guard(key, "SetIfAbsent", &err)
+	return r.wrapped.SetIfAbsent(ctx, key, value, ttl, tags)
+}
+
+func (r *recovering) SetIfPresent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (written bool, err error) {
+	defer r.guard(key, "SetIfPresent", &err)
+	return r.wrapped.SetIfPresent(ctx, key, value, ttl, tags)
+}
+
+func (r *recovering) Get(ctx context.Context, key string, value interface{}) (err error) {
+	defer r.guard(key, "Get", &err)
+	return r.wrapped.Get(ctx, key, value)
+}
+
+func (r *recovering) GetWithTTL(ctx context.Context, key string, value interface{}) (ttl time.Duration, err error) {
+	defer r.guard(key, "GetWithTTL", &err)
+	return r.wrapped.GetWithTTL(ctx, key, value)
+}
+
+func (r *recovering) MGet(ctx context.Context, keys []string, values []interface{}) (err error) {
+	defer r.guard(strings.Join(keys, ","), "MGet", &err)
+	return r.wrapped.MGet(ctx, keys, values)
+}
+
+func (r *recovering) GetAndDelete(ctx context.Context, key string, value interface{}) (err error) {
+	defer r.guard(key, "GetAndDelete", &err)
+	return r.wrapped.GetAndDelete(ctx, key, value)
+}
+
+func (r *recovering) Touch(ctx context.Context, key string, ttl time.Duration) (err error) {
+	defer r.guard(key, "Touch", &err)
+	return r.wrapped.Touch(ctx, key, ttl)
+}
+
+func (r *recovering) Remove(ctx context.Context, key string) (err error) {
+	defer r.guard(key, "Remove", &err)
+	return r.wrapped.Remove(ctx, key)
+}
+
+func (r *recovering) RemoveByTag(ctx context.Context, tag string) (err error) {
+	defer r.guard(tag, "RemoveByTag", &err)
+	return r.wrapped.RemoveByTag(ctx, tag)
+}
+
+func (r *recovering) RemoveByTags(ctx context.Context, tags []string) (err error) {
+	defer r.guard(strings.Join(tags, ","), "RemoveByTags", &err)
+	return r.wrapped.RemoveByTags(ctx, tags)
+}
+
+func (r *recovering) Flush(ctx context.Context) (err error) {
+	defer r.guard("", "Flush", &err)
+	return r.wrapped.Flush(ctx)
+}
+
+func (r *recovering) Exists(ctx context.Context, key string) (exists bool, err error) {
+	defer r.guard(key, "Exists", &err)
+	return r.wrapped.Exists(ctx, key)
+}
+
+func (r *recovering) Increment(ctx context.Context, key string) (err error) {
+	defer r.guard(key, "Increment", &err)
+	return r.wrapped.Increment(ctx, key)
+}
+
+func (r *recovering) Decrement(ctx context.Context, key string) (err error) {
+	defer r.guard(key, "Decrement", &err)
+	return r.wrapped.Decrement(ctx, key)
+}
+
+func (r *recovering) IncrBy(ctx context.Context, key string, delta int64) (value int64, err error) {
+	defer r.guard(key, "IncrBy", &err)
+	return r.wrapped.IncrBy(ctx, key, delta)
+}
+
+func (r *recovering) DecrBy(ctx context.Context, key string, delta int64) (value int64, err error) {
+	defer r.guard(key, "DecrBy", &err)
+	return r.wrapped.DecrBy(ctx, key, delta)
+}
+
+func (r *recovering) IncrementFloat(ctx context.Context, key string, delta float64) (value float64, err error) {
+	defer r.guard(key, "IncrementFloat", &err)
+	return r.wrapped.IncrementFloat(ctx, key, delta)
+}
+
+func (r *recovering) DecrementFloat(ctx context.Context, key string, delta float64) (value float64, err error) {
+	defer r.guard(key, "DecrementFloat", &err)
+	return r.wrapped.DecrementFloat(ctx, key, delta)
+}
+
+func (r *recovering) GetKeysByTag(ctx context.Context, tag string) (keys []string, err error) {
+	defer r.guard(tag, "GetKeysByTag", &err)
+	return r.wrapped.GetKeysByTag(ctx, tag)
+}
+
+func (r *recovering) Ping() (err error) {
+	defer r.guard("", "Ping", &err)
+	return r.wrapped.Ping()
+}
+
+func (r *recovering) Close() (err error) {
+	defer r.guard("", "Close", &err)
+	return r.wrapped.Close()
+}
@@ -0,0 +1,421 @@
+// Package replication provides a Cacher that propagates writes from a primary cache to a set of replicas.
+package replication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+const (
+	canaryKey           = "cachemar:replication:canary"
+	lagPollInterval     = time.Millisecond
+	defaultReplicaQueue = 1024
+)
+
+var (
+	// ErrQueueFull is reported to OnReplicationError for an asynchronous write dropped because the queue
+	// of the replica is full.
+	ErrQueueFull = errors.New("replication: replica queue is full")
+	// ErrClosed is returned by writes after Close.
+	ErrClosed = errors.New("replication: closed")
+)
+
+// ReplicationOptions configures how writes are propagated to the replicas.
+type ReplicationOptions struct {
+	// ReplicationTimeout bounds every single replica operation. Zero means no timeout.
+	ReplicationTimeout time.Duration
+	// OnReplicationError is called when an operation fails on a replica.
+	OnReplicationError func(replica int, err error)
+	// SyncWrite makes writes wait for all replicas before returning.
+	SyncWrite bool
+	// Workers is ignored.
+	//
+	// Deprecated: asynchronous writes are replicated in order by one goroutine per replica.
+	Workers int
+	// QueueSize is the number of asynchronous writes waiting for each replica. Writes are dropped, and
+	// reported to OnReplicationError with ErrQueueFull, while the queue is full. Defaults to 1024.
+	QueueSize int
+}
+
+// Lagger is implemented by replicated cachers to report replica lag.
+type Lagger interface {
+	Lag(ctx context.Context) (map[int]time.Duration, error)
+}
+
+type job func(ctx context.Context, c cachemar.Cacher) error
+
+type replicated struct {
+	primary  cachemar.Cacher
+	replicas []cachemar.Cacher
+	opts     ReplicationOptions
+	queues   []chan job // One queue per replica, so its writes are applied in order.
+	mu       sync.RWMutex
+	closed   bool // Set by Close under mu, which closes the queues.
+	wg       sync.WaitGroup
+}
+
+// New creates a Cacher that writes to primary synchronously and to replicas asynchronously.
+// Reads are served by the primary only.
+func New(primary cachemar.Cacher, replicas []cachemar.Cacher, opts ReplicationOptions) cachemar.Cacher {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = defaultReplicaQueue
+	}
+
+	r := &replicated{
+		primary:  primary,
+		replicas: replicas,
+		opts:     opts,
+		queues:   make([]chan job, len(replicas)),
+	}
+
+	for i := range replicas {
+		r.queues[i] = make(chan job, opts.QueueSize)
+		r.wg.Add(1)
+		go r.worker(i)
+	}
+
+	return r
+}
+
+func (r *replicated) worker(replica int) {
+	defer r.wg.Done()
+
+	for op := range r.queues[replica] {
+		ctx, cancel := r.replicaContext(context.Background())
+		r.handleError(replica, op(ctx, r.replicas[replica]))
+		cancel()
+	}
+}
+
+func (r *replicated) replicaContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.opts.ReplicationTimeout > 0 {
+		return context.WithTimeout(ctx, r.opts.ReplicationTimeout)
+	}
+
+	return context.WithCancel(ctx)
+}
+
+func (r *replicated) handleError(replica int, err error) {
+	if err != nil && r.opts.OnReplicationError != nil {
+		r.opts.OnReplicationError(replica, err)
+	}
+}
+
+// write runs op on the primary and then replicates it.
+func (r *replicated) write(ctx context.Context, op func(ctx context.Context, c cachemar.Cacher) error) error {
+	if err := op(ctx, r.primary); err != nil {
+		return err
+	}
+
+	return r.replicate(ctx, op)
+}
+
+func (r *replicated) replicate(ctx context.Context, op func(ctx context.Context, c cachemar.Cacher) error) error {
+	if !r.opts.SyncWrite {
+		return r.enqueue(op)
+	}
+
+	var (
+		mu     sync.Mutex
+		errors []error
+		wg     sync.WaitGroup
+	)
+
+	for i, replica := range r.replicas {
+		wg.Add(1)
+		go func(i int, replica cachemar.Cacher) {
+			defer wg.Done()
+
+			replicaCtx, cancel := r.replicaContext(ctx)
+			defer cancel()
+
+			if err := op(replicaCtx, replica); err != nil {
+				r.handleError(i, err)
+				mu.Lock()
+				errors = append(errors, fmt.Errorf("replica %d: %w", i, err))
+				mu.Unlock()
+			}
+		}(i, replica)
+	}
+	wg.Wait()
+
+	if len(errors) > 0 {
+		return fmt.Errorf("errors occurred while replicating: %v", errors)
+	}
+	return nil
+}
+
+// enqueue queues op for every replica without blocking. It is dropped for the replicas whose queue is full.
+func (r *replicated) enqueue(op job) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		return ErrClosed
+	}
+
+	for i, queue := range r.queues {
+		select {
+		case queue <- op:
+		default:
+			r.handleError(i, ErrQueueFull)
+		}
+	}
+
+	return nil
+}
+
+func (r *replicated) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
+	return r.write(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		return c.Set(ctx, key, value, ttl, tags)
+	})
+}
+
+func (r *replicated) MSet(ctx context.Context, items []cachemar.CacheItem) error {
+	return r.write(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		return c.MSet(ctx, items)
+	})
+}
+
+// SetOpts stores the value like Set. Conditional options are checked on the primary only, and the value
+// is then replicated unconditionally.
+func (r *replicated) SetOpts(ctx context.Context, key string, value interface{}, opts cachemar.SetOptions) error {
+	if err := r.primary.SetOpts(ctx, key, value, opts); err != nil {
+		return err
+	}
+
+	return r.replicate(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		return c.Set(ctx, key, value, opts.TTL, opts.Tags)
+	})
+}
+
+// SetIfAbsent stores the value if the key is absent from the primary, and then replicates it.
+func (r *replicated) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	written, err := r.primary.SetIfAbsent(ctx, key, value, ttl, tags)
+	if err != nil || !written {
+		return false, err
+	}
+
+	return true, r.replicate(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		return c.Set(ctx, key, value, ttl, tags)
+	})
+}
+
+// SetIfPresent stores the value if the key is present in the primary, and then replicates it.
+func (r *replicated) SetIfPresent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	written, err := r.primary.SetIfPresent(ctx, key, value, ttl, tags)
+	if err != nil || !written {
+		return false, err
+	}
+
+	return true, r.replicate(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		return c.Set(ctx, key, value, ttl, tags)
+	})
+}
+
+func (r *replicated) Get(ctx context.Context, key string, value interface{}) error {
+	return r.primary.Get(ctx, key, value)
+}
+
+func (r *replicated) GetWithTTL(ctx context.Context, key string, value interface{}) (time.Duration, error) {
+	return r.primary.GetWithTTL(ctx, key, value)
+}
+
+func (r *replicated) MGet(ctx context.Context, keys []string, values []interface{}) error {
+	return r.primary.MGet(ctx, keys, values)
+}
+
+// GetAndDelete takes the value from the primary, and then removes it from the replicas.
+func (r *replicated) GetAndDelete(ctx context.Context, key string, value interface{}) error {
+	if err := r.primary.GetAndDelete(ctx, key, value); err != nil {
+		return err
+	}
+
+	return r.replicate(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		return c.Remove(ctx, key)
+	})
+}
+
+// Touch resets the expiry on the primary, and then on the replicas that have the key.
+func (r *replicated) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	if err := r.primary.Touch(ctx, key, ttl); err != nil {
+		return err
+	}
+
+	return r.replicate(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		if err := c.Touch(ctx, key, ttl); err != nil && !cachemar.IsNotFound(err) {
+			return err
+		}
+		return nil
+	})
+}
+
+func (r *replicated) Remove(ctx context.Context, key string) error {
+	return r.write(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		return c.Remove(ctx, key)
+	})
+}
+
+func (r *replicated) RemoveByTag(ctx context.Context, tag string) error {
+	return r.write(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		return c.RemoveByTag(ctx, tag)
+	})
+}
+
+func (r *replicated) RemoveByTags(ctx context.Context, tags []string) error {
+	return r.write(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		return c.RemoveByTags(ctx, tags)
+	})
+}
+
+func (r *replicated) Flush(ctx context.Context) error {
+	return r.write(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		return c.Flush(ctx)
+	})
+}
+
+func (r *replicated) Exists(ctx context.Context, key string) (bool, error) {
+	return r.primary.Exists(ctx, key)
+}
+
+func (r *replicated) Increment(ctx context.Context, key string) error {
+	return r.write(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		return c.Increment(ctx, key)
+	})
+}
+
+func (r *replicated) Decrement(ctx context.Context, key string) error {
+	return r.write(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		return c.Decrement(ctx, key)
+	})
+}
+
+func (r *replicated) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	value, err := r.primary.IncrBy(ctx, key, delta)
+	if err != nil {
+		return 0, err
+	}
+
+	return value, r.replicate(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		_, err := c.IncrBy(ctx, key, delta)
+		return err
+	})
+}
+
+func (r *replicated) DecrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	return r.IncrBy(ctx, key, -delta)
+}
+
+func (r *replicated) IncrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	value, err := r.primary.IncrementFloat(ctx, key, delta)
+	if err != nil {
+		return 0, err
+	}
+
+	return value, r.replicate(ctx, func(ctx context.Context, c cachemar.Cacher) error {
+		_, err := c.IncrementFloat(ctx, key, delta)
+		return err
+	})
+}
+
+func (r *replicated) DecrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	return r.IncrementFloat(ctx, key, -delta)
+}
+
+func (r *replicated) GetKeysByTag(ctx context.Context, tag string) ([]string, error) {
+	return r.primary.GetKeysByTag(ctx, tag)
+}
+
+func (r *replicated) Ping() error {
+	errors := make([]error, 0)
+
+	if err := r.primary.Ping(); err != nil {
+		errors = append(errors, err)
+	}
+	for i, replica := range r.replicas {
+		if err := replica.Ping(); err != nil {
+			errors = append(errors, fmt.Errorf("replica %d: %w", i, err))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("errors: %v", errors)
+	}
+	return nil
+}
+
+// Close waits for pending replication and closes the primary and all replicas.
+func (r *replicated) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return ErrClosed
+	}
+	r.closed = true
+	for _, queue := range r.queues {
+		close(queue)
+	}
+	r.mu.Unlock()
+	r.wg.Wait()
+
+	errors := make([]error, 0)
+
+	if err := r.primary.Close(); err != nil {
+		errors = append(errors, err)
+	}
+	for i, replica := range r.replicas {
+		if err := replica.Close(); err != nil {
+			errors = append(errors, fmt.Errorf("replica %d: %w", i, err))
+		}
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("errors: %v", errors)
+	}
+	return nil
+}
+
+// Lag writes a canary timestamp through the replicated cache and measures how long it takes
+// until each replica holds it.
+func (r *replicated) Lag(ctx context.Context) (map[int]time.Duration, error) {
+	start := time.Now()
+	stamp := start.UnixNano()
+
+	if err := r.Set(ctx, canaryKey, stamp, time.Minute, nil); err != nil {
+		return nil, err
+	}
+
+	lags := make(map[int]time.Duration, len(r.replicas))
+	for i, replica := range r.replicas {
+		lag, err := waitForCanary(ctx, replica, stamp, start)
+		if err != nil {
+			return lags, fmt.Errorf("replica %d: %w", i, err)
+		}
+		lags[i] = lag
+	}
+
+	return lags, nil
+}
+
+func waitForCanary(ctx context.Context, replica cachemar.Cacher, stamp int64, start time.Time) (time.Duration, error) {
+	ticker := time.NewTicker(lagPollInterval)
+	defer ticker.Stop()
+
+	for {
+		var got int64
+		if err := replica.Get(ctx, canaryKey, &got); err == nil && got >= stamp {
+			return time.Since(start), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package cachemar
+
+import (
+	"errors"
+	"time"
+)
+
+// SetOptions describes how SetOpts stores a value.
+type SetOptions struct {
+	TTL  time.Duration
+	Tags []string
+	// IfAbsent stores the value only if the key does not exist yet, like SetIfAbsent.
+	IfAbsent bool
+	// IfPresent stores the value only if the key already exists, like SetIfPresent.
+	IfPresent bool
+}
+
+// Validate checks the options.
+func (o SetOptions) Validate() error {
+	if o.IfAbsent && o.IfPresent {
+		return errors.New("set options: IfAbsent and IfPresent are mutually exclusive")
+	}
+
+	return nil
+}
+
+// Conditional reports whether the value is stored only depending on the presence of the key.
+func (o SetOptions) Conditional() bool {
+	return o.IfAbsent || o.IfPresent
+}
+
+// SetOptionsBuilder builds SetOptions fluently.
+type SetOptionsBuilder struct {
+	options SetOptions
+}
+
+// NewSetOptions starts building SetOptions with the default cache time.
+func NewSetOptions() *SetOptionsBuilder {
+	return &SetOptionsBuilder{
+		options: SetOptions{
+			TTL: DefaultCacheTime,
+		},
+	}
+}
+
+func (b *SetOptionsBuilder) WithTTL(ttl time.Duration) *SetOptionsBuilder {
+	b.options.TTL = ttl
+	return b
+}
+
+func (b *SetOptionsBuilder) WithTags(tags ...string) *SetOptionsBuilder {
+	b.options.Tags = tags
+	return b
+}
+
+// WithNX stores the value only if the key does not exist yet.
+func (b *SetOptionsBuilder) WithNX() *SetOptionsBuilder {
+	b.options.IfAbsent = true
+	return b
+}
+
+// WithXX stores the value only if the key already exists.
+func (b *SetOptionsBuilder) WithXX() *SetOptionsBuilder {
+	b.options.IfPresent = true
+	return b
+}
+
+func (b *SetOptionsBuilder) Build() SetOptions {
+	return b.options
+}
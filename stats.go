@@ -0,0 +1,20 @@
+package cachemar
+
+// Stats holds counters of the manager's own activity, as opposed to the backend statistics of the cache managers.
+type Stats struct {
+	// CorruptEntriesRemoved counts the entries removed because their value couldn't be decoded.
+	// See WithAutoRemoveCorruptEntries.
+	CorruptEntriesRemoved uint64
+}
+
+// Stats returns counters of the manager's own activity.
+func (c *manager) Stats() Stats {
+	return Stats{
+		CorruptEntriesRemoved: c.corruptRemoved.Load(),
+	}
+}
+
+// Stats returns the counters of the manager the chain was created from.
+func (c *chained) Stats() Stats {
+	return c.m.Stats()
+}
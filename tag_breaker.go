@@ -0,0 +1,59 @@
+package cachemar
+
+import (
+	"context"
+	"fmt"
+)
+
+// TagCounter is implemented by cache managers that can count the keys associated with a tag.
+type TagCounter interface {
+	// CountByTag returns the number of keys associated with the tag.
+	CountByTag(ctx context.Context, tag string) (int64, error)
+}
+
+// BatchTagRemover is implemented by cache managers that can remove the keys of a tag in batches.
+type BatchTagRemover interface {
+	// ForceRemoveByTag removes all keys associated with the tag, batchSize keys at a time.
+	ForceRemoveByTag(ctx context.Context, tag string, batchSize int) error
+}
+
+// ForceRemoveByTag removes all keys associated with the tag bypassing the tag circuit breaker.
+// Cache managers implementing BatchTagRemover remove the keys in batches.
+func (c *manager) ForceRemoveByTag(ctx context.Context, tag string, batchSize int) error {
+	if c.IsReadOnly() {
+		return ErrReadOnly
+	}
+
+	return forceRemoveByTag(ctx, c.Current(), c.prepareTag(tag), batchSize)
+}
+
+func forceRemoveByTag(ctx context.Context, cacher Cacher, tag string, batchSize int) error {
+	if remover, ok := As[BatchTagRemover](cacher); ok {
+		return remover.ForceRemoveByTag(ctx, tag, batchSize)
+	}
+
+	return cacher.RemoveByTag(ctx, tag)
+}
+
+// checkTagSize rejects tags with more than maxTagSize keys when the tag circuit breaker is enabled.
+func (c *manager) checkTagSize(ctx context.Context, cacher Cacher, tag string) error {
+	if c.maxTagSize <= 0 {
+		return nil
+	}
+
+	counter, ok := As[TagCounter](cacher)
+	if !ok {
+		return nil
+	}
+
+	count, err := counter.CountByTag(ctx, tag)
+	if err != nil {
+		return err
+	}
+
+	if count > int64(c.maxTagSize) {
+		return fmt.Errorf("%w: tag %q has %d keys, limit is %d", ErrTagTooLarge, tag, count, c.maxTagSize)
+	}
+
+	return nil
+}
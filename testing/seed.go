@@ -0,0 +1,120 @@
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	gotesting "testing"
+	"time"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+// seedWorkers is the number of seeds stored concurrently.
+const seedWorkers = 10
+
+// SeedItem is a value to pre-populate a cache with.
+type SeedItem struct {
+	Key   string
+	Value interface{}
+	// TTL defaults to cachemar.DefaultCacheTime.
+	TTL  time.Duration
+	Tags []string
+}
+
+// Seed stores the seeds concurrently and removes them when the test finishes.
+// The test fails if any seed can't be stored.
+func Seed(ctx context.Context, c cachemar.Cacher, seeds []SeedItem, t gotesting.TB) error {
+	t.Helper()
+
+	errs := make(cachemar.MultiError, len(seeds))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < seedWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for index := range indexes {
+				seed := seeds[index]
+				ttl := seed.TTL
+				if ttl <= 0 {
+					ttl = cachemar.DefaultCacheTime
+				}
+				errs[index] = c.Set(ctx, seed.Key, seed.Value, ttl, seed.Tags)
+			}
+		}()
+	}
+
+	for i := range seeds {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	keys := make([]string, 0, len(seeds))
+	for _, seed := range seeds {
+		keys = append(keys, seed.Key)
+	}
+	t.Cleanup(func() { removeSeeds(c, keys) })
+
+	if err := errs.ErrorOrNil(); err != nil {
+		t.Errorf("failed to seed cache: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+func removeSeeds(c cachemar.Cacher, keys []string) {
+	ctx := context.Background()
+	for _, key := range keys {
+		_ = c.Remove(ctx, key)
+	}
+}
+
+// jsonSeed is the JSON form of a SeedItem, with the TTL as a duration string such as "5m".
+type jsonSeed struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+	TTL   string      `json:"ttl"`
+	Tags  []string    `json:"tags"`
+}
+
+// SeedFromJSON seeds the cache like Seed with the seeds read from a JSON file of the form
+// [{"key": "user:1", "value": {"name": "John"}, "ttl": "5m", "tags": ["users"]}].
+func SeedFromJSON(ctx context.Context, c cachemar.Cacher, jsonPath string, t gotesting.TB) error {
+	t.Helper()
+
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Errorf("failed to read seeds: %v", err)
+		return err
+	}
+
+	var parsed []jsonSeed
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		t.Errorf("failed to parse seeds: %v", err)
+		return err
+	}
+
+	seeds := make([]SeedItem, 0, len(parsed))
+	for _, seed := range parsed {
+		var ttl time.Duration
+		if seed.TTL != "" {
+			ttl, err = time.ParseDuration(seed.TTL)
+			if err != nil {
+				err = fmt.Errorf("invalid ttl of seed %q: %v", seed.Key, err)
+				t.Errorf("failed to parse seeds: %v", err)
+				return err
+			}
+		}
+
+		seeds = append(seeds, SeedItem{Key: seed.Key, Value: seed.Value, TTL: ttl, Tags: seed.Tags})
+	}
+
+	return Seed(ctx, c, seeds, t)
+}
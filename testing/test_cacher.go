@@ -0,0 +1,35 @@
+// Package testing provides helpers for testing code that uses cachemar.
+package testing
+
+import (
+	gotesting "testing"
+
+	"github.com/stremovskyy/cachemar/drivers/memory"
+	"github.com/stremovskyy/cachemar/testutils"
+)
+
+// CacherCall is a method call recorded by TestCacher.
+type CacherCall = testutils.CacheCall
+
+// TestCacher is a Cacher backed by the memory driver that records all method calls. It is a
+// testutils.RecordingCacher over an empty memory backend.
+type TestCacher struct {
+	*testutils.RecordingCacher
+}
+
+// NewTestCacher creates a TestCacher with an empty memory backend.
+func NewTestCacher() *TestCacher {
+	return &TestCacher{RecordingCacher: testutils.NewRecordingCacher(memory.New())}
+}
+
+// AssertCalled fails the test if the method was not called with the key.
+func (c *TestCacher) AssertCalled(t gotesting.TB, method, key string) {
+	t.Helper()
+	c.AssertCalledWith(t, method, key)
+}
+
+// AssertNotCalled fails the test if the method was called with the key.
+func (c *TestCacher) AssertNotCalled(t gotesting.TB, method, key string) {
+	t.Helper()
+	c.AssertNotCalledWith(t, method, key)
+}
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+	cachetesting "github.com/stremovskyy/cachemar/testing"
+)
+
+// blockingCacher holds every Set until release is closed.
+type blockingCacher struct {
+	cachemar.Cacher
+	release chan struct{}
+}
+
+func (c *blockingCacher) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
+	<-c.release
+	return c.Cacher.Set(ctx, key, value, ttl, tags)
+}
+
+func TestChainAsync(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	slow := &blockingCacher{Cacher: memory.New(), release: make(chan struct{})}
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+	manager.Register("slow", slow)
+	chain := manager.Chain()
+	chain.AddToChain("memory")
+	chain.AddToChain("slow")
+	chain.SetAsync("slow", true)
+
+	require.NoError(t, chain.Set(ctx, "key", "value", time.Minute, nil))
+	cancel()
+
+	exists, err := manager.Use("memory").Exists(context.Background(), "key")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = slow.Cacher.Exists(context.Background(), "key")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	close(slow.release)
+	require.NoError(t, manager.Close())
+
+	exists, err = slow.Cacher.Exists(context.Background(), "key")
+	require.NoError(t, err)
+	assert.True(t, exists, "the pending write runs on Close, even though its context was cancelled")
+}
+
+func TestChainAsyncErrorHandler(t *testing.T) {
+	ctx := context.Background()
+
+	errRemove := errors.New("remove failed")
+	broken := cachetesting.NewTestCacher()
+	broken.SimulateError("Remove", errRemove)
+
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+	manager.Register("broken", broken)
+	chain := manager.Chain()
+	chain.AddToChain("memory")
+	chain.AddToChain("broken")
+	chain.SetAsync("broken", true)
+	chain.SetAsyncWorkers(1)
+
+	var (
+		mu     sync.Mutex
+		failed []string
+	)
+	chain.SetAsyncErrorHandler(func(cacher string, op string, err error) {
+		assert.ErrorIs(t, err, errRemove)
+		mu.Lock()
+		failed = append(failed, cacher+"."+op)
+		mu.Unlock()
+	})
+
+	require.NoError(t, chain.Remove(ctx, "key"))
+	require.NoError(t, chain.Close())
+
+	assert.Equal(t, []string{"broken.Remove"}, failed)
+}
+
+func TestChainAsyncOrder(t *testing.T) {
+	ctx := context.Background()
+
+	slow := &slowSetCacher{Cacher: memory.New(), delay: 5 * time.Millisecond}
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+	manager.Register("slow", slow)
+	chain := manager.Chain()
+	chain.AddToChain("memory")
+	chain.AddToChain("slow")
+	chain.SetAsync("slow", true)
+
+	for _, key := range []string{"a", "b", "c"} {
+		require.NoError(t, chain.Set(ctx, key, "value", time.Minute, nil))
+		require.NoError(t, chain.Remove(ctx, key))
+	}
+	require.NoError(t, chain.Set(ctx, "tagged", "value", time.Minute, []string{"tag"}))
+	require.NoError(t, chain.RemoveByTag(ctx, "tag"))
+	require.NoError(t, manager.Close())
+
+	for _, key := range []string{"a", "b", "c", "tagged"} {
+		exists, err := slow.Cacher.Exists(ctx, key)
+		require.NoError(t, err)
+		assert.False(t, exists, "the removal of %q must not overtake its write", key)
+	}
+}
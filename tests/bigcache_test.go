@@ -0,0 +1,190 @@
+package tests
+
+import (
+	"context"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/bigcache"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func TestBigCache(t *testing.T) {
+	ctx := context.Background()
+	cache := bigcache.New(bigcache.DefaultOptions())
+	defer cache.Close()
+
+	require.NoError(t, cache.Set(ctx, "alice", profile{Name: "Alice", Age: 30}, time.Minute, []string{"users"}))
+	require.NoError(t, cache.Set(ctx, "bob", profile{Name: "Bob"}, time.Minute, []string{"users", "admins"}))
+
+	var value profile
+	require.NoError(t, cache.Get(ctx, "alice", &value))
+	assert.Equal(t, profile{Name: "Alice", Age: 30}, value)
+	assert.True(t, cachemar.IsNotFound(cache.Get(ctx, "missing", &value)))
+
+	ttl, err := cache.GetWithTTL(ctx, "alice", &value)
+	require.NoError(t, err)
+	assert.InDelta(t, time.Minute, ttl, float64(time.Second))
+
+	keys, err := cache.GetKeysByTag(ctx, "users")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"alice", "bob"}, keys)
+
+	// Retagging a key removes it from its previous tags.
+	require.NoError(t, cache.Set(ctx, "alice", profile{Name: "Alice"}, time.Minute, nil))
+	keys, err = cache.GetKeysByTag(ctx, "users")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob"}, keys)
+
+	require.NoError(t, cache.RemoveByTag(ctx, "admins"))
+	exists, err := cache.Exists(ctx, "bob")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	exists, err = cache.Exists(ctx, "alice")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	assert.ErrorIs(t, cache.Set(ctx, "a,b", "value", time.Minute, []string{"tag"}), cachemar.ErrInvalidKey)
+}
+
+func TestBigCacheExpiryAndCounters(t *testing.T) {
+	ctx := context.Background()
+	cache := bigcache.New(bigcache.DefaultOptions())
+	defer cache.Close()
+
+	require.NoError(t, cache.Set(ctx, "key", "value", 50*time.Millisecond, nil))
+	time.Sleep(100 * time.Millisecond)
+	var text string
+	assert.True(t, cachemar.IsNotFound(cache.Get(ctx, "key", &text)))
+
+	written, err := cache.SetIfAbsent(ctx, "key", "again", time.Minute, nil)
+	require.NoError(t, err)
+	assert.True(t, written)
+
+	count, err := cache.IncrBy(ctx, "counter", 3)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	count, err = cache.DecrBy(ctx, "counter", 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	_, err = cache.IncrBy(ctx, "key", 1)
+	assert.ErrorIs(t, err, cachemar.ErrInvalidType)
+
+	require.NoError(t, cache.Flush(ctx))
+	exists, err := cache.Exists(ctx, "counter")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	assert.Panics(t, func() { bigcache.New(&bigcache.Options{}) })
+}
+
+func TestBigCacheTagListOutlivesLifeWindow(t *testing.T) {
+	ctx := context.Background()
+	options := bigcache.DefaultOptions()
+	options.Config.LifeWindow = time.Second
+	options.Config.CleanWindow = 100 * time.Millisecond
+	cache := bigcache.New(options)
+	defer cache.Close()
+
+	// The key stays fresh, so the list of its tag must too.
+	for i := 0; i < 7; i++ {
+		require.NoError(t, cache.Set(ctx, "key", "value", 0, []string{"tag"}))
+		time.Sleep(400 * time.Millisecond)
+
+		keys, err := cache.GetKeysByTag(ctx, "tag")
+		require.NoError(t, err)
+		require.Equal(t, []string{"key"}, keys, "after %d writes", i+1)
+	}
+
+	require.NoError(t, cache.RemoveByTag(ctx, "tag"))
+	exists, err := cache.Exists(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+// benchItems is the number of items the caches hold while BenchmarkBigCacheVsMemory runs.
+const benchItems = 1_000_000
+
+// BenchmarkBigCacheVsMemory compares reads and writes with a million stored items, and reports the
+// duration of a full garbage collection with that heap, which the pointer-free storage of BigCache keeps short.
+func BenchmarkBigCacheVsMemory(b *testing.B) {
+	ctx := context.Background()
+
+	drivers := []struct {
+		name string
+		new  func() cachemar.Cacher
+	}{
+		{"bigcache", func() cachemar.Cacher { return bigcache.New(bigcache.DefaultOptions()) }},
+		{"memory", memory.NewWithJSON},
+	}
+
+	for _, driver := range drivers {
+		cache := driver.new()
+
+		fillCache(b, cache, benchItems)
+
+		b.Run(driver.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for n := 0; n < b.N; n++ {
+				key := "key" + strconv.Itoa(n%benchItems)
+
+				var value profile
+				if err := cache.Get(ctx, key, &value); err != nil {
+					b.Fatalf("Get failed: %v", err)
+				}
+				if err := cache.Set(ctx, key, value, time.Hour, nil); err != nil {
+					b.Fatalf("Set failed: %v", err)
+				}
+			}
+			b.StopTimer()
+
+			start := time.Now()
+			runtime.GC()
+			b.ReportMetric(float64(time.Since(start).Microseconds()), "gc-µs")
+		})
+
+		_ = cache.Close()
+	}
+}
+
+// fillCache stores count items in chunks on all CPUs, as the memory driver encodes values outside its lock.
+func fillCache(b *testing.B, cache cachemar.Cacher, count int) {
+	b.Helper()
+
+	const chunk = 10_000
+	var wg sync.WaitGroup
+	errs := make(chan error, count/chunk+1)
+	sem := make(chan struct{}, runtime.NumCPU())
+
+	for first := 0; first < count; first += chunk {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(first int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			items := make([]cachemar.CacheItem, 0, chunk)
+			for i := first; i < first+chunk && i < count; i++ {
+				items = append(items, cachemar.CacheItem{Key: "key" + strconv.Itoa(i), Value: profile{Name: "name", Age: i}, TTL: time.Hour})
+			}
+			if err := cache.MSet(context.Background(), items); err != nil {
+				errs <- err
+			}
+		}(first)
+	}
+	wg.Wait()
+	close(errs)
+
+	if err := <-errs; err != nil {
+		b.Fatalf("MSet failed: %v", err)
+	}
+}
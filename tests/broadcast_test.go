@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+	cachetesting "github.com/stremovskyy/cachemar/testing"
+)
+
+func TestBroadcast(t *testing.T) {
+	ctx := context.Background()
+
+	first, second := memory.New(), memory.New()
+	manager := cachemar.New()
+	manager.Register("first", first)
+	manager.Register("second", second)
+
+	require.NoError(t, manager.BroadcastSet(ctx, "config", "v1", time.Minute, nil))
+	for _, cache := range []cachemar.Cacher{first, second} {
+		var value string
+		require.NoError(t, cache.Get(ctx, "config", &value))
+		assert.Equal(t, "v1", value)
+	}
+
+	require.NoError(t, manager.BroadcastRemove(ctx, "config"))
+	for _, cache := range []cachemar.Cacher{first, second} {
+		exists, err := cache.Exists(ctx, "config")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	}
+}
+
+func TestBroadcastPartialFailure(t *testing.T) {
+	ctx := context.Background()
+
+	errSet := errors.New("set failed")
+	broken := cachetesting.NewTestCacher()
+	broken.SimulateError("Set", errSet)
+
+	healthy := memory.New()
+	manager := cachemar.New()
+	manager.Register("broken", broken)
+	manager.Register("healthy", healthy)
+
+	err := manager.BroadcastSet(ctx, "config", "v1", time.Minute, nil)
+	assert.ErrorIs(t, err, errSet)
+	assert.Contains(t, err.Error(), "broken")
+
+	var value string
+	require.NoError(t, healthy.Get(ctx, "config", &value))
+	assert.Equal(t, "v1", value)
+
+	manager.SetReadOnly(true)
+	assert.ErrorIs(t, manager.BroadcastRemove(ctx, "config"), cachemar.ErrReadOnly)
+}
@@ -2,10 +2,14 @@ package tests
 
 import (
 	"context"
+	"errors"
 	"github.com/stremovskyy/cachemar"
 	"github.com/stremovskyy/cachemar/drivers/memcached"
+	"github.com/stremovskyy/cachemar/drivers/memory"
 	"github.com/stremovskyy/cachemar/drivers/redis"
+	cachetesting "github.com/stremovskyy/cachemar/testing"
 	"github.com/stretchr/testify/assert"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -58,3 +62,209 @@ func TestCacheMarService(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "value", value)
 }
+
+func TestWarmChainFromTail(t *testing.T) {
+	ctx := context.Background()
+	cachemarService := setupCachemar()
+	cachemarService.Register("memory", memory.New())
+
+	chain := cachemarService.Chain()
+	chain.AddToChain("memory")
+	chain.AddToChain("redis")
+
+	err := cachemarService.Use("redis").Set(ctx, "warm-key", "value", 1*time.Minute, []string{"warm"})
+	assert.NoError(t, err)
+
+	err = chain.WarmChainFromTail(ctx, []string{"warm-key", "missing-key"})
+	assert.NoError(t, err)
+
+	var value string
+	err = cachemarService.Use("memory").Get(ctx, "warm-key", &value)
+	assert.NoError(t, err)
+	assert.Equal(t, "value", value)
+
+	exists, err := cachemarService.Use("memory").Exists(ctx, "missing-key")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestGetBatchWithSource(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.New()
+	manager.Register("l1", memory.New())
+	manager.Register("l2", memory.New())
+
+	chain := manager.Chain()
+	chain.AddToChain("l1")
+	chain.AddToChain("l2")
+
+	assert.NoError(t, manager.Use("l1").Set(ctx, "a", "value-a", time.Minute, nil))
+	assert.NoError(t, manager.Use("l2").Set(ctx, "b", "value-b", time.Minute, nil))
+
+	results, err := chain.GetBatchWithSource(ctx, []string{"a", "b", "c"}, func() interface{} { return new(string) })
+	assert.NoError(t, err)
+
+	assert.Equal(t, "l1", results["a"].Driver)
+	assert.Equal(t, "value-a", *results["a"].Value.(*string))
+	assert.Equal(t, "l2", results["b"].Driver)
+	assert.Equal(t, "value-b", *results["b"].Value.(*string))
+	assert.Equal(t, "", results["c"].Driver)
+	assert.Nil(t, results["c"].Value)
+}
+
+// batchCountingCacher counts the batches read from the wrapped memory cache manager.
+type batchCountingCacher struct {
+	cachemar.Cacher
+	batches atomic.Int32
+}
+
+func (c *batchCountingCacher) MGetWithTTL(ctx context.Context, keys []string, values []interface{}) ([]time.Duration, error) {
+	c.batches.Add(1)
+	return c.Cacher.(cachemar.BatchTTLGetter).MGetWithTTL(ctx, keys, values)
+}
+
+func TestGetBatchWithSourceBatchesLayers(t *testing.T) {
+	ctx := context.Background()
+
+	failing := cachetesting.NewTestCacher()
+	failing.SimulateError("GetWithTTL", errors.New("connection refused"))
+	l2 := &batchCountingCacher{Cacher: memory.New()}
+	l3 := &batchCountingCacher{Cacher: memory.New()}
+
+	manager := cachemar.New()
+	manager.Register("failing", failing)
+	manager.Register("l2", l2)
+	manager.Register("l3", l3)
+
+	chain := manager.Chain()
+	chain.AddToChain("failing")
+	chain.AddToChain("l2")
+	chain.AddToChain("l3")
+
+	assert.NoError(t, manager.Use("l2").Set(ctx, "a", "value-a", time.Minute, nil))
+	assert.NoError(t, manager.Use("l3").Set(ctx, "b", "value-b", time.Hour, nil))
+	assert.NoError(t, manager.Use("l3").Set(ctx, "c", "value-c", time.Hour, nil))
+
+	results, err := chain.GetBatchWithSource(ctx, []string{"a", "b", "c", "d"}, func() interface{} { return new(string) })
+	assert.ErrorContains(t, err, "connection refused")
+
+	assert.Equal(t, "l2", results["a"].Driver)
+	assert.InDelta(t, time.Minute, results["a"].TTLRemaining, float64(time.Second))
+	assert.Equal(t, "l3", results["b"].Driver)
+	assert.Equal(t, "value-b", *results["b"].Value.(*string))
+	assert.InDelta(t, time.Hour, results["b"].TTLRemaining, float64(time.Second))
+	assert.Equal(t, "l3", results["c"].Driver)
+	assert.Equal(t, "", results["d"].Driver)
+
+	assert.Equal(t, int32(1), l2.batches.Load())
+	assert.Equal(t, int32(1), l3.batches.Load())
+}
+
+func TestWarmChainKeepsTTLTagsAndType(t *testing.T) {
+	ctx := context.Background()
+
+	for name, newCache := range map[string]func() cachemar.Cacher{"gob": memory.New, "json": memory.NewWithJSON} {
+		t.Run(name, func(t *testing.T) {
+			tail := newCache()
+			manager := cachemar.New()
+			manager.Register("head", newCache())
+			manager.Register("tail", tail)
+
+			chain := manager.Chain()
+			chain.AddToChain("head")
+			chain.AddToChain("tail")
+
+			assert.NoError(t, tail.Set(ctx, "user", profile{Name: "Ann", Age: 30}, 10*time.Minute, []string{"users"}))
+			assert.NoError(t, chain.WarmChainFromTail(ctx, []string{"user", "missing"}))
+
+			head := manager.Use("head")
+
+			var value profile
+			ttl, err := head.GetWithTTL(ctx, "user", &value)
+			assert.NoError(t, err)
+			assert.Equal(t, profile{Name: "Ann", Age: 30}, value)
+			assert.InDelta(t, 10*time.Minute, ttl, float64(time.Second))
+
+			keys, err := head.GetKeysByTag(ctx, "users")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"user"}, keys)
+
+			exists, err := head.Exists(ctx, "missing")
+			assert.NoError(t, err)
+			assert.False(t, exists)
+		})
+	}
+}
+
+func TestWarmChainKeepsValuesUsable(t *testing.T) {
+	ctx := context.Background()
+
+	tail := memory.NewWithJSON()
+	manager := cachemar.New()
+	manager.Register("head", memory.NewWithJSON())
+	manager.Register("gob", memory.New())
+	manager.Register("tail", tail)
+
+	chain := manager.Chain()
+	chain.AddToChain("head")
+	chain.AddToChain("gob")
+	chain.AddToChain("tail")
+
+	assert.NoError(t, tail.Set(ctx, "bytes", []byte("hello"), time.Minute, nil))
+	assert.NoError(t, tail.Set(ctx, "counter", 1, time.Minute, nil))
+	assert.NoError(t, tail.Set(ctx, "name", "Ann", time.Minute, nil))
+	assert.NoError(t, tail.(cachemar.RawCacher).SetRaw(ctx, "raw", []byte("payload"), time.Minute, nil))
+	assert.NoError(t, chain.WarmChainFromTail(ctx, []string{"bytes", "counter", "name", "raw"}))
+
+	head := manager.Use("head")
+
+	var data []byte
+	assert.NoError(t, head.Get(ctx, "bytes", &data))
+	assert.Equal(t, []byte("hello"), data)
+
+	assert.NoError(t, head.Increment(ctx, "counter"))
+	var counter int
+	assert.NoError(t, head.Get(ctx, "counter", &counter))
+	assert.Equal(t, 2, counter)
+
+	assert.NoError(t, head.Get(ctx, "raw", &data))
+	assert.Equal(t, []byte("payload"), data)
+
+	var name string
+	assert.NoError(t, manager.Use("gob").Get(ctx, "name", &name))
+	assert.Equal(t, "Ann", name)
+}
+
+func TestWarmChainWithGlobalKeyPrefix(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.NewWithOptions(cachemar.WithGlobalKeyPrefix("env"))
+	manager.Register("head", memory.New())
+	manager.Register("tail", memory.New())
+
+	chain := manager.Chain()
+	chain.AddToChain("head")
+	chain.AddToChain("tail")
+
+	manager.SetCurrent("tail")
+	assert.NoError(t, manager.Set(ctx, "k", "value", time.Minute, []string{"tag"}))
+	assert.NoError(t, manager.Set(ctx, "tagged", "value", time.Minute, []string{"tag"}))
+
+	assert.NoError(t, chain.WarmChainFromTail(ctx, []string{"k"}))
+
+	manager.SetCurrent("head")
+	var value string
+	assert.NoError(t, manager.Get(ctx, "k", &value))
+	assert.Equal(t, "value", value)
+
+	exists, err := manager.Exists(ctx, "tagged")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	assert.NoError(t, chain.WarmChainByTag(ctx, "tag"))
+
+	keys, err := manager.GetKeysByTag(ctx, "tag")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"k", "tagged"}, keys)
+}
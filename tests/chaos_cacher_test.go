@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar/drivers/memory"
+	"github.com/stremovskyy/cachemar/testutils"
+)
+
+// chaosOutcomes returns the error of each of n Set calls, or nil if it succeeded.
+func chaosOutcomes(opts testutils.ChaosCacherOptions, n int) []error {
+	cache := testutils.NewChaosCacher(memory.New(), opts)
+
+	errs := make([]error, n)
+	for i := range errs {
+		errs[i] = cache.Set(context.Background(), "key", "value", time.Minute, nil)
+	}
+	return errs
+}
+
+func TestChaosCacher(t *testing.T) {
+	errs := chaosOutcomes(testutils.ChaosCacherOptions{ErrorRate: 0.3, PanicRate: 0.1, Seed: 42}, 1000)
+
+	var failed, panicked int
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		assert.ErrorIs(t, err, testutils.ErrChaos)
+		assert.Contains(t, err.Error(), "chaos injection")
+		failed++
+		if strings.Contains(err.Error(), "panic") {
+			panicked++
+		}
+	}
+	assert.InDelta(t, 370, failed, 60)
+	assert.InDelta(t, 100, panicked, 40)
+
+	assert.Equal(t, errs, chaosOutcomes(testutils.ChaosCacherOptions{ErrorRate: 0.3, PanicRate: 0.1, Seed: 42}, 1000),
+		"the same seed injects the same faults")
+
+	for _, err := range chaosOutcomes(testutils.ChaosCacherOptions{}, 100) {
+		require.NoError(t, err)
+	}
+}
+
+func TestChaosCacherLatency(t *testing.T) {
+	cache := testutils.NewChaosCacher(memory.New(), testutils.ChaosCacherOptions{LatencyP50: time.Second, LatencyP99: time.Second, Seed: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	var value string
+	err := cache.Get(ctx, "key", &value)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
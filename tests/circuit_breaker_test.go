@@ -0,0 +1,256 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+const circuitInterval = 20 * time.Millisecond
+
+// unreliableCacher fails Ping, Get and Set with a connection error while it is down, and only Get and Set
+// with a timeout while it is overloaded. Get and Set are counted and take delay.
+type unreliableCacher struct {
+	cachemar.Cacher
+	down       atomic.Bool
+	overloaded atomic.Bool
+	delay      time.Duration
+	calls      atomic.Int32
+}
+
+func (c *unreliableCacher) call() error {
+	c.calls.Add(1)
+	time.Sleep(c.delay)
+	if c.down.Load() {
+		return syscall.ECONNREFUSED
+	}
+	if c.overloaded.Load() {
+		return context.DeadlineExceeded
+	}
+	return nil
+}
+
+func (c *unreliableCacher) Ping() error {
+	if c.down.Load() {
+		return syscall.ECONNREFUSED
+	}
+	return nil
+}
+
+func (c *unreliableCacher) Get(ctx context.Context, key string, value interface{}) error {
+	if err := c.call(); err != nil {
+		return err
+	}
+	return c.Cacher.Get(ctx, key, value)
+}
+
+func (c *unreliableCacher) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
+	if err := c.call(); err != nil {
+		return err
+	}
+	return c.Cacher.Set(ctx, key, value, ttl, tags)
+}
+
+func newCircuitManager(opts ...cachemar.Option) (cachemar.Manager, *unreliableCacher, cachemar.Cacher) {
+	primary := &unreliableCacher{Cacher: memory.New()}
+	fallback := memory.New()
+
+	opts = append([]cachemar.Option{cachemar.WithCircuitBreaker("fallback"), cachemar.WithCheckInterval(circuitInterval)}, opts...)
+	manager := cachemar.NewWithOptions(opts...)
+	manager.Register("fallback", fallback)
+	manager.Register("primary", primary)
+
+	return manager, primary, fallback
+}
+
+// openCircuit takes the primary down and waits until the ping opened its circuit.
+func openCircuit(t *testing.T, manager cachemar.Manager, primary *unreliableCacher) {
+	t.Helper()
+
+	primary.down.Store(true)
+	require.Eventually(t, func() bool {
+		return manager.Set(context.Background(), "probe", "value", time.Minute, nil) == nil
+	}, time.Second, time.Millisecond)
+}
+
+func TestCircuitBreakerOpensOnPingFailure(t *testing.T) {
+	ctx := context.Background()
+	manager, primary, fallback := newCircuitManager()
+
+	require.NoError(t, manager.Set(ctx, "key", "primary", time.Minute, nil))
+	openCircuit(t, manager, primary)
+
+	require.NoError(t, manager.Set(ctx, "key", "fallback", time.Minute, nil))
+	var value string
+	require.NoError(t, fallback.Get(ctx, "key", &value))
+	assert.Equal(t, "fallback", value)
+}
+
+func TestCircuitBreakerHalfOpenProbe(t *testing.T) {
+	ctx := context.Background()
+	manager, primary, _ := newCircuitManager()
+	openCircuit(t, manager, primary)
+
+	time.Sleep(circuitInterval)
+	calls := primary.calls.Load()
+	assert.Error(t, manager.Set(ctx, "key", "value", time.Minute, nil), "the failed probe is returned")
+	assert.Equal(t, calls+1, primary.calls.Load())
+
+	require.NoError(t, manager.Set(ctx, "key", "value", time.Minute, nil), "the circuit opened again")
+	assert.Equal(t, calls+1, primary.calls.Load())
+
+	primary.down.Store(false)
+	time.Sleep(circuitInterval)
+	require.NoError(t, manager.Set(ctx, "key", "value", time.Minute, nil))
+	require.NoError(t, manager.Set(ctx, "key", "value", time.Minute, nil))
+	assert.Equal(t, calls+3, primary.calls.Load(), "the probe succeeded and closed the circuit")
+}
+
+func TestCircuitBreakerSingleProbe(t *testing.T) {
+	ctx := context.Background()
+	manager, primary, _ := newCircuitManager()
+	openCircuit(t, manager, primary)
+
+	primary.down.Store(false)
+	primary.delay = 50 * time.Millisecond
+	time.Sleep(circuitInterval)
+	calls := primary.calls.Load()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var value string
+			_ = manager.Get(ctx, "key", &value)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, calls+1, primary.calls.Load())
+}
+
+func TestCircuitBreakerThreshold(t *testing.T) {
+	ctx := context.Background()
+	manager, primary, _ := newCircuitManager(cachemar.WithCircuitBreakerThreshold(3, time.Second))
+
+	primary.overloaded.Store(true)
+	for i := 0; i < 3; i++ {
+		assert.ErrorIs(t, manager.Set(ctx, "key", "value", time.Minute, nil), context.DeadlineExceeded)
+	}
+
+	require.NoError(t, manager.Set(ctx, "key", "value", time.Minute, nil))
+	assert.Equal(t, int32(3), primary.calls.Load())
+}
+
+func TestCircuitBreakerThresholdWindow(t *testing.T) {
+	ctx := context.Background()
+	manager, primary, _ := newCircuitManager(cachemar.WithCircuitBreakerThreshold(2, 10*time.Millisecond))
+
+	primary.overloaded.Store(true)
+	assert.Error(t, manager.Set(ctx, "key", "value", time.Minute, nil))
+	time.Sleep(20 * time.Millisecond)
+	assert.Error(t, manager.Set(ctx, "key", "value", time.Minute, nil))
+
+	var value string
+	assert.ErrorIs(t, manager.Get(ctx, "missing", &value), context.DeadlineExceeded)
+	assert.Equal(t, int32(3), primary.calls.Load(), "failures outside the window don't open the circuit")
+}
+
+func TestCircuitBreakerCallbacks(t *testing.T) {
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var transitions []string
+	record := func(state string) func(string) {
+		return func(primaryCacher string) {
+			mu.Lock()
+			defer mu.Unlock()
+			transitions = append(transitions, state+" "+primaryCacher)
+		}
+	}
+	recorded := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), transitions...)
+	}
+
+	manager, primary, _ := newCircuitManager(cachemar.WithCircuitBreakerCallbacks(record("open"), record("close"), record("half-open")))
+	openCircuit(t, manager, primary)
+	assert.Equal(t, []string{"open primary"}, recorded())
+
+	time.Sleep(circuitInterval)
+	assert.Error(t, manager.Set(ctx, "key", "value", time.Minute, nil))
+	assert.Equal(t, []string{"open primary", "half-open primary", "open primary"}, recorded())
+
+	primary.down.Store(false)
+	time.Sleep(circuitInterval)
+	require.NoError(t, manager.Set(ctx, "key", "value", time.Minute, nil))
+	assert.Equal(t, []string{"open primary", "half-open primary", "open primary", "half-open primary", "close primary"}, recorded())
+}
+
+func TestCircuitBreakerProbeReleasedByEmptyMGet(t *testing.T) {
+	ctx := context.Background()
+	manager, primary, _ := newCircuitManager(cachemar.WithKeyValidator(cachemar.NewPIISafeKeyValidator()))
+	openCircuit(t, manager, primary)
+
+	primary.down.Store(false)
+	time.Sleep(circuitInterval)
+
+	assert.NoError(t, manager.MGet(ctx, nil, nil))
+	var value string
+	assert.Error(t, manager.MGet(ctx, []string{"user@example.com"}, []interface{}{&value}))
+
+	calls := primary.calls.Load()
+	require.NoError(t, manager.Set(ctx, "key", "value", time.Minute, nil))
+	require.NoError(t, manager.Set(ctx, "key", "value", time.Minute, nil))
+	assert.Equal(t, calls+2, primary.calls.Load(), "the probe was not taken by MGet and closed the circuit")
+}
+
+func TestCircuitBreakerThresholdKeepsDriverInterfaces(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.NewWithOptions(
+		cachemar.WithCircuitBreaker("fallback"),
+		cachemar.WithCircuitBreakerThreshold(3, time.Second),
+		cachemar.WithTagCircuitBreaker(1),
+	)
+	primary := &metadataSpy{Cacher: memory.New()}
+	manager.Register("fallback", memory.New())
+	manager.Register("primary", primary)
+
+	require.NoError(t, manager.Set(ctx, "a", "value", time.Minute, []string{"tag"}))
+	require.NoError(t, manager.Set(ctx, "b", "value", time.Minute, []string{"tag"}))
+	assert.ErrorIs(t, manager.RemoveByTag(ctx, "tag"), cachemar.ErrTagTooLarge, "the driver is still a TagCounter")
+
+	loader := func(ctx context.Context) (interface{}, time.Duration, []string, error) {
+		return 1, time.Minute, nil, nil
+	}
+	var value int
+	require.NoError(t, manager.GetPER(ctx, "per", &value, 0, loader))
+	assert.Positive(t, primary.metadataCalls.Load(), "the driver is still a MetadataGetter")
+}
+
+// metadataSpy counts the GetWithMetadata calls of the wrapped cache manager.
+type metadataSpy struct {
+	cachemar.Cacher
+	metadataCalls atomic.Int32
+}
+
+func (s *metadataSpy) GetWithMetadata(ctx context.Context, key string, value interface{}) (cachemar.Metadata, error) {
+	s.metadataCalls.Add(1)
+	return s.Cacher.(cachemar.MetadataGetter).GetWithMetadata(ctx, key, value)
+}
+
+func (s *metadataSpy) Unwrap() cachemar.Cacher {
+	return s.Cacher
+}
@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/codecs"
+)
+
+// wideStruct returns a pointer to a struct with n alternating int and string fields.
+func wideStruct(n int) interface{} {
+	fields := make([]reflect.StructField, n)
+	for i := range fields {
+		fields[i] = reflect.StructField{Name: fmt.Sprintf("Field%d", i), Type: reflect.TypeOf(0)}
+		if i%2 == 1 {
+			fields[i].Type = reflect.TypeOf("")
+		}
+	}
+
+	v := reflect.New(reflect.StructOf(fields))
+	for i := 0; i < n; i++ {
+		if i%2 == 1 {
+			v.Elem().Field(i).SetString(fmt.Sprintf("value-%d", i))
+		} else {
+			v.Elem().Field(i).SetInt(int64(i))
+		}
+	}
+
+	return v.Interface()
+}
+
+func TestCodecsRoundTrip(t *testing.T) {
+	for name, codec := range map[string]cachemar.Codec{
+		"json":    codecs.JSONCodec{},
+		"msgpack": codecs.NewMsgpackCodec(),
+	} {
+		t.Run(
+			name, func(t *testing.T) {
+				value := wideStruct(10)
+
+				data, err := codec.Marshal(value)
+				require.NoError(t, err)
+
+				decoded := reflect.New(reflect.TypeOf(value).Elem()).Interface()
+				require.NoError(t, codec.Unmarshal(data, decoded))
+				assert.Equal(t, value, decoded)
+			},
+		)
+	}
+}
+
+func BenchmarkCodecs(b *testing.B) {
+	for _, fields := range []int{10, 100, 1000} {
+		value := wideStruct(fields)
+
+		for name, codec := range map[string]cachemar.Codec{
+			"json":    codecs.JSONCodec{},
+			"msgpack": codecs.NewMsgpackCodec(),
+		} {
+			b.Run(
+				fmt.Sprintf("%s/%d", name, fields), func(b *testing.B) {
+					decoded := reflect.New(reflect.TypeOf(value).Elem()).Interface()
+
+					b.ReportAllocs()
+					for i := 0; i < b.N; i++ {
+						data, err := codec.Marshal(value)
+						if err != nil {
+							b.Fatal(err)
+						}
+						if err := codec.Unmarshal(data, decoded); err != nil {
+							b.Fatal(err)
+						}
+					}
+				},
+			)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+// truncatingCodec writes JSON that is cut off, like a value written in a format the reader doesn't expect.
+type truncatingCodec struct{}
+
+func (truncatingCodec) Marshal(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	return data[:len(data)-1], err
+}
+
+func (truncatingCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func TestAutoRemoveCorruptEntries(t *testing.T) {
+	ctx := context.Background()
+	manager := cachemar.NewWithOptions(cachemar.WithAutoRemoveCorruptEntries())
+	manager.Register("memory", memory.NewWithConfig(&memory.Config{Codec: truncatingCodec{}}))
+
+	require.NoError(t, manager.Set(ctx, "key", "value", time.Minute, nil))
+
+	var value string
+	err := manager.Get(ctx, "key", &value)
+	assert.ErrorIs(t, err, cachemar.ErrNotFound)
+	assert.Equal(t, uint64(1), manager.Stats().CorruptEntriesRemoved)
+
+	exists, err := manager.Exists(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Misses are not counted.
+	assert.ErrorIs(t, manager.Get(ctx, "missing", &value), cachemar.ErrNotFound)
+	assert.Equal(t, uint64(1), manager.Stats().CorruptEntriesRemoved)
+}
+
+func TestCorruptEntriesKeptByDefault(t *testing.T) {
+	ctx := context.Background()
+	manager := cachemar.New()
+	manager.Register("memory", memory.NewWithJSON())
+
+	require.NoError(t, manager.Set(ctx, "key", "not a number", time.Minute, nil))
+
+	var value int
+	err := manager.Get(ctx, "key", &value)
+	assert.True(t, cachemar.IsDecodeError(err))
+	assert.False(t, cachemar.IsNotFound(err))
+
+	exists, _ := manager.Exists(ctx, "key")
+	assert.True(t, exists)
+	assert.Equal(t, uint64(0), manager.Stats().CorruptEntriesRemoved)
+}
+
+func TestAutoRemoveCorruptEntriesKeepsTypeMismatches(t *testing.T) {
+	ctx := context.Background()
+
+	for name, cacher := range map[string]cachemar.Cacher{
+		"gob":     memory.New(),
+		"json":    memory.NewWithJSON(),
+		"msgpack": memory.NewWithMsgpack(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			manager := cachemar.NewWithOptions(cachemar.WithAutoRemoveCorruptEntries())
+			manager.Register("memory", cacher)
+			require.NoError(t, manager.Set(ctx, "key", "not a number", time.Minute, nil))
+
+			var number int
+			err := manager.Get(ctx, "key", &number)
+			assert.True(t, cachemar.IsDecodeError(err))
+			assert.Equal(t, uint64(0), manager.Stats().CorruptEntriesRemoved)
+
+			var value string
+			require.NoError(t, manager.Get(ctx, "key", &value))
+			assert.Equal(t, "not a number", value)
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+	cachetesting "github.com/stremovskyy/cachemar/testing"
+)
+
+func TestDiff(t *testing.T) {
+	ctx := context.Background()
+	a := memory.New()
+	b := memory.New()
+
+	_ = a.Set(ctx, "same", "value", time.Minute, nil)
+	_ = b.Set(ctx, "same", "value", time.Minute, nil)
+	_ = a.Set(ctx, "changed", "old", time.Minute, nil)
+	_ = b.Set(ctx, "changed", "new", time.Minute, nil)
+	_ = a.Set(ctx, "onlyA", "value", time.Minute, nil)
+	_ = b.(cachemar.RawCacher).SetRaw(ctx, "onlyB", []byte("raw"), time.Minute, nil)
+
+	result, err := cachemar.Diff(ctx, a, b, []string{"same", "changed", "onlyA", "onlyB", "missing"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"same"}, result.Matching)
+	assert.Equal(t, []string{"onlyA"}, result.OnlyInA)
+	assert.Equal(t, []string{"onlyB"}, result.OnlyInB)
+	assert.Len(t, result.Mismatched, 1)
+	assert.Equal(t, "changed", result.Mismatched[0].Key)
+
+	_, err = cachemar.Diff(ctx, a, cachetesting.NewTestCacher(), []string{"same"})
+	assert.Error(t, err)
+}
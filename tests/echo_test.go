@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	labstack "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+	cacheecho "github.com/stremovskyy/cachemar/echo"
+)
+
+func TestEchoCache(t *testing.T) {
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+
+	calls := 0
+	e := labstack.New()
+	e.Use(
+		cacheecho.Cache(
+			manager, cacheecho.EchoCacheConfig{
+				TTL:          time.Minute,
+				Skipper:      func(c labstack.Context) bool { return c.QueryParam("skip") != "" },
+				TagGenerator: func(c labstack.Context) []string { return []string{"items"} },
+			},
+		),
+	)
+	e.GET(
+		"/items/:id", func(c labstack.Context) error {
+			calls++
+			if c.Param("id") == "missing" {
+				return c.String(http.StatusNotFound, "not found")
+			}
+			c.Response().Header().Set("X-Item", c.Param("id"))
+			return c.String(http.StatusOK, "item "+c.Param("id"))
+		},
+	)
+
+	serve := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	serve("/items/1")
+	rec := serve("/items/1")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "item 1", rec.Body.String())
+	assert.Equal(t, "1", rec.Header().Get("X-Item"))
+	assert.Equal(t, 1, calls)
+
+	serve("/items/missing")
+	serve("/items/missing")
+	assert.Equal(t, 3, calls)
+
+	serve("/items/1?skip=1")
+	assert.Equal(t, 4, calls)
+
+	assert.NoError(t, manager.RemoveByTag(context.Background(), "items"))
+	serve("/items/1")
+	assert.Equal(t, 5, calls)
+}
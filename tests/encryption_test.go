@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/contrib/encryption"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+type secret struct {
+	Card string
+	CVV  int
+}
+
+func TestEncryptedCacher(t *testing.T) {
+	ctx := context.Background()
+
+	inner := memory.New()
+	key := bytes.Repeat([]byte{1}, encryption.KeySize)
+	cache, err := encryption.NewEncryptedCacher(inner, key)
+	require.NoError(t, err)
+
+	require.NoError(t, cache.Set(ctx, "card", secret{Card: "4111111111111111", CVV: 123}, time.Minute, []string{"cards"}))
+
+	var stored []byte
+	require.NoError(t, inner.Get(ctx, "card", &stored))
+	assert.NotContains(t, string(stored), "4111111111111111")
+
+	var value secret
+	require.NoError(t, cache.Get(ctx, "card", &value))
+	assert.Equal(t, secret{Card: "4111111111111111", CVV: 123}, value)
+
+	require.NoError(t, cache.Set(ctx, "other", secret{Card: "5500000000000004"}, time.Minute, nil))
+	values := []interface{}{&secret{}, &secret{}, &secret{}}
+	err = cache.MGet(ctx, []string{"card", "missing", "other"}, values)
+	assert.True(t, cachemar.IsNotFound(err))
+	assert.Equal(t, "4111111111111111", values[0].(*secret).Card)
+	assert.Equal(t, "5500000000000004", values[2].(*secret).Card)
+
+	require.NoError(t, inner.Set(ctx, "plain", []byte("not encrypted"), time.Minute, nil))
+	err = cache.Get(ctx, "plain", &value)
+	assert.ErrorIs(t, err, encryption.ErrNotEncrypted)
+	assert.True(t, cachemar.IsDecodeError(err))
+
+	assert.ErrorIs(t, cache.Increment(ctx, "counter"), encryption.ErrUnsupported)
+}
+
+func TestEncryptedCacherKeyRotation(t *testing.T) {
+	ctx := context.Background()
+
+	inner := memory.New()
+	oldKey := bytes.Repeat([]byte{1}, encryption.KeySize)
+	newKey := bytes.Repeat([]byte{2}, encryption.KeySize)
+
+	oldCache, err := encryption.NewEncryptedCacher(inner, oldKey)
+	require.NoError(t, err)
+	require.NoError(t, oldCache.Set(ctx, "key", "value", time.Minute, nil))
+
+	rotated, err := encryption.NewEncryptedCacherWithKeyring(inner, [][]byte{newKey, oldKey})
+	require.NoError(t, err)
+
+	var value string
+	require.NoError(t, rotated.Get(ctx, "key", &value))
+	assert.Equal(t, "value", value)
+
+	require.NoError(t, rotated.Set(ctx, "key", "rotated", time.Minute, nil))
+	assert.ErrorIs(t, oldCache.Get(ctx, "key", &value), encryption.ErrDecrypt)
+
+	_, err = encryption.NewEncryptedCacher(inner, []byte("short"))
+	assert.Error(t, err)
+}
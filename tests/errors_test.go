@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func TestDriverError(t *testing.T) {
+	cause := errors.New("boom")
+	err := fmt.Errorf("wrapped: %w", &cachemar.DriverError{Driver: "redis", Operation: "Get", Key: "app:user", Cause: cause})
+
+	var driverErr *cachemar.DriverError
+	assert.True(t, errors.As(err, &driverErr))
+	assert.Equal(t, "Get", driverErr.Operation)
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, `redis: Get "app:user": boom`, driverErr.Error())
+	assert.Equal(t, "redis: Ping: boom", (&cachemar.DriverError{Driver: "redis", Operation: "Ping", Cause: cause}).Error())
+}
+
+func TestDriverErrorNotFound(t *testing.T) {
+	driver := memory.New()
+
+	err := driver.Increment(context.Background(), "missing")
+	assert.True(t, cachemar.IsNotFound(err))
+	assert.ErrorIs(t, err, cachemar.ErrNotFound)
+
+	var driverErr *cachemar.DriverError
+	assert.True(t, errors.As(err, &driverErr))
+	assert.Equal(t, cachemar.MemoryCacherName.String(), driverErr.Driver)
+	assert.Equal(t, "missing", driverErr.Key)
+}
+
+func TestErrorClassifiers(t *testing.T) {
+	timeout := &cachemar.DriverError{Driver: "redis", Operation: "Get", Cause: context.DeadlineExceeded}
+	assert.True(t, cachemar.IsTimeout(timeout))
+	assert.False(t, cachemar.IsConnectionError(timeout))
+	assert.False(t, cachemar.IsNotFound(timeout))
+
+	refused := &cachemar.DriverError{
+		Driver: "redis", Operation: "Get",
+		Cause: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED},
+	}
+	assert.True(t, cachemar.IsConnectionError(refused))
+	assert.False(t, cachemar.IsTimeout(refused))
+
+	assert.False(t, cachemar.IsTimeout(nil))
+	assert.False(t, cachemar.IsConnectionError(nil))
+	assert.False(t, cachemar.IsNotFound(errors.New("other")))
+}
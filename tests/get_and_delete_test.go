@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func TestGetAndDelete(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+	require.NoError(t, manager.Set(ctx, "token", "secret", time.Minute, []string{"tokens"}))
+
+	var value string
+	require.NoError(t, manager.GetAndDelete(ctx, "token", &value))
+	assert.Equal(t, "secret", value)
+
+	assert.ErrorIs(t, manager.GetAndDelete(ctx, "token", &value), cachemar.ErrNotFound)
+
+	keys, err := manager.GetKeysByTag(ctx, "tokens")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	manager.SetReadOnly(true)
+	assert.ErrorIs(t, manager.GetAndDelete(ctx, "token", &value), cachemar.ErrReadOnly)
+}
+
+func TestGetAndDeleteConsumedOnce(t *testing.T) {
+	ctx := context.Background()
+
+	cache := memory.New()
+	require.NoError(t, cache.Set(ctx, "token", "secret", time.Minute, nil))
+
+	var consumed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var value string
+			if cache.GetAndDelete(ctx, "token", &value) == nil {
+				consumed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), consumed.Load())
+}
+
+func TestChainGetAndDelete(t *testing.T) {
+	ctx := context.Background()
+
+	l1, l2 := memory.New(), memory.New()
+	manager := cachemar.New()
+	manager.Register("l1", l1)
+	manager.Register("l2", l2)
+
+	chain := manager.Chain()
+	chain.AddToChain("l1")
+	chain.AddToChain("l2")
+
+	require.NoError(t, l2.Set(ctx, "token", "secret", time.Minute, nil))
+
+	var value string
+	require.NoError(t, chain.GetAndDelete(ctx, "token", &value))
+	assert.Equal(t, "secret", value)
+
+	exists, err := l2.Exists(ctx, "token")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	assert.ErrorIs(t, chain.GetAndDelete(ctx, "token", &value), cachemar.ErrNotFound)
+}
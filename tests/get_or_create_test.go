@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+type testLocker struct {
+	mu     sync.Mutex
+	locked map[string]bool
+	ttls   []time.Duration
+}
+
+func (l *testLocker) LockKey(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.ttls = append(l.ttls, ttl)
+
+	if l.locked[key] {
+		return "", cachemar.ErrLockNotAcquired
+	}
+	l.locked[key] = true
+
+	return "token", nil
+}
+
+func (l *testLocker) UnlockKey(ctx context.Context, key string, lockToken string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.locked, key)
+	return nil
+}
+
+func TestGetOrCreate(t *testing.T) {
+	ctx := context.Background()
+
+	for name, manager := range map[string]cachemar.Manager{
+		"local":       cachemar.New(),
+		"distributed": cachemar.NewWithOptions(cachemar.WithDistributedSingleflight(&testLocker{locked: make(map[string]bool)})),
+	} {
+		t.Run(
+			name, func(t *testing.T) {
+				manager.Register("memory", memory.New())
+
+				var calls int32
+				create := func(ctx context.Context) (interface{}, error) {
+					atomic.AddInt32(&calls, 1)
+					time.Sleep(50 * time.Millisecond)
+					return "created", nil
+				}
+
+				var wg sync.WaitGroup
+				for i := 0; i < 20; i++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+
+						var value string
+						err := manager.GetOrCreate(ctx, "key", &value, time.Minute, nil, create)
+						assert.NoError(t, err)
+						assert.Equal(t, "created", value)
+					}()
+				}
+				wg.Wait()
+
+				assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+			},
+		)
+	}
+}
+
+func TestGetOrSetSingleFlight(t *testing.T) {
+	ctx := context.Background()
+
+	for name, tt := range map[string]struct {
+		manager cachemar.Manager
+		calls   int32
+	}{
+		"enabled":  {manager: cachemar.New(), calls: 1},
+		"disabled": {manager: cachemar.NewWithOptions(cachemar.WithSingleFlight(false)), calls: 5},
+	} {
+		t.Run(
+			name, func(t *testing.T) {
+				tt.manager.Register("memory", memory.New())
+
+				var calls int32
+				loader := func(ctx context.Context) (interface{}, error) {
+					atomic.AddInt32(&calls, 1)
+					time.Sleep(50 * time.Millisecond)
+					return "loaded", nil
+				}
+
+				var wg sync.WaitGroup
+				for i := 0; i < 5; i++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+
+						var value string
+						err := tt.manager.GetOrSet(ctx, "key", &value, time.Minute, nil, loader)
+						assert.NoError(t, err)
+						assert.Equal(t, "loaded", value)
+					}()
+				}
+				wg.Wait()
+
+				assert.Equal(t, tt.calls, atomic.LoadInt32(&calls))
+			},
+		)
+	}
+}
+
+func TestManagerLocker(t *testing.T) {
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+
+	_, ok := manager.Locker()
+	assert.False(t, ok)
+
+	locker := &testLocker{locked: make(map[string]bool)}
+	manager = cachemar.NewWithOptions(cachemar.WithDistributedSingleflight(locker))
+	manager.Register("memory", memory.New())
+
+	got, ok := manager.Chain().Locker()
+	assert.True(t, ok)
+	assert.Same(t, locker, got)
+}
+
+func TestGetOrCreatePrefixedTagsAndReadOnly(t *testing.T) {
+	ctx := context.Background()
+
+	cache := memory.New()
+	manager := cachemar.NewWithOptions(cachemar.WithGlobalKeyPrefix("app"))
+	manager.Register("memory", cache)
+
+	create := func(ctx context.Context) (interface{}, error) {
+		return "created", nil
+	}
+
+	var value string
+	assert.NoError(t, manager.GetOrCreate(ctx, "key", &value, time.Minute, []string{"tag"}, create))
+
+	keys, err := manager.GetKeysByTag(ctx, "tag")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"key"}, keys)
+
+	manager.SetReadOnly(true)
+	assert.ErrorIs(t, manager.GetOrCreate(ctx, "other", &value, time.Minute, nil, create), cachemar.ErrReadOnly)
+	exists, err := manager.Exists(ctx, "other")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestGetOrCreateLockTTL(t *testing.T) {
+	ctx := context.Background()
+
+	locker := &testLocker{locked: make(map[string]bool)}
+	manager := cachemar.NewWithOptions(cachemar.WithDistributedSingleflight(locker))
+	manager.Register("memory", memory.New())
+
+	create := func(ctx context.Context) (interface{}, error) {
+		return "created", nil
+	}
+
+	var value string
+	assert.NoError(t, manager.GetOrCreate(ctx, "short", &value, 100*time.Millisecond, nil, create))
+	assert.NoError(t, manager.GetOrCreate(ctx, "hour", &value, time.Hour, nil, create))
+
+	assert.Equal(t, []time.Duration{time.Second, 54 * time.Minute}, locker.ttls, "short-lived values get the minimum lock TTL")
+}
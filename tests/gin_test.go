@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	gingonic "github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+	cachegin "github.com/stremovskyy/cachemar/gin"
+)
+
+func TestGinCache(t *testing.T) {
+	gingonic.SetMode(gingonic.TestMode)
+
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+
+	calls := 0
+	router := gingonic.New()
+	router.GET(
+		"/list", cachegin.Cache(manager, time.Minute, "lists"), func(c *gingonic.Context) {
+			calls++
+			c.String(http.StatusCreated, "page %s", c.Query("page"))
+		},
+	)
+	router.GET(
+		"/users/:id", cachegin.CacheByParam(manager, "id", time.Minute, "users"), func(c *gingonic.Context) {
+			calls++
+			c.String(http.StatusOK, "user %s", c.Param("id"))
+		},
+	)
+
+	serve := func(target string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("/list?page=1&size=10", nil)
+	assert.Equal(t, "MISS", rec.Header().Get("X-Cache"))
+
+	rec = serve("/list?size=10&page=1", nil)
+	assert.Equal(t, "HIT", rec.Header().Get("X-Cache"))
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "page 1", rec.Body.String())
+	assert.Equal(t, 1, calls)
+
+	serve("/list?page=1&size=10", http.Header{"Pragma": {"no-cache"}})
+	assert.Equal(t, 2, calls)
+
+	serve("/users/1", nil)
+	rec = serve("/users/1", nil)
+	assert.Equal(t, "user 1", rec.Body.String())
+	assert.Equal(t, 3, calls)
+
+	assert.NoError(t, manager.RemoveByTag(context.Background(), "users"))
+	rec = serve("/users/1", http.Header{"Cache-Control": {"max-age=0"}})
+	assert.Equal(t, "MISS", rec.Header().Get("X-Cache"))
+	assert.Equal(t, 4, calls)
+}
+
+func TestGinCacheSkipsUnsafeMethods(t *testing.T) {
+	gingonic.SetMode(gingonic.TestMode)
+
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+
+	calls := 0
+	router := gingonic.New()
+	handle := func(c *gingonic.Context) {
+		calls++
+		c.String(http.StatusOK, "%s %d", c.Request.Method, calls)
+	}
+	router.GET("/items", cachegin.Cache(manager, time.Minute), handle)
+	router.POST("/items", cachegin.Cache(manager, time.Minute), handle)
+
+	serve := func(method string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(method, "/items", nil))
+		return rec
+	}
+
+	serve(http.MethodGet)
+	rec := serve(http.MethodPost)
+	assert.Equal(t, "POST 2", rec.Body.String(), "a POST is never answered from the cache")
+	assert.Empty(t, rec.Header().Get("X-Cache"))
+
+	serve(http.MethodPost)
+	rec = serve(http.MethodGet)
+	assert.Equal(t, "HIT", rec.Header().Get("X-Cache"))
+	assert.Equal(t, "GET 1", rec.Body.String(), "POST responses are not cached")
+}
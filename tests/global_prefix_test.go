@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func TestGlobalKeyPrefix(t *testing.T) {
+	ctx := context.Background()
+
+	cache := memory.New()
+	manager := cachemar.NewWithOptions(cachemar.WithGlobalKeyPrefix("staging"))
+	manager.Register("memory", cache)
+
+	err := manager.Set(ctx, "key", "value", time.Minute, []string{"tag"})
+	assert.NoError(t, err)
+	assert.Equal(t, "staging:key", manager.EffectiveKey("key"))
+
+	exists, err := cache.Exists(ctx, "staging:key")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	rawKeys, err := cache.GetKeysByTag(ctx, "staging:tag")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"staging:key"}, rawKeys)
+
+	keys, err := manager.GetKeysByTag(ctx, "tag")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"key"}, keys)
+
+	var value string
+	err = manager.Get(ctx, "key", &value)
+	assert.NoError(t, err)
+	assert.Equal(t, "value", value)
+
+	err = manager.RemoveByTag(ctx, "tag")
+	assert.NoError(t, err)
+
+	exists, err = cache.Exists(ctx, "staging:key")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+	cachetesting "github.com/stremovskyy/cachemar/testing"
+)
+
+func TestCacheGroupMemory(t *testing.T) {
+	ctx := context.Background()
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+
+	require.NoError(t, manager.Set(ctx, "counter", 1, time.Minute, nil))
+
+	err := cachemar.Group(manager).
+		Add(func(ctx context.Context) error {
+			return manager.Set(ctx, "order:1", "paid", time.Minute, []string{"orders"})
+		}).
+		Add(func(ctx context.Context) error { return manager.Increment(ctx, "counter") }).
+		Execute(ctx)
+	require.NoError(t, err)
+
+	var counter int
+	require.NoError(t, manager.Get(ctx, "counter", &counter))
+	assert.Equal(t, 2, counter)
+
+	keys, err := manager.GetKeysByTag(ctx, "orders")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"order:1"}, keys)
+}
+
+func TestCacheGroupMemoryRollback(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.New()
+
+	require.NoError(t, cache.Set(ctx, "existing", "old", time.Minute, []string{"tag"}))
+	require.NoError(t, cache.Set(ctx, "removed", "value", time.Minute, nil))
+
+	failure := errors.New("boom")
+	err := cachemar.Group(cache).
+		Add(func(ctx context.Context) error { return cache.Set(ctx, "existing", "new", time.Minute, nil) }).
+		Add(func(ctx context.Context) error { return cache.Set(ctx, "created", "value", time.Minute, nil) }).
+		Add(func(ctx context.Context) error { return cache.Remove(ctx, "removed") }).
+		Add(func(ctx context.Context) error { return failure }).
+		Execute(ctx)
+	assert.ErrorIs(t, err, failure)
+
+	var value string
+	require.NoError(t, cache.Get(ctx, "existing", &value))
+	assert.Equal(t, "old", value)
+
+	exists, _ := cache.Exists(ctx, "created")
+	assert.False(t, exists)
+	exists, _ = cache.Exists(ctx, "removed")
+	assert.True(t, exists)
+
+	keys, err := cache.GetKeysByTag(ctx, "tag")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"existing"}, keys)
+}
+
+func TestCacheGroupMemoryFlush(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	cache := memory.New()
+
+	require.NoError(t, cache.Set(ctx, "existing", "old", time.Minute, []string{"tag"}))
+
+	failure := errors.New("boom")
+	err := cachemar.Group(cache).
+		Add(func(ctx context.Context) error { return cache.Set(ctx, "created", "value", time.Minute, nil) }).
+		Add(func(ctx context.Context) error { return cache.Flush(ctx) }).
+		Add(func(ctx context.Context) error { return failure }).
+		Execute(ctx)
+	assert.ErrorIs(t, err, failure)
+
+	var value string
+	require.NoError(t, cache.Get(ctx, "existing", &value))
+	assert.Equal(t, "old", value)
+
+	exists, _ := cache.Exists(ctx, "created")
+	assert.False(t, exists)
+
+	keys, err := cache.GetKeysByTag(ctx, "tag")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"existing"}, keys)
+
+	err = cachemar.Group(cache).
+		Add(func(ctx context.Context) error { return cache.Set(ctx, "created", "value", time.Minute, nil) }).
+		Add(func(ctx context.Context) error { return cache.Flush(ctx) }).
+		Execute(ctx)
+	require.NoError(t, err)
+
+	exists, _ = cache.Exists(ctx, "existing")
+	assert.False(t, exists)
+}
+
+func TestCacheGroupWithoutTransactions(t *testing.T) {
+	ctx := context.Background()
+	cacher := cachetesting.NewTestCacher()
+
+	err := cachemar.Group(cacher).
+		Add(func(ctx context.Context) error { return cacher.Set(ctx, "a", 1, time.Minute, nil) }).
+		Add(func(ctx context.Context) error { return cacher.Set(ctx, "b", 2, time.Minute, nil) }).
+		Execute(ctx)
+	require.NoError(t, err)
+
+	cacher.AssertCallCount(t, "Set", 2)
+}
@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+	"github.com/stremovskyy/cachemar/grpcmiddleware"
+)
+
+func TestGRPCUnaryServerInterceptor(t *testing.T) {
+	ctx := context.Background()
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+
+	interceptor := grpcmiddleware.UnaryServerInterceptor(
+		manager, grpcmiddleware.GRPCCacheOptions{
+			TTL:     time.Minute,
+			Methods: []string{"/greeter.Greeter/Hello"},
+			TagExtractor: func(ctx context.Context, method string) []string {
+				return []string{"greetings"}
+			},
+		},
+	)
+
+	calls := 0
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return wrapperspb.String("hello " + req.(*wrapperspb.StringValue).GetValue()), nil
+	}
+
+	hello := &grpc.UnaryServerInfo{FullMethod: "/greeter.Greeter/Hello"}
+	other := &grpc.UnaryServerInfo{FullMethod: "/greeter.Greeter/Other"}
+
+	resp, err := interceptor(ctx, wrapperspb.String("bob"), hello, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello bob", resp.(*wrapperspb.StringValue).GetValue())
+
+	resp, err = interceptor(ctx, wrapperspb.String("bob"), hello, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello bob", resp.(*wrapperspb.StringValue).GetValue())
+	assert.Equal(t, 1, calls)
+
+	_, _ = interceptor(ctx, wrapperspb.String("alice"), hello, handler)
+	assert.Equal(t, 2, calls)
+
+	_, _ = interceptor(ctx, wrapperspb.String("bob"), other, handler)
+	_, _ = interceptor(ctx, wrapperspb.String("bob"), other, handler)
+	assert.Equal(t, 4, calls)
+
+	assert.NoError(t, manager.RemoveByTag(ctx, "greetings"))
+	_, _ = interceptor(ctx, wrapperspb.String("bob"), hello, handler)
+	assert.Equal(t, 5, calls)
+}
+
+func TestGRPCUnaryServerInterceptorCachesNothingByDefault(t *testing.T) {
+	ctx := context.Background()
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+
+	interceptor := grpcmiddleware.UnaryServerInterceptor(manager, grpcmiddleware.GRPCCacheOptions{TTL: time.Minute})
+
+	calls := 0
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls++
+		return wrapperspb.String("deleted"), nil
+	}
+
+	remove := &grpc.UnaryServerInfo{FullMethod: "/greeter.Greeter/Delete"}
+	_, _ = interceptor(ctx, wrapperspb.String("bob"), remove, handler)
+	_, _ = interceptor(ctx, wrapperspb.String("bob"), remove, handler)
+	assert.Equal(t, 2, calls)
+}
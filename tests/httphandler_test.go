@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+	"github.com/stremovskyy/cachemar/httphandler"
+)
+
+func TestHTTPHandlerCache(t *testing.T) {
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+
+	calls := 0
+	handler := httphandler.Cache(
+		manager, httphandler.HTTPCacheOptions{
+			TTL:          time.Minute,
+			TagExtractor: func(r *http.Request) []string { return []string{"pages"} },
+			SkipIf:       func(r *http.Request) bool { return r.Header.Get("Cache-Control") == "no-cache" },
+		},
+	)(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.Header().Set("Content-Type", "text/plain")
+				_, _ = w.Write([]byte("hello " + r.URL.Query().Get("name")))
+			},
+		),
+	)
+
+	serve := func(target string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("/greet?name=bob", nil)
+	assert.Equal(t, "hello bob", rec.Body.String())
+
+	rec = serve("/greet?name=bob", nil)
+	assert.Equal(t, "hello bob", rec.Body.String())
+	assert.Equal(t, "text/plain", rec.Header().Get("Content-Type"))
+	assert.Equal(t, 1, calls)
+
+	serve("/greet?name=alice", nil)
+	assert.Equal(t, 2, calls)
+
+	serve("/greet?name=bob", http.Header{"Cache-Control": {"no-cache"}})
+	assert.Equal(t, 3, calls)
+
+	assert.NoError(t, manager.RemoveByTag(context.Background(), "pages"))
+	serve("/greet?name=bob", nil)
+	assert.Equal(t, 4, calls)
+}
@@ -0,0 +1,41 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func TestInspect(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.NewWithOptions(cachemar.WithGlobalKeyPrefix("app"))
+	manager.Register("memory", memory.NewWithJSON())
+
+	before := time.Now()
+	assert.NoError(t, manager.Set(ctx, "key", "value", time.Minute, []string{"tag"}))
+
+	result, err := manager.Inspect(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, "app:key", result.Key)
+	assert.Equal(t, "value", result.DecodedValue)
+	assert.Equal(t, []string{"app:tag"}, result.Tags)
+	assert.Equal(t, "memory", result.Driver)
+	assert.True(t, result.Compressed)
+	assert.Equal(t, int64(len(result.RawBytes)), result.SizeBytes)
+	assert.InDelta(t, time.Minute, result.TTLRemaining, float64(time.Second))
+	assert.False(t, result.SetAt.Before(before))
+
+	manager.Chain().AddToChain("memory")
+	chainResult, err := manager.Chain().Inspect(ctx, "key")
+	assert.NoError(t, err)
+	assert.Equal(t, result.Key, chainResult.Key)
+
+	_, err = manager.Inspect(ctx, "missing")
+	assert.ErrorIs(t, err, cachemar.ErrNotFound)
+}
@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+	"github.com/stremovskyy/cachemar/invalidation"
+)
+
+func TestInvalidationLogReplay(t *testing.T) {
+	ctx := context.Background()
+	shared := memory.New()
+	online := memory.New()
+	offline := memory.New()
+
+	for _, cache := range []cachemar.Cacher{online, offline} {
+		assert.NoError(t, cache.Set(ctx, "key", "value", time.Minute, nil))
+		assert.NoError(t, cache.Set(ctx, "tagged", "value", time.Minute, []string{"tag"}))
+	}
+
+	shutdown := time.Now()
+
+	log := invalidation.Log(shared, "invalidation-log", 10).WithLocal(online)
+	assert.NoError(t, log.Remove(ctx, "key"))
+	assert.NoError(t, log.RemoveByTag(ctx, "tag"))
+
+	err := invalidation.Log(shared, "invalidation-log", 10).WithLocal(offline).ReplayLog(ctx, shutdown)
+	assert.NoError(t, err)
+
+	for _, key := range []string{"key", "tagged"} {
+		exists, err := offline.Exists(ctx, key)
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	}
+}
+
+func TestInvalidationLogTrim(t *testing.T) {
+	ctx := context.Background()
+	shared := memory.New()
+
+	log := invalidation.Log(shared, "invalidation-log", 2)
+	for _, key := range []string{"a", "b", "c"} {
+		assert.NoError(t, log.Remove(ctx, key))
+	}
+
+	var events []invalidation.Event
+	assert.NoError(t, shared.Get(ctx, "invalidation-log", &events))
+	assert.Len(t, events, 2)
+	assert.Equal(t, "c", events[0].Key)
+
+	assert.NoError(t, log.TrimLog(ctx, 1))
+	assert.NoError(t, shared.Get(ctx, "invalidation-log", &events))
+	assert.Len(t, events, 1)
+}
@@ -0,0 +1,25 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+func TestKeyBuilder(t *testing.T) {
+	key := cachemar.KeyBuilder{}.Prefix("app").Entity("user").ID(42).Field("profile")
+	assert.Equal(t, "app:user:42:profile", key.Build())
+	assert.Equal(t, []string{"app:user", "app:user:42"}, key.Tags())
+
+	base := cachemar.KeyBuilder{}.Prefix("app").Entity("order")
+	first := base.ID(1)
+	second := base.ID(2)
+	assert.Equal(t, "app:order:1", first.Build())
+	assert.Equal(t, "app:order:2", second.Build())
+	assert.Equal(t, "app:order", base.MustBuild())
+
+	assert.Equal(t, "", cachemar.KeyBuilder{}.Build())
+	assert.Panics(t, func() { cachemar.KeyBuilder{}.MustBuild() })
+}
@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func TestKeyTransformer(t *testing.T) {
+	transformer := cachemar.NewKeyTransformer(cachemar.TrimKey, cachemar.LowerCaseKey, cachemar.SnakeCaseKey)
+	assert.Equal(t, "user_id:_42", transformer.Transform("  User-ID: 42 "))
+
+	assert.Equal(t, "abc", cachemar.TruncateKey(3)("abcdef"))
+	assert.Equal(t, "short", cachemar.HashLongKeys(10)("short"))
+	assert.True(t, strings.HasPrefix(cachemar.HashLongKeys(10)(strings.Repeat("x", 11)), "sha256:"))
+}
+
+func TestManagerWithKeyTransformer(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.NewWithOptions(
+		cachemar.WithKeyTransformer(cachemar.NewKeyTransformer(cachemar.TrimKey, cachemar.LowerCaseKey, cachemar.SnakeCaseKey)),
+	)
+	cache := memory.New()
+	manager.Register("memory", cache)
+
+	err := manager.Set(ctx, "User ID: 42", "value", time.Minute, nil)
+	assert.NoError(t, err)
+
+	var value string
+	err = manager.Get(ctx, "User ID: 42", &value)
+	assert.NoError(t, err)
+	assert.Equal(t, "value", value)
+
+	err = manager.Get(ctx, " user-id: 42", &value)
+	assert.NoError(t, err)
+	assert.Equal(t, "value", value)
+
+	exists, err := cache.Exists(ctx, "user_id:_42")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+}
@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func TestPIISafeKeyValidator(t *testing.T) {
+	validator := cachemar.NewPIISafeKeyValidator()
+
+	assert.NoError(t, validator.Validate("user:42:profile"))
+	assert.Error(t, validator.Validate("user:john.doe@example.com"))
+	assert.Error(t, validator.Validate("card:4111111111111111"))
+	assert.Error(t, validator.Validate("mention:@john"))
+
+	err := validator.Validate("user:john.doe@example.com")
+	assert.ErrorIs(t, err, cachemar.ErrInvalidKey)
+	assert.NotContains(t, err.Error(), "john.doe")
+}
+
+func TestManagerWithKeyValidator(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.NewWithOptions(cachemar.WithKeyValidator(cachemar.NewPIISafeKeyValidator()))
+	manager.Register("memory", memory.New())
+
+	err := manager.Set(ctx, "user:john.doe@example.com", "value", time.Minute, nil)
+	assert.True(t, errors.Is(err, cachemar.ErrInvalidKey))
+
+	err = manager.Set(ctx, "user:42", "value", time.Minute, nil)
+	assert.NoError(t, err)
+
+	var value string
+	err = manager.Get(ctx, "user:42", &value)
+	assert.NoError(t, err)
+	assert.Equal(t, "value", value)
+
+	noop := cachemar.NewWithOptions(cachemar.WithKeyValidator(cachemar.NoopKeyValidator{}))
+	noop.Register("memory", memory.New())
+
+	err = noop.Set(ctx, "user:john.doe@example.com", "value", time.Minute, nil)
+	assert.NoError(t, err)
+}
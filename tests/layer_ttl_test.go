@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func layerTTL(t *testing.T, manager cachemar.Manager, name, key string) time.Duration {
+	t.Helper()
+
+	var value string
+	ttl, err := manager.Use(name).GetWithTTL(context.Background(), key, &value)
+	require.NoError(t, err)
+	return ttl
+}
+
+func TestChainLayerTTLScale(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+	manager.Register("backup", memory.New())
+	manager.Register("shared", memory.New())
+	chain := manager.Chain()
+	chain.AddToChain("memory")
+	chain.AddToChain("shared")
+	chain.AddToChain("backup")
+	chain.SetLayerTTLScale("memory", 0.1)
+	chain.SetLayerTTLScale("backup", 2)
+
+	require.NoError(t, chain.Set(ctx, "key", "value", time.Hour, nil))
+
+	assert.InDelta(t, 6*time.Minute, layerTTL(t, manager, "memory", "key"), float64(time.Second))
+	assert.InDelta(t, time.Hour, layerTTL(t, manager, "shared", "key"), float64(time.Second))
+	assert.InDelta(t, 2*time.Hour, layerTTL(t, manager, "backup", "key"), float64(time.Second))
+}
+
+func TestChainLayerTTLScaleZero(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+	manager.Register("shared", memory.New())
+	chain := manager.Chain()
+	chain.AddToChain("memory")
+	chain.AddToChain("shared")
+	chain.SetLayerTTLScale("memory", 0)
+	chain.SetMinLayerTTL(30 * time.Second)
+	chain.SetReadThrough(true, 0)
+
+	require.NoError(t, manager.Use("shared").Set(ctx, "key", "value", time.Hour, nil))
+
+	var value string
+	require.NoError(t, chain.Get(ctx, "key", &value))
+
+	assert.InDelta(t, 30*time.Second, layerTTL(t, manager, "memory", "key"), float64(time.Second))
+}
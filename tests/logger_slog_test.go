@@ -0,0 +1,26 @@
+//go:build go1.21
+
+package tests
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/contrib/logging"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func TestSlogAdapter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	manager := cachemar.NewWithOptions(cachemar.WithLogger(logging.NewSlogAdapter(logger)))
+	manager.Register("redis", memory.New())
+
+	assert.Contains(t, buf.String(), "level=ERROR")
+	assert.Contains(t, buf.String(), "operation=Register cacher=redis driver=memory")
+}
@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/contrib/logging"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+	cachetesting "github.com/stremovskyy/cachemar/testing"
+)
+
+type recordingLogger struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (l *recordingLogger) Debug(msg string, fields ...interface{}) {
+	l.record("debug", msg, fields)
+}
+
+func (l *recordingLogger) Error(msg string, fields ...interface{}) {
+	l.record("error", msg, fields)
+}
+
+func (l *recordingLogger) record(level, msg string, fields []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = append(l.entries, fmt.Sprint(level, " ", msg, " ", fields))
+}
+
+func TestWithLogger(t *testing.T) {
+	ctx := context.Background()
+	logger := &recordingLogger{}
+
+	manager := cachemar.NewWithOptions(cachemar.WithLogger(logger))
+	failing := cachetesting.NewTestCacher()
+	failing.SimulateError("Get", errors.New("connection refused"))
+	manager.Register("failing", failing)
+	manager.Register("empty", cachetesting.NewTestCacher())
+	manager.Register("memory", memory.New())
+	require.NoError(t, manager.Use("memory").Set(ctx, "key", "value", time.Minute, nil))
+
+	chain := manager.Chain()
+	chain.AddToChain("failing")
+	chain.AddToChain("empty")
+	chain.AddToChain("memory")
+
+	var value string
+	require.NoError(t, chain.Get(ctx, "key", &value))
+
+	assert.Equal(
+		t, []string{
+			"error cachemar: cache manager failed [operation Get key key cacher failing error connection refused]",
+			"debug cachemar: cache manager missed [operation Get key key cacher empty]",
+		}, logger.entries,
+	)
+}
+
+func TestZapAdapter(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	manager := cachemar.NewWithOptions(cachemar.WithLogger(logging.NewZapAdapter(zap.New(core))))
+	manager.Register("redis", memory.New())
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, zapcore.ErrorLevel, entries[0].Level)
+	assert.Equal(
+		t, map[string]interface{}{"operation": "Register", "cacher": "redis", "driver": "memory"},
+		entries[0].ContextMap(),
+	)
+}
@@ -2,9 +2,24 @@ package tests
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"github.com/bradfitz/gomemcache/memcache"
 	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/codecs"
 	"github.com/stremovskyy/cachemar/drivers/memcached"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -86,3 +101,347 @@ func TestIncrementDecrement(t *testing.T) {
 	err = memcacheCacheService.Remove(ctx, "key")
 	assert.NoError(t, err)
 }
+
+func TestMemcachedRawValues(t *testing.T) {
+	setup()
+	ctx := context.Background()
+	raw := memcacheCacheService.(cachemar.RawCacher)
+
+	err := raw.SetRaw(ctx, "rawKey", []byte{0x89, 'P', 'N', 'G'}, time.Minute, nil)
+	assert.NoError(t, err)
+
+	value, err := raw.GetRaw(ctx, "rawKey")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x89, 'P', 'N', 'G'}, value)
+
+	var str string
+	err = memcacheCacheService.Get(ctx, "rawKey", &str)
+	assert.ErrorIs(t, err, cachemar.ErrRawValue)
+}
+
+func TestMemcachedIncrementFloat(t *testing.T) {
+	setup()
+	ctx := context.Background()
+	_ = memcacheCacheService.Remove(ctx, "floatKey")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := memcacheCacheService.IncrementFloat(ctx, "floatKey", 0.25)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	value, err := memcacheCacheService.DecrementFloat(ctx, "floatKey", 0.5)
+	assert.NoError(t, err)
+	assert.Equal(t, 24.5, value)
+
+	err = memcacheCacheService.Set(ctx, "floatText", "abc", time.Minute, nil)
+	assert.NoError(t, err)
+
+	_, err = memcacheCacheService.IncrementFloat(ctx, "floatText", 1)
+	assert.ErrorIs(t, err, cachemar.ErrInvalidType)
+}
+
+func TestMemcachedIncrBy(t *testing.T) {
+	setup()
+	ctx := context.Background()
+	_ = memcacheCacheService.Remove(ctx, "intKey")
+
+	value, err := memcacheCacheService.IncrBy(ctx, "intKey", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), value)
+
+	value, err = memcacheCacheService.DecrBy(ctx, "intKey", 4)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), value)
+
+	value, err = memcacheCacheService.DecrBy(ctx, "intKey", 100)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), value)
+
+	err = memcacheCacheService.Set(ctx, "intText", "abc", time.Minute, nil)
+	assert.NoError(t, err)
+
+	_, err = memcacheCacheService.IncrBy(ctx, "intText", 1)
+	assert.ErrorIs(t, err, cachemar.ErrInvalidType)
+}
+
+func TestMemcachedFlush(t *testing.T) {
+	ctx := context.Background()
+	cache := memcached.New(&memcached.Options{Servers: []string{"localhost:11211"}, Prefix: "flush"})
+
+	require.NoError(t, cache.Set(ctx, "key", "value", time.Minute, nil))
+	require.NoError(t, cache.Flush(ctx))
+
+	exists, err := cache.Exists(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestMemcachedGetWithTTL(t *testing.T) {
+	ctx := context.Background()
+	cache := memcached.New(&memcached.Options{Servers: []string{"localhost:11211"}, Prefix: "ttl"})
+
+	require.NoError(t, cache.Set(ctx, "expiring", "value", time.Minute, nil))
+	require.NoError(t, cache.Set(ctx, "persistent", "value", 0, nil))
+
+	var value string
+	ttl, err := cache.GetWithTTL(ctx, "expiring", &value)
+	require.NoError(t, err)
+	assert.Equal(t, "value", value)
+	assert.InDelta(t, time.Minute, ttl, float64(2*time.Second))
+
+	ttl, err = cache.GetWithTTL(ctx, "persistent", &value)
+	require.NoError(t, err)
+	assert.Equal(t, cachemar.NoExpiry, ttl)
+
+	_, err = cache.GetWithTTL(ctx, "missing", &value)
+	assert.ErrorIs(t, err, cachemar.ErrNotFound)
+}
+
+func TestMemcachedSetIfAbsentAndPresent(t *testing.T) {
+	ctx := context.Background()
+	cache := memcached.New(&memcached.Options{Servers: []string{"localhost:11211"}, Prefix: "setif"})
+	_ = cache.Remove(ctx, "key")
+
+	written, err := cache.SetIfPresent(ctx, "key", "first", time.Minute, nil)
+	require.NoError(t, err)
+	assert.False(t, written)
+
+	written, err = cache.SetIfAbsent(ctx, "key", "first", time.Minute, nil)
+	require.NoError(t, err)
+	assert.True(t, written)
+
+	written, err = cache.SetIfAbsent(ctx, "key", "second", time.Minute, nil)
+	require.NoError(t, err)
+	assert.False(t, written)
+
+	written, err = cache.SetIfPresent(ctx, "key", "third", time.Minute, nil)
+	require.NoError(t, err)
+	assert.True(t, written)
+
+	var value string
+	require.NoError(t, cache.Get(ctx, "key", &value))
+	assert.Equal(t, "third", value)
+	assert.NoError(t, cache.Remove(ctx, "key"))
+}
+
+func TestMemcachedGetAndDelete(t *testing.T) {
+	ctx := context.Background()
+	cache := memcached.New(&memcached.Options{Servers: []string{"localhost:11211"}, Prefix: "getdel"})
+
+	require.NoError(t, cache.Set(ctx, "token", "secret", time.Minute, nil))
+
+	var value string
+	require.NoError(t, cache.GetAndDelete(ctx, "token", &value))
+	assert.Equal(t, "secret", value)
+
+	assert.ErrorIs(t, cache.GetAndDelete(ctx, "token", &value), cachemar.ErrNotFound)
+}
+
+func TestMemcachedMGet(t *testing.T) {
+	ctx := context.Background()
+	cache := memcached.New(&memcached.Options{Servers: []string{"localhost:11211"}, Prefix: "mget"})
+	_ = cache.Remove(ctx, "missing")
+
+	require.NoError(t, cache.Set(ctx, "a", "first", time.Minute, nil))
+	require.NoError(t, cache.Set(ctx, "b", 2, time.Minute, nil))
+
+	var a, missing string
+	var b int
+	err := cache.MGet(ctx, []string{"a", "missing", "b"}, []interface{}{&a, &missing, &b})
+
+	var errs cachemar.MultiError
+	require.True(t, errors.As(err, &errs))
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], cachemar.ErrNotFound)
+	assert.NoError(t, errs[2])
+	assert.Equal(t, "first", a)
+	assert.Equal(t, 2, b)
+}
+
+func TestMemcachedMSet(t *testing.T) {
+	ctx := context.Background()
+	cache := memcached.New(&memcached.Options{Servers: []string{"localhost:11211"}, Prefix: "mset"})
+
+	err := cache.MSet(
+		ctx, []cachemar.CacheEntry{
+			{Key: "a", Value: "value-a", TTL: time.Minute, Tags: []string{"mset-tag"}},
+			{Key: "b", Value: 2, TTL: time.Minute},
+		},
+	)
+	require.NoError(t, err)
+
+	var a string
+	var b int
+	require.NoError(t, cache.MGet(ctx, []string{"a", "b"}, []interface{}{&a, &b}))
+	assert.Equal(t, "value-a", a)
+	assert.Equal(t, 2, b)
+}
+
+func TestMemcachedTouch(t *testing.T) {
+	ctx := context.Background()
+	cache := memcached.New(&memcached.Options{Servers: []string{"localhost:11211"}, Prefix: "touch"})
+	_ = cache.Remove(ctx, "missing")
+
+	require.NoError(t, cache.Set(ctx, "session", "user-1", time.Second, nil))
+	require.NoError(t, cache.Touch(ctx, "session", time.Hour))
+
+	var value string
+	ttl, err := cache.GetWithTTL(ctx, "session", &value)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", value)
+	assert.Greater(t, ttl, time.Minute)
+
+	assert.ErrorIs(t, cache.Touch(ctx, "missing", time.Hour), cachemar.ErrNotFound)
+}
+
+func TestMemcachedCodec(t *testing.T) {
+	type user struct {
+		Name string
+		Age  int
+	}
+
+	ctx := context.Background()
+	options := (&memcached.Options{Servers: []string{"localhost:11211"}, Prefix: "codec"}).WithCodec(codecs.GobCodec{})
+	cache := memcached.New(options)
+
+	require.NoError(t, cache.Set(ctx, "user", user{Name: "Ann", Age: 42}, time.Minute, nil))
+
+	var got user
+	require.NoError(t, cache.Get(ctx, "user", &got))
+	assert.Equal(t, user{Name: "Ann", Age: 42}, got)
+	assert.NoError(t, cache.Remove(ctx, "user"))
+}
+
+func TestMemcachedCompressionValidate(t *testing.T) {
+	options := &memcached.Options{Servers: []string{"localhost:11211"}, CompressionEnabled: true, CompressionAlgo: "zstd"}
+	assert.NoError(t, options.Validate())
+
+	options.CompressionAlgo = "brotli"
+	assert.Error(t, options.Validate())
+
+	options.CompressionAlgo = ""
+	options.CompressionThreshold = -1
+	assert.Error(t, options.Validate())
+}
+
+func TestMemcachedCompression(t *testing.T) {
+	ctx := context.Background()
+	value := strings.Repeat("compressible ", 100)
+	client := memcache.New("localhost:11211")
+
+	// A driver without compression reads compressed values, and the other way around.
+	plain := memcached.New(&memcached.Options{Servers: []string{"localhost:11211"}, Prefix: "compression"})
+	require.NoError(t, plain.Set(ctx, "legacy", value, time.Minute, nil))
+
+	for _, algo := range []string{"gzip", "zstd", "lz4"} {
+		t.Run(
+			algo, func(t *testing.T) {
+				cache := memcached.New(
+					&memcached.Options{
+						Servers:              []string{"localhost:11211"},
+						Prefix:               "compression",
+						CompressionEnabled:   true,
+						CompressionAlgo:      algo,
+						CompressionThreshold: 64,
+					},
+				)
+				require.NoError(t, cache.Set(ctx, "key", value, time.Minute, nil))
+				require.NoError(t, cache.Set(ctx, "small", "tiny", time.Minute, nil))
+
+				var got string
+				require.NoError(t, cache.Get(ctx, "key", &got))
+				assert.Equal(t, value, got)
+				require.NoError(t, plain.Get(ctx, "key", &got))
+				assert.Equal(t, value, got)
+				require.NoError(t, cache.Get(ctx, "legacy", &got))
+				assert.Equal(t, value, got)
+
+				item, err := client.Get("compression:key")
+				require.NoError(t, err)
+				assert.Less(t, len(item.Value), len(value))
+
+				item, err = client.Get("compression:small")
+				require.NoError(t, err)
+				assert.Equal(t, `"tiny"`, string(item.Value), "values below the threshold are not compressed")
+			},
+		)
+	}
+}
+
+// startTLSProxy terminates TLS with a self-signed certificate for localhost and forwards the connections to
+// target. It returns the address of the proxy and a pool trusting its certificate.
+func startTLSProxy(t *testing.T, target string) (string, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				upstream, err := net.Dial("tcp", target)
+				if err != nil {
+					return
+				}
+				defer upstream.Close()
+				go func() { _, _ = io.Copy(upstream, conn) }()
+				_, _ = io.Copy(conn, upstream)
+			}()
+		}
+	}()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return listener.Addr().String(), pool
+}
+
+func TestMemcachedTLS(t *testing.T) {
+	ctx := context.Background()
+	addr, pool := startTLSProxy(t, "localhost:11211")
+
+	cache := memcached.New(&memcached.Options{Servers: []string{addr}, Prefix: "tls", TLSConfig: &tls.Config{RootCAs: pool}})
+	require.NoError(t, cache.Ping())
+	require.NoError(t, cache.Set(ctx, "key", "value", time.Minute, nil))
+
+	var got string
+	require.NoError(t, cache.Get(ctx, "key", &got))
+	assert.Equal(t, "value", got)
+
+	info, err := cache.(cachemar.BackendInformer).BackendInfo(ctx)
+	require.NoError(t, err)
+	assert.NotEmpty(t, info.Version)
+
+	untrusted := memcached.New(&memcached.Options{Servers: []string{addr}, Prefix: "tls", TLSConfig: &tls.Config{}})
+	assert.Error(t, untrusted.Ping(), "the self-signed certificate is not trusted")
+}
@@ -3,6 +3,11 @@ package tests_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -154,3 +159,843 @@ func TestMemoryCache(t *testing.T) {
 		},
 	)
 }
+
+func TestMemoryCodecs(t *testing.T) {
+	ctx := context.Background()
+
+	caches := map[string]cachemar.Cacher{
+		"json":    memory.NewWithJSON(),
+		"msgpack": memory.NewWithMsgpack(),
+	}
+
+	for name, cache := range caches {
+		t.Run(
+			name, func(t *testing.T) {
+				value := map[string]interface{}{
+					"name":  "John",
+					"score": 1.5,
+					"tags":  []interface{}{"a", "b"},
+				}
+				if err := cache.Set(ctx, "map", value, time.Minute, nil); err != nil {
+					t.Fatalf("Set failed: %v", err)
+				}
+
+				var retrieved map[string]interface{}
+				if err := cache.Get(ctx, "map", &retrieved); err != nil {
+					t.Fatalf("Get failed: %v", err)
+				}
+
+				if !reflect.DeepEqual(value, retrieved) {
+					t.Errorf("Expected %v, got %v", value, retrieved)
+				}
+			},
+		)
+	}
+}
+
+func TestMemoryContextCancellation(t *testing.T) {
+	cache := memory.New()
+
+	for i := 0; i < 5000; i++ {
+		if err := cache.Set(context.Background(), fmt.Sprintf("key-%d", i), i, time.Minute, []string{"tag"}); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := cache.RemoveByTag(ctx, "tag"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	keys, err := cache.GetKeysByTag(context.Background(), "tag")
+	if err != nil {
+		t.Fatalf("GetKeysByTag failed: %v", err)
+	}
+	if len(keys) != 5000 {
+		t.Errorf("Expected 5000 keys to remain, got %d", len(keys))
+	}
+}
+
+func BenchmarkMemoryParallelGet(b *testing.B) {
+	ctx := context.Background()
+
+	configs := map[string]*memory.Config{
+		// LRU with a size limit moves every read item, so reads lock exclusively.
+		"LRU-Exclusive": {MaxSize: 10000},
+		// FIFO reads don't change anything, so they share a read lock.
+		"FIFO-Shared": {MaxSize: 10000, EvictionPolicy: memory.FIFO},
+	}
+
+	for name, config := range configs {
+		b.Run(
+			name, func(b *testing.B) {
+				cache := memory.NewWithConfig(config)
+				for i := 0; i < 1000; i++ {
+					if err := cache.Set(ctx, fmt.Sprintf("key-%d", i), i, time.Minute, nil); err != nil {
+						b.Fatalf("Set failed: %v", err)
+					}
+				}
+
+				b.SetParallelism(16)
+				b.ResetTimer()
+
+				b.RunParallel(
+					func(pb *testing.PB) {
+						var value int
+						for i := 0; pb.Next(); i++ {
+							if err := cache.Get(ctx, fmt.Sprintf("key-%d", i%1000), &value); err != nil {
+								b.Fatalf("Get failed: %v", err)
+							}
+						}
+					},
+				)
+			},
+		)
+	}
+}
+
+func BenchmarkMemoryRemoveByTag(b *testing.B) {
+	ctx := context.Background()
+
+	b.Run(
+		"RemoveByTag-Large", func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				b.StopTimer()
+				cache := memory.New()
+				for i := 0; i < 100000; i++ {
+					tag := "odd"
+					if i%2 == 0 {
+						tag = "even"
+					}
+					if err := cache.Set(ctx, fmt.Sprintf("key-%d", i), i, time.Minute, []string{tag}); err != nil {
+						b.Fatalf("Set failed: %v", err)
+					}
+				}
+				b.StartTimer()
+
+				if err := cache.RemoveByTag(ctx, "even"); err != nil {
+					b.Fatalf("RemoveByTag failed: %v", err)
+				}
+			}
+		},
+	)
+}
+
+func TestMemoryBackendInfo(t *testing.T) {
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+
+	infos, err := manager.AllBackendInfo(context.Background())
+	if err != nil {
+		t.Fatalf("AllBackendInfo failed: %v", err)
+	}
+
+	if infos["memory"].Type != "memory" {
+		t.Errorf("Expected backend type memory, got %q", infos["memory"].Type)
+	}
+}
+
+func TestMemoryTagCleanup(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.New()
+
+	_ = cache.Set(ctx, "a", "value", time.Minute, []string{"tag"})
+	_ = cache.Set(ctx, "b", "value", time.Minute, []string{"tag", "other"})
+
+	// Re-setting a key replaces its tags.
+	_ = cache.Set(ctx, "b", "value", time.Minute, []string{"other"})
+
+	keys, err := cache.GetKeysByTag(ctx, "tag")
+	if err != nil || len(keys) != 1 || keys[0] != "a" {
+		t.Fatalf("Expected [a], got %v (err=%v)", keys, err)
+	}
+
+	if err := cache.Remove(ctx, "a"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+
+	count, err := cache.(cachemar.TagCounter).CountByTag(ctx, "tag")
+	if err != nil || count != 0 {
+		t.Errorf("Expected empty tag, got count=%d, err=%v", count, err)
+	}
+}
+
+func TestMemoryStream(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.New().(cachemar.StreamCacher)
+
+	content := strings.Repeat("log line\n", 1000)
+	if err := cache.SetStream(ctx, "log", strings.NewReader(content), time.Minute, []string{"logs"}); err != nil {
+		t.Fatalf("SetStream failed: %v", err)
+	}
+
+	r, err := cache.GetStream(ctx, "log")
+	if err != nil {
+		t.Fatalf("GetStream failed: %v", err)
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Reading stream failed: %v", err)
+	}
+
+	if string(data) != content {
+		t.Errorf("Expected %d bytes, got %d", len(content), len(data))
+	}
+
+	if _, err := cache.GetStream(ctx, "missing"); !errors.Is(err, cachemar.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestMemoryRawValues(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.New()
+	raw := cache.(cachemar.RawCacher)
+
+	data := []byte{0x89, 'P', 'N', 'G'}
+	if err := raw.SetRaw(ctx, "image", data, time.Minute, []string{"images"}); err != nil {
+		t.Fatalf("SetRaw failed: %v", err)
+	}
+	data[0] = 0
+
+	value, err := raw.GetRaw(ctx, "image")
+	if err != nil || !reflect.DeepEqual(value, []byte{0x89, 'P', 'N', 'G'}) {
+		t.Fatalf("Expected stored bytes, got %v (err=%v)", value, err)
+	}
+
+	var str string
+	if err := cache.Get(ctx, "image", &str); !errors.Is(err, cachemar.ErrRawValue) {
+		t.Errorf("Expected ErrRawValue, got %v", err)
+	}
+
+	keys, err := cache.GetKeysByTag(ctx, "images")
+	if err != nil || len(keys) != 1 {
+		t.Errorf("Expected tagged raw value, got %v (err=%v)", keys, err)
+	}
+}
+
+func TestMemoryIncrementFloat(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.New()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.IncrementFloat(ctx, "sum", 0.25); err != nil {
+				t.Errorf("IncrementFloat failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	value, err := cache.DecrementFloat(ctx, "sum", 0.5)
+	if err != nil || value != 24.5 {
+		t.Fatalf("Expected 24.5, got %v (err=%v)", value, err)
+	}
+
+	var stored float64
+	if err := cache.Get(ctx, "sum", &stored); err != nil || stored != 24.5 {
+		t.Errorf("Expected stored 24.5, got %v (err=%v)", stored, err)
+	}
+
+	_ = cache.Set(ctx, "text", "abc", time.Minute, nil)
+	if _, err := cache.IncrementFloat(ctx, "text", 1); !errors.Is(err, cachemar.ErrInvalidType) {
+		t.Errorf("Expected ErrInvalidType, got %v", err)
+	}
+}
+
+func TestMemoryIncrBy(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.New()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.IncrBy(ctx, "counter", 5); err != nil {
+				t.Errorf("IncrBy failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	value, err := cache.DecrBy(ctx, "counter", 20)
+	if err != nil || value != 480 {
+		t.Fatalf("Expected 480, got %v (err=%v)", value, err)
+	}
+
+	var stored int64
+	if err := cache.Get(ctx, "counter", &stored); err != nil || stored != 480 {
+		t.Errorf("Expected stored 480, got %v (err=%v)", stored, err)
+	}
+
+	_ = cache.Set(ctx, "text", "abc", time.Minute, nil)
+	if _, err := cache.IncrBy(ctx, "text", 1); !errors.Is(err, cachemar.ErrInvalidType) {
+		t.Errorf("Expected ErrInvalidType, got %v", err)
+	}
+}
+
+func TestMemoryMaxSize(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.NewWithConfig(&memory.Config{MaxSize: 2})
+
+	_ = cache.Set(ctx, "a", "value", time.Minute, []string{"tag"})
+	_ = cache.Set(ctx, "b", "value", time.Minute, []string{"tag"})
+
+	var value string
+	_ = cache.Get(ctx, "a", &value)
+	_ = cache.Set(ctx, "c", "value", time.Minute, nil)
+
+	for key, expected := range map[string]bool{"a": true, "b": false, "c": true} {
+		if exists, _ := cache.Exists(ctx, key); exists != expected {
+			t.Errorf("Expected %s to exist=%v", key, expected)
+		}
+	}
+
+	keys, _ := cache.GetKeysByTag(ctx, "tag")
+	if len(keys) != 1 || keys[0] != "a" {
+		t.Errorf("Expected evicted key to leave its tag, got %v", keys)
+	}
+}
+
+func TestMemoryEvictionPolicyLFU(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.NewWithConfig(&memory.Config{MaxSize: 3, EvictionPolicy: memory.LFU})
+
+	_ = cache.Set(ctx, "a", "value", time.Minute, nil)
+	_ = cache.Set(ctx, "b", "value", time.Minute, nil)
+	_ = cache.Set(ctx, "counter", 0, time.Minute, nil)
+
+	var value string
+	_ = cache.Get(ctx, "a", &value)
+	_ = cache.Get(ctx, "a", &value)
+	_ = cache.Set(ctx, "b", "updated", time.Minute, nil)
+	_ = cache.Increment(ctx, "counter")
+
+	// "b" and "counter" were used equally often, "b" first, so it goes first.
+	_ = cache.Set(ctx, "d", "value", time.Minute, nil)
+	_ = cache.Get(ctx, "d", &value)
+	_ = cache.Get(ctx, "d", &value)
+	_ = cache.Set(ctx, "e", "value", time.Minute, nil)
+
+	for key, expected := range map[string]bool{"a": true, "b": false, "counter": false, "d": true, "e": true} {
+		if exists, _ := cache.Exists(ctx, key); exists != expected {
+			t.Errorf("Expected %s to exist=%v", key, expected)
+		}
+	}
+}
+
+func TestMemoryEvictionPolicyFIFO(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.NewWithConfig(&memory.Config{MaxSize: 2, EvictionPolicy: memory.FIFO})
+
+	_ = cache.Set(ctx, "a", "value", time.Minute, nil)
+	_ = cache.Set(ctx, "b", "value", time.Minute, nil)
+
+	var value string
+	_ = cache.Get(ctx, "a", &value)
+	_ = cache.Set(ctx, "c", "value", time.Minute, nil)
+
+	for key, expected := range map[string]bool{"a": false, "b": true, "c": true} {
+		if exists, _ := cache.Exists(ctx, key); exists != expected {
+			t.Errorf("Expected %s to exist=%v", key, expected)
+		}
+	}
+}
+
+func TestMemoryStats(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.NewWithConfig(&memory.Config{MaxSize: 2})
+	statter, ok := cache.(memory.Statter)
+	if !ok {
+		t.Fatal("Expected the memory cache to implement memory.Statter")
+	}
+
+	_ = cache.Set(ctx, "a", "value", time.Minute, nil)
+	_ = cache.Set(ctx, "b", "value", time.Minute, nil)
+	_ = cache.Set(ctx, "c", "value", time.Minute, nil)
+	_ = cache.Set(ctx, "expired", "value", -time.Second, nil)
+
+	var value string
+	_ = cache.Get(ctx, "c", &value)
+	_ = cache.Get(ctx, "a", &value)
+	_ = cache.Get(ctx, "expired", &value)
+
+	stats := statter.Stats()
+	expected := memory.Stats{Hits: 1, Misses: 2, Evictions: 2, Expirations: 1, CurrentSize: 1, CurrentBytes: stats.CurrentBytes}
+	if stats != expected {
+		t.Errorf("Expected %+v, got %+v", expected, stats)
+	}
+	if stats.CurrentBytes <= 0 {
+		t.Errorf("Expected the bytes of the remaining item, got %d", stats.CurrentBytes)
+	}
+}
+
+func TestMemoryGetWithTTL(t *testing.T) {
+	ctx := context.Background()
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+
+	_ = manager.Set(ctx, "key", "value", time.Minute, nil)
+
+	var value string
+	ttl, err := manager.GetWithTTL(ctx, "key", &value)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if value != "value" {
+		t.Errorf("Expected value, got %q", value)
+	}
+	if ttl <= 59*time.Second || ttl > time.Minute {
+		t.Errorf("Expected about a minute left, got %v", ttl)
+	}
+
+	if _, err := manager.GetWithTTL(ctx, "missing", &value); !errors.Is(err, cachemar.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestMemorySharedReads(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.NewWithConfig(&memory.Config{MaxSize: 100, EvictionPolicy: memory.FIFO})
+	_ = cache.Set(ctx, "key", "value", time.Minute, []string{"tag"})
+	_ = cache.Set(ctx, "expired", "value", -time.Second, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var value string
+			for j := 0; j < 100; j++ {
+				if err := cache.Get(ctx, "key", &value); err != nil || value != "value" {
+					t.Errorf("Expected value, got %q, %v", value, err)
+				}
+				if err := cache.Get(ctx, "expired", &value); !errors.Is(err, cachemar.ErrNotFound) {
+					t.Errorf("Expected ErrNotFound, got %v", err)
+				}
+				_, _ = cache.GetKeysByTag(ctx, "tag")
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := cache.(memory.Statter).Stats()
+	if stats.Hits != 800 || stats.Misses != 800 {
+		t.Errorf("Expected 800 hits and misses, got %+v", stats)
+	}
+}
+
+func TestMemoryOnEvictOnExpire(t *testing.T) {
+	ctx := context.Background()
+
+	var cache cachemar.Cacher
+	evicted := make(map[string]string)
+	expired := make(map[string]string)
+	cache = memory.NewWithConfig(
+		&memory.Config{
+			MaxSize: 2,
+			OnEvict: func(key string, value []byte) {
+				// The lock is released, so the cache may be used.
+				if exists, _ := cache.Exists(ctx, key); exists {
+					t.Errorf("Expected %s to be gone when evicted", key)
+				}
+				evicted[key] = string(value)
+			},
+			OnExpire: func(key string, value []byte) {
+				expired[key] = string(value)
+			},
+		},
+	)
+	raw := cache.(cachemar.RawCacher)
+
+	_ = raw.SetRaw(ctx, "a", []byte("first"), time.Minute, nil)
+	_ = raw.SetRaw(ctx, "b", []byte("second"), -time.Second, nil)
+	_ = raw.SetRaw(ctx, "c", []byte("third"), time.Minute, nil)
+	_ = raw.SetRaw(ctx, "d", []byte("fourth"), time.Minute, nil)
+
+	// Both a and b were evicted, but b had already expired.
+	if len(evicted) != 1 || evicted["a"] != "first" {
+		t.Errorf("Expected a to be evicted, got %v", evicted)
+	}
+	if len(expired) != 1 || expired["b"] != "second" {
+		t.Errorf("Expected b to expire, got %v", expired)
+	}
+
+	_ = raw.SetRaw(ctx, "c", []byte("third"), -time.Second, nil)
+	if _, err := raw.GetRaw(ctx, "c"); !errors.Is(err, cachemar.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if expired["c"] != "third" {
+		t.Errorf("Expected c to expire on read, got %v", expired)
+	}
+}
+
+func TestMemoryMaxBytes(t *testing.T) {
+	const mb = 1 << 20
+
+	ctx := context.Background()
+	cache := memory.NewWithConfig(&memory.Config{MaxBytes: 50 * mb})
+	raw := cache.(cachemar.RawCacher)
+	usage := cache.(interface{ BytesUsed() int64 })
+
+	for i := 0; i < 4; i++ {
+		_ = raw.SetRaw(ctx, fmt.Sprintf("small%d", i), make([]byte, 10*mb), time.Minute, nil)
+	}
+	if usage.BytesUsed() != 40*mb {
+		t.Fatalf("Expected 40 MB used, got %d", usage.BytesUsed())
+	}
+
+	// Touch the oldest item so it is not the first one to go.
+	_, _ = raw.GetRaw(ctx, "small0")
+
+	_ = raw.SetRaw(ctx, "medium", make([]byte, 20*mb), time.Minute, nil)
+	if usage.BytesUsed() > 50*mb {
+		t.Errorf("Expected at most 50 MB used, got %d", usage.BytesUsed())
+	}
+	if exists, _ := cache.Exists(ctx, "small1"); exists {
+		t.Errorf("Expected the least recently used item to be evicted")
+	}
+	if exists, _ := cache.Exists(ctx, "small0"); !exists {
+		t.Errorf("Expected the recently used item to be kept")
+	}
+
+	_ = raw.SetRaw(ctx, "large", make([]byte, 100*mb), time.Minute, nil)
+	if usage.BytesUsed() > 50*mb {
+		t.Errorf("Expected at most 50 MB used, got %d", usage.BytesUsed())
+	}
+
+	_ = cache.Flush(ctx)
+	if usage.BytesUsed() != 0 {
+		t.Errorf("Expected no bytes used after Flush, got %d", usage.BytesUsed())
+	}
+}
+
+func TestMemoryMaxSizeAndMaxBytes(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.NewWithConfig(&memory.Config{MaxSize: 3, MaxBytes: 100})
+	raw := cache.(cachemar.RawCacher)
+
+	// The byte limit is hit first.
+	for i := 0; i < 3; i++ {
+		_ = raw.SetRaw(ctx, fmt.Sprintf("bytes%d", i), make([]byte, 40), time.Minute, nil)
+	}
+	if exists, _ := cache.Exists(ctx, "bytes0"); exists {
+		t.Error("Expected MaxBytes to evict the least recently used item")
+	}
+
+	// The item limit is hit first.
+	for i := 0; i < 4; i++ {
+		_ = raw.SetRaw(ctx, fmt.Sprintf("items%d", i), make([]byte, 1), time.Minute, nil)
+	}
+	for key, exists := range map[string]bool{"items0": false, "items1": true, "items3": true} {
+		if found, _ := cache.Exists(ctx, key); found != exists {
+			t.Errorf("Expected %s to exist=%v under MaxSize", key, exists)
+		}
+	}
+}
+
+func TestMemoryReservations(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.NewWithConfig(
+		&memory.Config{
+			Reservations: []memory.Reservation{
+				{Prefix: "users:", MaxItems: 2},
+				{Prefix: "products:", MaxItems: 10},
+			},
+		},
+	)
+
+	_ = cache.Set(ctx, "products:1", "value", time.Minute, nil)
+	_ = cache.Set(ctx, "users:1", "value", time.Minute, nil)
+	_ = cache.Set(ctx, "users:2", "value", time.Minute, nil)
+	_ = cache.Set(ctx, "other", "value", time.Minute, nil)
+
+	var value string
+	_ = cache.Get(ctx, "users:1", &value)
+	_ = cache.Set(ctx, "users:3", "value", time.Minute, nil)
+
+	expected := map[string]bool{
+		"users:1":    true,
+		"users:2":    false,
+		"users:3":    true,
+		"products:1": true,
+		"other":      true,
+	}
+	for key, exists := range expected {
+		if found, _ := cache.Exists(ctx, key); found != exists {
+			t.Errorf("Expected %s to exist=%v", key, exists)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected duplicate reservation prefixes to be rejected")
+		}
+	}()
+	memory.NewWithConfig(&memory.Config{Reservations: []memory.Reservation{{Prefix: "a"}, {Prefix: "a"}}})
+}
+
+func TestMemoryCoalesceWrites(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.NewWithConfig(&memory.Config{CoalesceWrites: true, CoalesceWindow: 10 * time.Millisecond})
+	defer cache.Close()
+
+	if err := cache.Set(ctx, "key", "value", time.Minute, []string{"tag"}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		var value string
+		if err := cache.Get(ctx, "key", &value); err == nil {
+			if value != "value" {
+				t.Errorf("Expected value, got %s", value)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Coalesced write was never applied")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// A remove right after a buffered set must not be undone by the next flush.
+	_ = cache.Set(ctx, "removed", "value", time.Minute, nil)
+	if err := cache.Remove(ctx, "removed"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if found, _ := cache.Exists(ctx, "removed"); found {
+		t.Error("Expected removed key to stay removed")
+	}
+
+	// Increment sees the buffered value.
+	_ = cache.Set(ctx, "counter", 1, time.Minute, nil)
+	if err := cache.Increment(ctx, "counter"); err != nil {
+		t.Fatalf("Increment failed: %v", err)
+	}
+}
+
+func TestMemoryCoalesceBufferFull(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.NewWithConfig(&memory.Config{CoalesceWrites: true, CoalesceWindow: time.Hour, CoalesceBufferSize: 2})
+	defer cache.Close()
+
+	_ = cache.Set(ctx, "first", "value", time.Minute, nil)
+	if found, _ := cache.Exists(ctx, "first"); found {
+		t.Error("Expected first write to be buffered")
+	}
+
+	_ = cache.Set(ctx, "second", "value", time.Minute, nil)
+	for _, key := range []string{"first", "second"} {
+		if found, _ := cache.Exists(ctx, key); !found {
+			t.Errorf("Expected %s to be flushed when the buffer filled up", key)
+		}
+	}
+
+	_ = cache.Set(ctx, "third", "value", time.Minute, nil)
+	_ = cache.Close()
+	if found, _ := cache.Exists(ctx, "third"); !found {
+		t.Error("Expected Close to apply the buffered writes")
+	}
+}
+
+func TestMemoryPrefetchHint(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.NewWithConfig(&memory.Config{MaxSize: 3, PrefetchDecodeAsync: true})
+
+	prefetcher, ok := cache.(cachemar.Prefetcher)
+	if !ok {
+		t.Fatal("Expected memory driver to implement Prefetcher")
+	}
+
+	_ = cache.Set(ctx, "a", "value-a", time.Minute, nil)
+	_ = cache.Set(ctx, "b", "value-b", time.Minute, nil)
+	_ = cache.Set(ctx, "c", "value-c", time.Minute, nil)
+
+	// "a" is the least recently used key until it is hinted.
+	prefetcher.PrefetchHint(ctx, "a", "missing")
+	_ = cache.Set(ctx, "d", "value-d", time.Minute, nil)
+
+	if found, _ := cache.Exists(ctx, "a"); !found {
+		t.Error("Expected hinted key to survive eviction")
+	}
+	if found, _ := cache.Exists(ctx, "b"); found {
+		t.Error("Expected least recently used key to be evicted")
+	}
+
+	// A replaced value must not be served from the prefetch buffer.
+	prefetcher.PrefetchHint(ctx, "c")
+	_ = cache.Set(ctx, "c", "replaced", time.Minute, nil)
+	time.Sleep(10 * time.Millisecond)
+
+	var value string
+	if err := cache.Get(ctx, "c", &value); err != nil || value != "replaced" {
+		t.Errorf("Expected replaced, got %q (%v)", value, err)
+	}
+
+	prefetcher.PrefetchHint(ctx, "a")
+	time.Sleep(10 * time.Millisecond)
+	if err := cache.Get(ctx, "a", &value); err != nil || value != "value-a" {
+		t.Errorf("Expected value-a, got %q (%v)", value, err)
+	}
+}
+
+func TestMemoryPrefetchHintIncrement(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.NewWithConfig(&memory.Config{PrefetchDecodeAsync: true})
+	prefetcher := cache.(cachemar.Prefetcher)
+
+	_ = cache.Set(ctx, "counter", 1, time.Minute, nil)
+	prefetcher.PrefetchHint(ctx, "counter")
+	time.Sleep(10 * time.Millisecond)
+
+	// An increment keeps SetAt, so it must drop the prefetched value itself.
+	if err := cache.Increment(ctx, "counter"); err != nil {
+		t.Fatalf("Increment failed: %v", err)
+	}
+
+	var value int
+	if err := cache.Get(ctx, "counter", &value); err != nil || value != 2 {
+		t.Errorf("Expected 2, got %d (%v)", value, err)
+	}
+}
+
+func TestMemoryMaxConcurrentOps(t *testing.T) {
+	ctx := context.Background()
+	const maxOps = 3
+	const callers = 10
+
+	cache := memory.NewWithConfig(&memory.Config{MaxConcurrentOps: maxOps})
+	_ = cache.Set(ctx, "key", "value", time.Minute, nil)
+
+	// A transaction holds one slot and the lock until released, so the callers that get a slot wait inside.
+	release := make(chan struct{})
+	held := make(chan struct{})
+	go func() {
+		_ = cache.(cachemar.Transactor).Transaction(
+			ctx, func(ctx context.Context) error {
+				close(held)
+				<-release
+				return nil
+			},
+		)
+	}()
+	<-held
+
+	results := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			var value string
+			results <- cache.Get(ctx, "key", &value)
+		}()
+	}
+
+	// Callers without a slot fail right away; the others wait for the lock.
+	for i := 0; i < callers-(maxOps-1); i++ {
+		select {
+		case err := <-results:
+			if !errors.Is(err, cachemar.ErrTooManyRequests) {
+				t.Fatalf("Expected ErrTooManyRequests, got %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected overflowing callers to be rejected")
+		}
+	}
+
+	close(release)
+	for i := 0; i < maxOps-1; i++ {
+		if err := <-results; err != nil {
+			t.Errorf("Expected callers with a slot to succeed, got %v", err)
+		}
+	}
+}
+
+func TestMemoryWaitForSlot(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.NewWithConfig(&memory.Config{MaxConcurrentOps: 1, WaitForSlot: 500 * time.Millisecond})
+
+	release := make(chan struct{})
+	held := make(chan struct{})
+	go func() {
+		_ = cache.(cachemar.Transactor).Transaction(
+			ctx, func(ctx context.Context) error {
+				close(held)
+				<-release
+				return nil
+			},
+		)
+	}()
+	<-held
+
+	time.AfterFunc(20*time.Millisecond, func() { close(release) })
+	if err := cache.Set(ctx, "key", "value", time.Minute, nil); err != nil {
+		t.Errorf("Expected Set to get a slot once it was freed, got %v", err)
+	}
+}
+
+func TestMemorySlidingTTL(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.NewWithConfig(&memory.Config{SlidingTTL: true})
+
+	_ = cache.Set(ctx, "session", "value", 100*time.Millisecond, nil)
+
+	var value string
+	for i := 0; i < 3; i++ {
+		time.Sleep(60 * time.Millisecond)
+		if err := cache.Get(ctx, "session", &value); err != nil {
+			t.Fatalf("Expected read %d to extend the expiry, got %v", i, err)
+		}
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	if err := cache.Get(ctx, "session", &value); !errors.Is(err, cachemar.ErrNotFound) {
+		t.Errorf("Expected inactive item to expire, got %v", err)
+	}
+
+	fixed := memory.New()
+	_ = fixed.Set(ctx, "session", "value", 100*time.Millisecond, nil)
+	time.Sleep(60 * time.Millisecond)
+	_ = fixed.Get(ctx, "session", &value)
+	time.Sleep(60 * time.Millisecond)
+	if err := fixed.Get(ctx, "session", &value); !errors.Is(err, cachemar.ErrNotFound) {
+		t.Errorf("Expected fixed expiry without sliding TTL, got %v", err)
+	}
+}
+
+func TestMemoryCleanupInterval(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.NewWithConfig(&memory.Config{CleanupInterval: 10 * time.Millisecond})
+	defer cache.Close()
+
+	usage := cache.(interface{ BytesUsed() int64 })
+
+	_ = cache.Set(ctx, "expiring", "value", 20*time.Millisecond, nil)
+	_ = cache.Set(ctx, "kept", "value", time.Minute, nil)
+	kept := usage.BytesUsed() / 2
+
+	deadline := time.Now().Add(time.Second)
+	for usage.BytesUsed() != kept {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the expired item to be cleaned up, %d bytes used", usage.BytesUsed())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if found, _ := cache.Exists(ctx, "kept"); !found {
+		t.Error("Expected unexpired item to be kept")
+	}
+
+	if err := cache.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/contrib/metrics"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func TestMetricsCacher(t *testing.T) {
+	ctx := context.Background()
+	reg := prometheus.NewRegistry()
+
+	cache := metrics.NewMetricsCacher(memory.New(), reg, "memory")
+	require.NoError(t, cache.Set(ctx, "key", "value", time.Minute, nil))
+
+	var value string
+	require.NoError(t, cache.Get(ctx, "key", &value))
+	require.NoError(t, cache.Get(ctx, "key", &value))
+	require.ErrorIs(t, cache.Get(ctx, "missing", &value), cachemar.ErrNotFound)
+	require.Error(t, cache.Increment(ctx, "key"))
+
+	expected := `
+# HELP cachemar_hits_total Number of cache reads that found the key.
+# TYPE cachemar_hits_total counter
+cachemar_hits_total{driver="memory"} 2
+# HELP cachemar_misses_total Number of cache reads that did not find the key.
+# TYPE cachemar_misses_total counter
+cachemar_misses_total{driver="memory"} 1
+# HELP cachemar_operations_total Number of cache operations.
+# TYPE cachemar_operations_total counter
+cachemar_operations_total{driver="memory",operation="Get",status="ok"} 3
+cachemar_operations_total{driver="memory",operation="Increment",status="error"} 1
+cachemar_operations_total{driver="memory",operation="Set",status="ok"} 1
+`
+	assert.NoError(
+		t, testutil.GatherAndCompare(
+			reg, strings.NewReader(expected),
+			"cachemar_hits_total", "cachemar_misses_total", "cachemar_operations_total",
+		),
+	)
+	assert.Equal(t, 3, testutil.CollectAndCount(reg, "cachemar_operation_duration_seconds"))
+}
+
+func TestMetricsCacherSharedRegistry(t *testing.T) {
+	ctx := context.Background()
+	reg := prometheus.NewRegistry()
+
+	first := metrics.NewMetricsCacher(memory.New(), reg, "first")
+	second := metrics.NewMetricsCacher(memory.New(), reg, "second")
+
+	var value string
+	_ = first.Get(ctx, "missing", &value)
+	_ = second.Get(ctx, "missing", &value)
+
+	assert.Equal(t, 2, testutil.CollectAndCount(reg, "cachemar_misses_total"))
+}
+
+func TestMetricsCacherOptionalInterfaces(t *testing.T) {
+	ctx := context.Background()
+	reg := prometheus.NewRegistry()
+
+	manager := cachemar.NewWithOptions(cachemar.WithTagCircuitBreaker(1))
+	manager.Register("memory", memory.New())
+	manager.WrapAll(
+		func(c cachemar.Cacher) cachemar.Cacher {
+			return metrics.NewMetricsCacher(c, reg, "memory")
+		},
+	)
+
+	require.NoError(t, manager.Set(ctx, "key", "value", time.Minute, []string{"tag"}))
+	require.NoError(t, manager.Set(ctx, "other", "value", time.Minute, []string{"tag"}))
+	assert.ErrorIs(t, manager.RemoveByTag(ctx, "tag"), cachemar.ErrTagTooLarge)
+
+	_, ok := cachemar.As[cachemar.Transactor](manager.Current())
+	assert.True(t, ok)
+}
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func TestMGet(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.NewWithOptions(cachemar.WithGlobalKeyPrefix("app"))
+	manager.Register("memory", memory.New())
+
+	require.NoError(t, manager.Set(ctx, "a", "first", time.Minute, nil))
+	require.NoError(t, manager.Set(ctx, "c", 3, time.Minute, nil))
+
+	var a, b string
+	var c int
+	err := manager.MGet(ctx, []string{"a", "b", "c"}, []interface{}{&a, &b, &c})
+
+	var errs cachemar.MultiError
+	require.True(t, errors.As(err, &errs))
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], cachemar.ErrNotFound)
+	assert.NoError(t, errs[2])
+	assert.Equal(t, "first", a)
+	assert.Equal(t, 3, c)
+
+	assert.NoError(t, manager.MGet(ctx, []string{"a"}, []interface{}{&a}))
+	assert.Error(t, manager.MGet(ctx, []string{"a", "c"}, []interface{}{&a}))
+}
+
+func TestChainMGet(t *testing.T) {
+	ctx := context.Background()
+
+	l1, l2 := memory.New(), memory.New()
+	manager := cachemar.New()
+	manager.Register("l1", l1)
+	manager.Register("l2", l2)
+
+	chain := manager.Chain()
+	chain.AddToChain("l1")
+	chain.AddToChain("l2")
+
+	require.NoError(t, l1.Set(ctx, "a", "l1", time.Minute, nil))
+	require.NoError(t, l2.Set(ctx, "a", "l2", time.Minute, nil))
+	require.NoError(t, l2.Set(ctx, "b", "l2", time.Minute, nil))
+
+	var a, b, c string
+	err := chain.MGet(ctx, []string{"a", "b", "c"}, []interface{}{&a, &b, &c})
+
+	var errs cachemar.MultiError
+	require.True(t, errors.As(err, &errs))
+	assert.Equal(t, "l1", a)
+	assert.Equal(t, "l2", b)
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	assert.ErrorIs(t, errs[2], cachemar.ErrNotFound)
+}
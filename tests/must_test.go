@@ -0,0 +1,24 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stremovskyy/cachemar/drivers/memory"
+	"github.com/stremovskyy/cachemar/must"
+)
+
+func TestMust(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.New()
+
+	must.MustSet(ctx, cache, "key", "value", time.Minute, nil)
+	assert.Equal(t, "value", must.MustGet[string](ctx, cache, "key"))
+
+	assert.Panics(t, func() { must.MustGet[string](ctx, cache, "missing") })
+	assert.Equal(t, "default", must.RecoverGet(ctx, cache, "missing", "default"))
+	assert.Equal(t, "value", must.RecoverGet(ctx, cache, "key", "default"))
+}
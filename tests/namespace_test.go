@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func TestNamespace(t *testing.T) {
+	ctx := context.Background()
+
+	cache := memory.New()
+	manager := cachemar.New()
+	manager.Register("memory", cache)
+
+	users := manager.Namespace("users")
+	orders := manager.Namespace("orders")
+
+	require.NoError(t, users.Set(ctx, "1", "alice", time.Minute, []string{"all"}))
+	require.NoError(t, orders.Set(ctx, "1", "order", time.Minute, []string{"all"}))
+	assert.Equal(t, "users:1", users.EffectiveKey("1"))
+
+	exists, err := cache.Exists(ctx, "users:1")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	var value string
+	require.NoError(t, users.Get(ctx, "1", &value))
+	assert.Equal(t, "alice", value)
+	require.NoError(t, orders.Get(ctx, "1", &value))
+	assert.Equal(t, "order", value)
+
+	keys, err := users.GetKeysByTag(ctx, "all")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1"}, keys)
+
+	require.NoError(t, users.RemoveByTag(ctx, "all"))
+	assert.True(t, cachemar.IsNotFound(users.Get(ctx, "1", &value)))
+	require.NoError(t, orders.Get(ctx, "1", &value))
+	assert.Equal(t, "order", value)
+}
+
+func TestNamespaceNested(t *testing.T) {
+	ctx := context.Background()
+
+	cache := memory.New()
+	manager := cachemar.NewWithOptions(cachemar.WithGlobalKeyPrefix("staging"))
+	manager.Register("memory", cache)
+
+	sessions := manager.Namespace("users").Namespace("sessions")
+	require.NoError(t, sessions.Set(ctx, "key", "value", time.Minute, nil))
+	assert.Equal(t, "staging:users:sessions:key", sessions.EffectiveKey("key"))
+
+	exists, err := cache.Exists(ctx, "staging:users:sessions:key")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestNamespaceSharesParentState(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+	ns := manager.Namespace("ns")
+
+	other := memory.New()
+	ns.Register("other", other)
+	ns.SetCurrent("other")
+	assert.Same(t, other, manager.Current())
+
+	manager.SetReadOnly(true)
+	assert.True(t, ns.IsReadOnly())
+	assert.ErrorIs(t, ns.Set(ctx, "key", "value", time.Minute, nil), cachemar.ErrReadOnly)
+}
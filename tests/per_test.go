@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func TestGetPER(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+
+	loads := 0
+	loader := func(ctx context.Context) (interface{}, time.Duration, []string, error) {
+		loads++
+		time.Sleep(10 * time.Millisecond)
+		return loads, time.Minute, []string{"per"}, nil
+	}
+
+	var value int
+	assert.NoError(t, manager.GetPER(ctx, "key", &value, 0, loader))
+	assert.Equal(t, 1, value)
+
+	// A recomputation taking 10ms is far from the remaining minute.
+	assert.NoError(t, manager.GetPER(ctx, "key", &value, cachemar.DefaultPERBeta, loader))
+	assert.Equal(t, 1, value)
+	assert.Equal(t, 1, loads)
+
+	// A huge beta makes the value look about to expire.
+	assert.NoError(t, manager.GetPER(ctx, "key", &value, 1e6, loader))
+	assert.Equal(t, 2, value)
+
+	keys, err := manager.GetKeysByTag(ctx, "per")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"key"}, keys)
+}
+
+func TestGetPERReadOnly(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+
+	loads := 0
+	loader := func(ctx context.Context) (interface{}, time.Duration, []string, error) {
+		loads++
+		time.Sleep(10 * time.Millisecond)
+		return loads, time.Minute, nil, nil
+	}
+
+	var value int
+	assert.NoError(t, manager.GetPER(ctx, "key", &value, 0, loader))
+	manager.SetReadOnly(true)
+
+	// The value is due for recomputation, but it is served as is.
+	assert.NoError(t, manager.GetPER(ctx, "key", &value, 1e6, loader))
+	assert.Equal(t, 1, value)
+	assert.Equal(t, 1, loads)
+
+	assert.ErrorIs(t, manager.GetPER(ctx, "missing", &value, 0, loader), cachemar.ErrReadOnly)
+	assert.Equal(t, 1, loads)
+}
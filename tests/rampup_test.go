@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar/drivers/memory"
+	"github.com/stremovskyy/cachemar/rampup"
+)
+
+func TestRampUpDistribution(t *testing.T) {
+	ctx := context.Background()
+	old, new := memory.New(), memory.New()
+
+	require.NoError(t, old.Set(ctx, "key", "old", time.Minute, nil))
+	require.NoError(t, new.Set(ctx, "key", "new", time.Minute, nil))
+
+	var percentage atomic.Int32
+	percentage.Store(50)
+	cache := rampup.New(old, new, func() int { return int(percentage.Load()) })
+
+	const requests = 10000
+	fromNew := 0
+	for i := 0; i < requests; i++ {
+		var value string
+		require.NoError(t, cache.Get(ctx, "key", &value))
+		if value == "new" {
+			fromNew++
+		}
+	}
+	assert.InDelta(t, requests/2, fromNew, requests*0.05)
+
+	// The split follows the percentage on every request.
+	percentage.Store(0)
+	var value string
+	require.NoError(t, cache.Get(ctx, "key", &value))
+	assert.Equal(t, "old", value)
+
+	percentage.Store(100)
+	require.NoError(t, cache.Get(ctx, "key", &value))
+	assert.Equal(t, "new", value)
+}
+
+func TestRampUpWritesToBoth(t *testing.T) {
+	ctx := context.Background()
+	old, new := memory.New(), memory.New()
+	cache := rampup.New(old, new, func() int { return 0 })
+
+	require.NoError(t, cache.Set(ctx, "key", "value", time.Minute, []string{"tag"}))
+	for _, c := range []interface {
+		Exists(ctx context.Context, key string) (bool, error)
+	}{old, new} {
+		exists, err := c.Exists(ctx, "key")
+		require.NoError(t, err)
+		assert.True(t, exists)
+	}
+
+	require.NoError(t, cache.RemoveByTag(ctx, "tag"))
+	exists, _ := new.Exists(ctx, "key")
+	assert.False(t, exists)
+}
@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func TestReadOnlyManager(t *testing.T) {
+	ctx := context.Background()
+
+	cache := memory.New()
+	assert.NoError(t, cache.Set(ctx, "key", "value", time.Minute, []string{"tag"}))
+
+	manager := cachemar.NewWithOptions(cachemar.WithReadOnly())
+	manager.Register("memory", cache)
+	assert.True(t, manager.IsReadOnly())
+
+	assert.ErrorIs(t, manager.Set(ctx, "other", "value", time.Minute, nil), cachemar.ErrReadOnly)
+	assert.ErrorIs(t, manager.Remove(ctx, "key"), cachemar.ErrReadOnly)
+	assert.ErrorIs(t, manager.RemoveByTag(ctx, "tag"), cachemar.ErrReadOnly)
+	assert.ErrorIs(t, manager.RemoveByTags(ctx, []string{"tag"}), cachemar.ErrReadOnly)
+	assert.ErrorIs(t, manager.Increment(ctx, "counter"), cachemar.ErrReadOnly)
+	assert.ErrorIs(t, manager.Decrement(ctx, "counter"), cachemar.ErrReadOnly)
+	assert.ErrorIs(t, manager.Flush(ctx), cachemar.ErrReadOnly)
+	_, err := manager.IncrementFloat(ctx, "counter", 1)
+	assert.ErrorIs(t, err, cachemar.ErrReadOnly)
+	assert.ErrorIs(t, manager.Chain().Set(ctx, "other", "value", time.Minute, nil), cachemar.ErrReadOnly)
+
+	var value string
+	assert.NoError(t, manager.Get(ctx, "key", &value))
+	assert.Equal(t, "value", value)
+
+	exists, err := manager.Exists(ctx, "key")
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	keys, err := manager.GetKeysByTag(ctx, "tag")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"key"}, keys)
+	assert.NoError(t, manager.Ping())
+
+	exists, _ = cache.Exists(ctx, "other")
+	assert.False(t, exists)
+
+	manager.SetReadOnly(false)
+	assert.NoError(t, manager.Set(ctx, "other", "value", time.Minute, nil))
+}
@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func newReadThroughChain() (cachemar.Manager, cachemar.ChainedManager) {
+	manager := cachemar.New()
+	manager.Register("l1", memory.New())
+	manager.Register("l2", memory.New())
+	chain := manager.Chain()
+	chain.AddToChain("l1")
+	chain.AddToChain("l2")
+
+	return manager, chain
+}
+
+func TestChainReadThrough(t *testing.T) {
+	ctx := context.Background()
+
+	manager, chain := newReadThroughChain()
+	chain.SetReadThrough(true, 0)
+	require.NoError(t, manager.Use("l2").Set(ctx, "user", "alice", time.Hour, []string{"users"}))
+
+	var value string
+	require.NoError(t, chain.Get(ctx, "user", &value))
+	assert.Equal(t, "alice", value)
+
+	ttl, err := manager.Use("l1").GetWithTTL(ctx, "user", &value)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", value)
+	assert.Greater(t, ttl, 59*time.Minute)
+
+	keys, err := manager.Use("l1").GetKeysByTag(ctx, "users")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user"}, keys)
+}
+
+func TestChainReadThroughPromoteTTL(t *testing.T) {
+	ctx := context.Background()
+
+	manager, chain := newReadThroughChain()
+	chain.SetReadThrough(true, time.Minute)
+	require.NoError(t, manager.Use("l2").Set(ctx, "user", "alice", time.Hour, nil))
+
+	var value string
+	require.NoError(t, chain.Get(ctx, "user", &value))
+
+	ttl, err := manager.Use("l1").GetWithTTL(ctx, "user", &value)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, ttl, time.Minute)
+}
+
+func TestChainWithoutReadThrough(t *testing.T) {
+	ctx := context.Background()
+
+	manager, chain := newReadThroughChain()
+	require.NoError(t, manager.Use("l2").Set(ctx, "user", "alice", time.Hour, nil))
+
+	var value string
+	require.NoError(t, chain.Get(ctx, "user", &value))
+
+	exists, err := manager.Use("l1").Exists(ctx, "user")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stremovskyy/cachemar"
+	cachetesting "github.com/stremovskyy/cachemar/testing"
+)
+
+// flakyCacher fails the first `failures` pings.
+type flakyCacher struct {
+	*cachetesting.TestCacher
+	failures int32
+	pings    atomic.Int32
+}
+
+func (c *flakyCacher) Ping() error {
+	if c.pings.Add(1) <= c.failures {
+		return errors.New("connection refused")
+	}
+
+	return c.TestCacher.Ping()
+}
+
+func TestWaitReady(t *testing.T) {
+	cacher := &flakyCacher{TestCacher: cachetesting.NewTestCacher(), failures: 3}
+
+	err := cachemar.WaitReady(context.Background(), cacher, time.Second, 10*time.Millisecond, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(4), cacher.pings.Load())
+}
+
+func TestWaitReadyTimeout(t *testing.T) {
+	cacher := &flakyCacher{TestCacher: cachetesting.NewTestCacher(), failures: 1000}
+
+	start := time.Now()
+	err := cachemar.WaitReady(context.Background(), cacher, 50*time.Millisecond, 10*time.Millisecond, nil)
+	assert.EqualError(t, err, "connection refused")
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Greater(t, cacher.pings.Load(), int32(1))
+}
+
+func TestWaitReadyLogsAttempts(t *testing.T) {
+	cacher := &flakyCacher{TestCacher: cachetesting.NewTestCacher(), failures: 2}
+	logger := &recordingLogger{}
+
+	err := cachemar.WaitReady(context.Background(), cacher, time.Second, 10*time.Millisecond, logger)
+	assert.NoError(t, err)
+	assert.Equal(
+		t, []string{
+			"debug cachemar: ping failed, retrying [operation Ping attempt 1 interval 10ms error connection refused]",
+			"debug cachemar: ping failed, retrying [operation Ping attempt 2 interval 10ms error connection refused]",
+		}, logger.entries,
+	)
+}
@@ -0,0 +1,39 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+	"github.com/stremovskyy/cachemar/testutils"
+)
+
+func TestRecordingCacher(t *testing.T) {
+	ctx := context.Background()
+	cache := testutils.NewRecordingCacher(memory.New())
+
+	require.NoError(t, cache.Set(ctx, "key", "value", time.Minute, []string{"tag"}))
+
+	var value string
+	require.NoError(t, cache.Get(ctx, "key", &value))
+	assert.Equal(t, "value", value)
+	assert.True(t, cachemar.IsNotFound(cache.Get(ctx, "missing", &value)))
+
+	cache.AssertCalledWith(t, "Set", "key")
+	cache.AssertCalledWith(t, "Get", "missing")
+	cache.AssertCallCount(t, "Get", 2)
+
+	calls := cache.Calls()
+	require.Len(t, calls, 3)
+	assert.Equal(t, testutils.CacheCall{Op: "Set", Key: "key", Value: "value", TTL: time.Minute, Tags: []string{"tag"}}, calls[0])
+	assert.True(t, cachemar.IsNotFound(calls[2].Error))
+
+	cache.Reset()
+	assert.Empty(t, cache.Calls())
+	cache.AssertCallCount(t, "Get", 0)
+}
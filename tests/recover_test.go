@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+	"github.com/stremovskyy/cachemar/recover"
+)
+
+type panickingCacher struct {
+	cachemar.Cacher
+}
+
+func (p *panickingCacher) Get(ctx context.Context, key string, value interface{}) error {
+	panic("nil client")
+}
+
+func TestRecoverWrapper(t *testing.T) {
+	ctx := context.Background()
+	cache := recover.New(&panickingCacher{Cacher: memory.New()}, nil)
+
+	var value string
+	err := cache.Get(ctx, "key", &value)
+	assert.ErrorIs(t, err, recover.ErrPanic)
+	assert.Contains(t, err.Error(), "nil client")
+
+	assert.NoError(t, cache.Set(ctx, "key", "value", time.Minute, nil))
+
+	errCustom := errors.New("custom")
+	var recovered []string
+	cache = recover.New(
+		&panickingCacher{Cacher: memory.New()}, func(key string, op string, r interface{}) error {
+			recovered = append(recovered, op+":"+key)
+			return errCustom
+		},
+	)
+
+	assert.ErrorIs(t, cache.Get(ctx, "key", &value), errCustom)
+	assert.Equal(t, []string{"Get:key"}, recovered)
+}
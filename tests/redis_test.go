@@ -2,8 +2,18 @@ package tests
 
 import (
 	"context"
+	"errors"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/codecs"
+	"github.com/stremovskyy/cachemar/drivers/memory"
 	"github.com/stremovskyy/cachemar/drivers/redis"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -66,3 +76,678 @@ func TestRedisCacheService(t *testing.T) {
 	assert.NoError(t, err)
 	assert.False(t, exists)
 }
+
+func TestRedisGlobalKeyPrefix(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.NewWithOptions(cachemar.WithGlobalKeyPrefix("staging"))
+	manager.Register(
+		"redis", redis.New(
+			&redis.Options{
+				DSN:    "localhost:6379",
+				Prefix: "prefix",
+			},
+		),
+	)
+
+	err := manager.Set(ctx, "globalKey", "value", time.Minute, []string{"globalTag"})
+	assert.NoError(t, err)
+	assert.Equal(t, "prefix:staging:globalKey", manager.EffectiveKey("globalKey"))
+
+	keys, err := manager.GetKeysByTag(ctx, "globalTag")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"globalKey"}, keys)
+
+	client := goredis.NewClient(&goredis.Options{Addr: "localhost:6379"})
+	defer client.Close()
+
+	count, err := client.Exists(ctx, "prefix:staging:globalKey").Result()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	err = manager.Remove(ctx, "globalKey")
+	assert.NoError(t, err)
+}
+
+func TestRedisWarmChainByTag(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.NewWithOptions(cachemar.WithGlobalKeyPrefix("staging"))
+	manager.Register("memory", memory.New())
+	manager.Register("redis", redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "prefix"}))
+
+	chain := manager.Chain()
+	chain.AddToChain("memory")
+	chain.AddToChain("redis")
+
+	manager.SetCurrent("redis")
+	require.NoError(t, manager.Set(ctx, "warmByTag", "value", time.Minute, []string{"warmTag"}))
+	defer manager.Remove(ctx, "warmByTag")
+
+	require.NoError(t, chain.WarmChainByTag(ctx, "warmTag"))
+
+	manager.SetCurrent("memory")
+	var value string
+	require.NoError(t, manager.Get(ctx, "warmByTag", &value))
+	assert.Equal(t, "value", value)
+
+	keys, err := manager.GetKeysByTag(ctx, "warmTag")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"warmByTag"}, keys)
+}
+
+func TestRedisRemoveCleansUpTags(t *testing.T) {
+	ctx := context.Background()
+	cacheService := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "prefix"})
+
+	err := cacheService.Set(ctx, "cleanupKey", "value", time.Minute, []string{"cleanupTag"})
+	assert.NoError(t, err)
+
+	err = cacheService.Remove(ctx, "cleanupKey")
+	assert.NoError(t, err)
+
+	client := goredis.NewClient(&goredis.Options{Addr: "localhost:6379"})
+	defer client.Close()
+
+	count, err := client.Exists(ctx, "tag:cleanupTag", "keytags:prefix:cleanupKey").Result()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestRedisRemoveByTagCleansUpOtherTags(t *testing.T) {
+	ctx := context.Background()
+	cacheService := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "prefix"})
+
+	require.NoError(t, cacheService.Set(ctx, "shared", "value", time.Minute, []string{"removedTag", "otherTag"}))
+	require.NoError(t, cacheService.Set(ctx, "kept", "value", time.Minute, []string{"keptTag"}))
+	defer cacheService.Remove(ctx, "kept")
+
+	require.NoError(t, cacheService.RemoveByTag(ctx, "removedTag"))
+
+	keys, err := cacheService.GetKeysByTag(ctx, "otherTag")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	keys, err = cacheService.GetKeysByTag(ctx, "keptTag")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prefix:kept"}, keys)
+
+	client := goredis.NewClient(&goredis.Options{Addr: "localhost:6379"})
+	defer client.Close()
+
+	count, err := client.Exists(ctx, "tag:removedTag", "tag:otherTag", "keytags:prefix:shared").Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestRedisStream(t *testing.T) {
+	ctx := context.Background()
+	cacheService := redis.New(
+		&redis.Options{
+			DSN:                "localhost:6379",
+			Prefix:             "prefix",
+			CompressionEnabled: true,
+		},
+	).(cachemar.StreamCacher)
+
+	content := strings.Repeat("log line\n", 1000)
+	err := cacheService.SetStream(ctx, "streamKey", strings.NewReader(content), time.Minute, []string{"streamTag"})
+	require.NoError(t, err)
+
+	r, err := cacheService.GetStream(ctx, "streamKey")
+	require.NoError(t, err)
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+}
+
+func TestRedisRawValues(t *testing.T) {
+	ctx := context.Background()
+	cacheService := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "prefix"})
+	raw := cacheService.(cachemar.RawCacher)
+
+	err := raw.SetRaw(ctx, "rawKey", []byte{0x89, 'P', 'N', 'G'}, time.Minute, []string{"rawTag"})
+	assert.NoError(t, err)
+
+	value, err := raw.GetRaw(ctx, "rawKey")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x89, 'P', 'N', 'G'}, value)
+
+	err = cacheService.Set(ctx, "jsonKey", []byte("json"), time.Minute, nil)
+	assert.NoError(t, err)
+
+	err = cacheService.Get(ctx, "jsonKey", &value)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("json"), value)
+
+	var str string
+	err = cacheService.Get(ctx, "rawKey", &str)
+	assert.ErrorIs(t, err, cachemar.ErrRawValue)
+}
+
+func TestRedisIncrementFloat(t *testing.T) {
+	ctx := context.Background()
+	cacheService := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "prefix"})
+	_ = cacheService.Remove(ctx, "floatKey")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cacheService.IncrementFloat(ctx, "floatKey", 0.25)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	value, err := cacheService.DecrementFloat(ctx, "floatKey", 0.5)
+	assert.NoError(t, err)
+	assert.Equal(t, 24.5, value)
+
+	err = cacheService.Set(ctx, "floatText", "abc", time.Minute, nil)
+	assert.NoError(t, err)
+
+	_, err = cacheService.IncrementFloat(ctx, "floatText", 1)
+	assert.ErrorIs(t, err, cachemar.ErrInvalidType)
+}
+
+func TestRedisIncrBy(t *testing.T) {
+	ctx := context.Background()
+	cacheService := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "prefix"})
+	_ = cacheService.Remove(ctx, "intKey")
+
+	value, err := cacheService.IncrBy(ctx, "intKey", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), value)
+
+	value, err = cacheService.DecrBy(ctx, "intKey", 25)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-15), value)
+
+	err = cacheService.Set(ctx, "intText", "abc", time.Minute, nil)
+	assert.NoError(t, err)
+
+	_, err = cacheService.IncrBy(ctx, "intText", 1)
+	assert.ErrorIs(t, err, cachemar.ErrInvalidType)
+}
+
+func TestRedisInspect(t *testing.T) {
+	ctx := context.Background()
+	cacheService := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "prefix", CompressionEnabled: true})
+
+	err := cacheService.Set(ctx, "inspectKey", "value", time.Minute, []string{"inspectTag"})
+	assert.NoError(t, err)
+
+	result, err := cacheService.(cachemar.Inspector).Inspect(ctx, "inspectKey")
+	assert.NoError(t, err)
+	assert.Equal(t, "prefix:inspectKey", result.Key)
+	assert.Equal(t, "value", result.DecodedValue)
+	assert.Equal(t, []string{"inspectTag"}, result.Tags)
+	assert.True(t, result.Compressed)
+	assert.Greater(t, result.TTLRemaining, time.Duration(0))
+}
+
+func BenchmarkRedisSetGet(b *testing.B) {
+	ctx := context.Background()
+	cacheService := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "bench"})
+	if err := cacheService.Ping(); err != nil {
+		b.Skipf("Redis is not available: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		if err := cacheService.Set(ctx, "key", n, time.Minute, nil); err != nil {
+			b.Fatalf("Set failed: %v", err)
+		}
+
+		var value int
+		if err := cacheService.Get(ctx, "key", &value); err != nil {
+			b.Fatalf("Get failed: %v", err)
+		}
+	}
+}
+
+func BenchmarkRedisEffectiveKey(b *testing.B) {
+	keyer := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "bench"}).(cachemar.EffectiveKeyer)
+
+	b.ReportAllocs()
+
+	for n := 0; n < b.N; n++ {
+		_ = keyer.EffectiveKey("user:42:profile")
+	}
+}
+
+func TestRedisCacheGroup(t *testing.T) {
+	ctx := context.Background()
+	cacheService := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "prefix"})
+
+	_ = cacheService.Set(ctx, "group:counter", 1, time.Minute, nil)
+
+	err := cachemar.Group(cacheService).
+		Add(func(ctx context.Context) error {
+			return cacheService.Set(ctx, "group:key", "value", time.Minute, []string{"group"})
+		}).
+		Add(func(ctx context.Context) error { return cacheService.Increment(ctx, "group:counter") }).
+		Execute(ctx)
+	assert.NoError(t, err)
+
+	var counter int
+	assert.NoError(t, cacheService.Get(ctx, "group:counter", &counter))
+	assert.Equal(t, 2, counter)
+
+	err = cachemar.Group(cacheService).
+		Add(func(ctx context.Context) error { return cacheService.Remove(ctx, "group:key") }).
+		Add(func(ctx context.Context) error { return errors.New("boom") }).
+		Execute(ctx)
+	assert.Error(t, err)
+
+	exists, _ := cacheService.Exists(ctx, "group:key")
+	assert.True(t, exists, "discarded transaction must not remove the key")
+}
+
+func TestRedisFlushPrefix(t *testing.T) {
+	ctx := context.Background()
+
+	cache := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "flush"})
+	other := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "other"})
+
+	require.NoError(t, cache.Set(ctx, "key", "value", time.Minute, []string{"flush-tag"}))
+	require.NoError(t, other.Set(ctx, "key", "value", time.Minute, nil))
+
+	require.NoError(t, cache.Flush(ctx))
+
+	exists, err := cache.Exists(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	keys, err := cache.GetKeysByTag(ctx, "flush-tag")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	exists, err = other.Exists(ctx, "key")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.NoError(t, other.Remove(ctx, "key"))
+}
+
+func TestRedisGetWithTTL(t *testing.T) {
+	ctx := context.Background()
+	cache := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "ttl"})
+
+	require.NoError(t, cache.Set(ctx, "expiring", "value", time.Minute, nil))
+	require.NoError(t, cache.Set(ctx, "persistent", "value", 0, nil))
+
+	var value string
+	ttl, err := cache.GetWithTTL(ctx, "expiring", &value)
+	require.NoError(t, err)
+	assert.Equal(t, "value", value)
+	assert.InDelta(t, time.Minute, ttl, float64(2*time.Second))
+
+	ttl, err = cache.GetWithTTL(ctx, "persistent", &value)
+	require.NoError(t, err)
+	assert.Equal(t, cachemar.NoExpiry, ttl)
+
+	_, err = cache.GetWithTTL(ctx, "missing", &value)
+	assert.ErrorIs(t, err, cachemar.ErrNotFound)
+}
+
+func TestRedisSetIfAbsentAndPresent(t *testing.T) {
+	ctx := context.Background()
+	cache := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "setif"})
+	_ = cache.Remove(ctx, "key")
+
+	written, err := cache.SetIfPresent(ctx, "key", "first", time.Minute, nil)
+	require.NoError(t, err)
+	assert.False(t, written)
+
+	written, err = cache.SetIfAbsent(ctx, "key", "first", time.Minute, nil)
+	require.NoError(t, err)
+	assert.True(t, written)
+
+	written, err = cache.SetIfAbsent(ctx, "key", "second", time.Minute, nil)
+	require.NoError(t, err)
+	assert.False(t, written)
+
+	written, err = cache.SetIfPresent(ctx, "key", "third", time.Minute, nil)
+	require.NoError(t, err)
+	assert.True(t, written)
+
+	var value string
+	require.NoError(t, cache.Get(ctx, "key", &value))
+	assert.Equal(t, "third", value)
+	assert.NoError(t, cache.Remove(ctx, "key"))
+}
+
+func TestRedisSetNX(t *testing.T) {
+	ctx := context.Background()
+	cache, ok := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "setnx"}).(cachemar.ExtendedCacher)
+	require.True(t, ok)
+	_ = cache.Remove(ctx, "lock")
+
+	written, err := cache.SetNX(ctx, "lock", "owner-1", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, written)
+
+	written, err = cache.SetNX(ctx, "lock", "owner-2", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, written)
+
+	var value string
+	require.NoError(t, cache.Get(ctx, "lock", &value))
+	assert.Equal(t, "owner-1", value)
+	assert.NoError(t, cache.Remove(ctx, "lock"))
+}
+
+func TestRedisGetAndDelete(t *testing.T) {
+	ctx := context.Background()
+	cache := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "getdel"})
+
+	require.NoError(t, cache.Set(ctx, "token", "secret", time.Minute, nil))
+
+	var value string
+	require.NoError(t, cache.GetAndDelete(ctx, "token", &value))
+	assert.Equal(t, "secret", value)
+
+	assert.ErrorIs(t, cache.GetAndDelete(ctx, "token", &value), cachemar.ErrNotFound)
+}
+
+func TestRedisMGet(t *testing.T) {
+	ctx := context.Background()
+	cache := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "mget", CompressionEnabled: true})
+	_ = cache.Remove(ctx, "missing")
+
+	require.NoError(t, cache.Set(ctx, "a", "first", time.Minute, nil))
+	require.NoError(t, cache.Set(ctx, "b", 2, time.Minute, nil))
+
+	var a, missing string
+	var b int
+	err := cache.MGet(ctx, []string{"a", "missing", "b"}, []interface{}{&a, &missing, &b})
+
+	var errs cachemar.MultiError
+	require.True(t, errors.As(err, &errs))
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], cachemar.ErrNotFound)
+	assert.NoError(t, errs[2])
+	assert.Equal(t, "first", a)
+	assert.Equal(t, 2, b)
+}
+
+func TestRedisMSet(t *testing.T) {
+	ctx := context.Background()
+	cache := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "mset"})
+
+	err := cache.MSet(
+		ctx, []cachemar.CacheEntry{
+			{Key: "a", Value: "value-a", TTL: time.Minute, Tags: []string{"mset-tag"}},
+			{Key: "b", Value: 2, TTL: time.Minute},
+		},
+	)
+	require.NoError(t, err)
+
+	var a string
+	var b int
+	require.NoError(t, cache.MGet(ctx, []string{"a", "b"}, []interface{}{&a, &b}))
+	assert.Equal(t, "value-a", a)
+	assert.Equal(t, 2, b)
+}
+
+func TestNewSentinelOptions(t *testing.T) {
+	options := redis.NewSentinelOptions("mymaster", []string{"sentinel1:26379", "sentinel2:26379"}, "secret")
+
+	assert.Equal(t, "mymaster", options.MasterName)
+	assert.Equal(t, []string{"sentinel1:26379", "sentinel2:26379"}, options.SentinelAddrs)
+	assert.Equal(t, "secret", options.Password)
+	assert.NoError(t, options.Validate())
+
+	assert.Error(t, redis.NewSentinelOptions("", []string{"sentinel1:26379"}, "").Validate())
+}
+
+func TestOptionsValidateRejectsDSNWithClusterOrSentinel(t *testing.T) {
+	cluster := &redis.Options{DSN: "localhost:6379", ClusterAddrs: []string{"node1:7000"}}
+	assert.ErrorContains(t, cluster.Validate(), "DSN and cluster addresses are mutually exclusive")
+
+	sentinel := redis.NewSentinelOptions("mymaster", []string{"sentinel1:26379"}, "")
+	sentinel.DSN = "localhost:6379"
+	assert.ErrorContains(t, sentinel.Validate(), "DSN and sentinel addresses are mutually exclusive")
+}
+
+func TestRedisTouch(t *testing.T) {
+	ctx := context.Background()
+	cache := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "touch"})
+	_ = cache.Remove(ctx, "missing")
+
+	require.NoError(t, cache.Set(ctx, "session", "user-1", time.Second, []string{"sessions"}))
+	require.NoError(t, cache.Touch(ctx, "session", time.Hour))
+
+	var value string
+	ttl, err := cache.GetWithTTL(ctx, "session", &value)
+	require.NoError(t, err)
+	assert.Greater(t, ttl, time.Minute)
+
+	assert.ErrorIs(t, cache.Touch(ctx, "missing", time.Hour), cachemar.ErrNotFound)
+	assert.NoError(t, cache.Remove(ctx, "session"))
+}
+
+func TestRedisScan(t *testing.T) {
+	ctx := context.Background()
+	cache := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "scan"})
+	require.NoError(t, cache.Flush(ctx))
+
+	require.NoError(t, cache.Set(ctx, "user:1", "a", time.Minute, []string{"users"}))
+	require.NoError(t, cache.Set(ctx, "user:2", "b", time.Minute, nil))
+	require.NoError(t, cache.Set(ctx, "order:1", "c", time.Minute, nil))
+
+	scanner, ok := cache.(cachemar.Scanner)
+	require.True(t, ok)
+
+	var keys []string
+	require.NoError(
+		t, scanner.Scan(
+			ctx, "user:*", func(key string) error {
+				keys = append(keys, key)
+				return nil
+			},
+		),
+	)
+	sort.Strings(keys)
+	assert.Equal(t, []string{"user:1", "user:2"}, keys)
+
+	assert.NoError(t, cache.Flush(ctx))
+}
+
+func TestRedisCodec(t *testing.T) {
+	type user struct {
+		Name string
+		Age  int
+	}
+
+	ctx := context.Background()
+	options := (&redis.Options{DSN: "localhost:6379", Prefix: "codec"}).WithCodec(codecs.MsgpackCodec{})
+	cache := redis.New(options)
+
+	require.NoError(t, cache.Set(ctx, "user", user{Name: "Ann", Age: 42}, time.Minute, nil))
+
+	var got user
+	require.NoError(t, cache.Get(ctx, "user", &got))
+	assert.Equal(t, user{Name: "Ann", Age: 42}, got)
+
+	// The value is stored with the codec rather than JSON.
+	var decoded map[string]interface{}
+	assert.Error(t, redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "codec"}).Get(ctx, "user", &decoded))
+	assert.NoError(t, cache.Remove(ctx, "user"))
+}
+
+func TestCompressionAlgoValidate(t *testing.T) {
+	options := &redis.Options{DSN: "localhost:6379", CompressionEnabled: true, CompressionAlgo: redis.CompressionZstd}
+	assert.NoError(t, options.Validate())
+
+	options.CompressionAlgo = redis.CompressionAlgo(42)
+	assert.Error(t, options.Validate())
+	assert.Equal(t, "lz4", redis.CompressionLZ4.String())
+}
+
+func TestRedisCompressionAlgos(t *testing.T) {
+	ctx := context.Background()
+	value := strings.Repeat("compressible ", 100)
+
+	// A driver configured with gzip reads values written with any algorithm.
+	reader := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "compression", CompressionEnabled: true})
+
+	for _, algo := range []redis.CompressionAlgo{redis.CompressionGzip, redis.CompressionZstd, redis.CompressionLZ4} {
+		t.Run(
+			algo.String(), func(t *testing.T) {
+				cache := redis.New(
+					&redis.Options{
+						DSN:                "localhost:6379",
+						Prefix:             "compression",
+						CompressionEnabled: true,
+						CompressionAlgo:    algo,
+					},
+				)
+				require.NoError(t, cache.Set(ctx, "key", value, time.Minute, nil))
+
+				var got string
+				require.NoError(t, cache.Get(ctx, "key", &got))
+				assert.Equal(t, value, got)
+
+				require.NoError(t, reader.Get(ctx, "key", &got))
+				assert.Equal(t, value, got)
+
+				result, err := cache.(cachemar.Inspector).Inspect(ctx, "key")
+				require.NoError(t, err)
+				assert.True(t, result.Compressed)
+				assert.Less(t, result.SizeBytes, int64(len(value)))
+
+				assert.NoError(t, cache.Remove(ctx, "key"))
+			},
+		)
+	}
+}
+
+func TestRedisLock(t *testing.T) {
+	ctx := context.Background()
+	cacheService := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "prefix"})
+
+	manager := cachemar.New()
+	manager.Register("redis", cacheService)
+	locker, ok := manager.Locker()
+	require.True(t, ok)
+
+	token, err := locker.LockKey(ctx, "job", time.Minute)
+	require.NoError(t, err)
+	assert.Len(t, token, 36)
+
+	_, err = locker.LockKey(ctx, "job", time.Minute)
+	assert.ErrorIs(t, err, cachemar.ErrLockNotAcquired)
+
+	require.NoError(t, locker.UnlockKey(ctx, "job", "someone-else"))
+	_, err = locker.LockKey(ctx, "job", time.Minute)
+	assert.ErrorIs(t, err, cachemar.ErrLockNotAcquired)
+
+	require.NoError(t, locker.UnlockKey(ctx, "job", token))
+	token, err = locker.LockKey(ctx, "job", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, locker.UnlockKey(ctx, "job", token))
+}
+
+func TestRedisHash(t *testing.T) {
+	ctx := context.Background()
+	cacheService := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "prefix"})
+	hashes, ok := cacheService.(redis.HashCacher)
+	require.True(t, ok)
+
+	require.NoError(t, cacheService.Remove(ctx, "user"))
+	require.NoError(t, hashes.HSet(ctx, "user", "name", "Alice", time.Minute, []string{"users"}))
+	require.NoError(t, hashes.HSet(ctx, "user", "age", 30, 0, nil))
+
+	keys, err := cacheService.GetKeysByTag(ctx, "users")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prefix:user"}, keys)
+
+	var name string
+	require.NoError(t, hashes.HGet(ctx, "user", "name", &name))
+	assert.Equal(t, "Alice", name)
+	assert.True(t, cachemar.IsNotFound(hashes.HGet(ctx, "user", "email", &name)))
+
+	fields, err := hashes.HGetAll(ctx, "user")
+	require.NoError(t, err)
+	assert.Len(t, fields, 2)
+
+	require.NoError(t, hashes.HDel(ctx, "user", "name", "age"))
+	_, err = hashes.HGetAll(ctx, "user")
+	assert.True(t, cachemar.IsNotFound(err))
+
+	require.NoError(t, hashes.HSet(ctx, "user", "name", "Bob", time.Minute, []string{"users"}))
+	require.NoError(t, cacheService.RemoveByTag(ctx, "users"))
+	exists, err := cacheService.Exists(ctx, "user")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestRedisSortedSet(t *testing.T) {
+	ctx := context.Background()
+	cacheService := redis.New(&redis.Options{DSN: "localhost:6379", Prefix: "prefix"})
+	sets, ok := cacheService.(redis.SortedSetCacher)
+	require.True(t, ok)
+
+	require.NoError(t, cacheService.Remove(ctx, "leaderboard"))
+	require.NoError(t, sets.ZAdd(ctx, "leaderboard", []redis.ZMember{
+		{Score: 30, Member: "alice"},
+		{Score: 10, Member: "bob"},
+		{Score: 20, Member: "carol"},
+	}, time.Minute, []string{"leaderboards"}))
+
+	keys, err := cacheService.GetKeysByTag(ctx, "leaderboards")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prefix:leaderboard"}, keys)
+
+	members, err := sets.ZRange(ctx, "leaderboard", 0, -1)
+	require.NoError(t, err)
+	assert.Equal(t, []redis.ZMember{{Score: 10, Member: "bob"}, {Score: 20, Member: "carol"}, {Score: 30, Member: "alice"}}, members)
+
+	score, err := sets.ZScore(ctx, "leaderboard", "carol")
+	require.NoError(t, err)
+	assert.Equal(t, float64(20), score)
+	_, err = sets.ZScore(ctx, "leaderboard", "dave")
+	assert.True(t, cachemar.IsNotFound(err))
+
+	count, err := sets.ZCount(ctx, "leaderboard", "(10", "+inf")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	require.NoError(t, sets.ZRem(ctx, "leaderboard", "alice", "dave"))
+	count, err = sets.ZCount(ctx, "leaderboard", "-inf", "+inf")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	require.NoError(t, cacheService.RemoveByTag(ctx, "leaderboards"))
+	exists, err := cacheService.Exists(ctx, "leaderboard")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestRedisInvalidationPubSub(t *testing.T) {
+	ctx := context.Background()
+	client := goredis.NewClient(&goredis.Options{Addr: "localhost:6379"})
+	defer client.Close()
+
+	local := memory.New()
+	require.NoError(t, local.Set(ctx, "key", "value", time.Minute, nil))
+
+	subscriber := redis.NewInvalidationSubscriber(client, local, "cachemar:invalidation")
+	require.NoError(t, subscriber.Subscribe(ctx))
+	defer subscriber.Stop()
+
+	publisher := redis.NewInvalidationPublisher(client, "cachemar:invalidation")
+	require.NoError(t, publisher.Publish(ctx, "key"))
+
+	assert.Eventually(t, func() bool {
+		exists, err := local.Exists(ctx, "key")
+		return err == nil && !exists
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,131 @@
+package tests
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+	"github.com/stremovskyy/cachemar/replication"
+)
+
+func TestReplication(t *testing.T) {
+	ctx := context.Background()
+	primary := memory.New()
+	replica := memory.New()
+
+	replicated := replication.New(primary, []cachemar.Cacher{replica}, replication.ReplicationOptions{SyncWrite: true})
+
+	err := replicated.Set(ctx, "key", "value", time.Minute, nil)
+	assert.NoError(t, err)
+
+	var value string
+	err = replica.Get(ctx, "key", &value)
+	assert.NoError(t, err)
+	assert.Equal(t, "value", value)
+
+	err = replicated.Remove(ctx, "key")
+	assert.NoError(t, err)
+
+	exists, err := replica.Exists(ctx, "key")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestReplicationAsyncLag(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	primary := memory.New()
+	replica := memory.New()
+
+	var replicationErrors atomic.Int32
+	replicated := replication.New(
+		primary, []cachemar.Cacher{replica}, replication.ReplicationOptions{
+			ReplicationTimeout: time.Second,
+			OnReplicationError: func(int, error) { replicationErrors.Add(1) },
+		},
+	)
+
+	err := replicated.Set(ctx, "key", "value", time.Minute, nil)
+	assert.NoError(t, err)
+
+	lags, err := replicated.(replication.Lagger).Lag(ctx)
+	assert.NoError(t, err)
+	assert.Contains(t, lags, 0)
+
+	var value string
+	err = replica.Get(ctx, "key", &value)
+	assert.NoError(t, err)
+	assert.Equal(t, "value", value)
+
+	assert.NoError(t, replicated.Close())
+	assert.Zero(t, replicationErrors.Load())
+}
+
+// slowSetCacher delays Set until release is closed, if set, or by delay.
+type slowSetCacher struct {
+	cachemar.Cacher
+	delay   time.Duration
+	release chan struct{}
+}
+
+func (c *slowSetCacher) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
+	if c.release != nil {
+		<-c.release
+	}
+	time.Sleep(c.delay)
+	return c.Cacher.Set(ctx, key, value, ttl, tags)
+}
+
+func TestReplicationAsyncOrder(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	replica := memory.New()
+	replicated := replication.New(memory.New(), []cachemar.Cacher{&slowSetCacher{Cacher: replica, delay: 10 * time.Millisecond}}, replication.ReplicationOptions{})
+	defer replicated.Close()
+
+	require.NoError(t, replicated.Set(ctx, "key", "value", time.Minute, nil))
+	require.NoError(t, replicated.Remove(ctx, "key"))
+
+	// The canary is queued after the writes, so they have been applied once it arrives.
+	_, err := replicated.(replication.Lagger).Lag(ctx)
+	require.NoError(t, err)
+
+	exists, err := replica.Exists(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, exists, "the Remove must not overtake the Set")
+}
+
+func TestReplicationAsyncQueueFull(t *testing.T) {
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	var dropped atomic.Int32
+	replicated := replication.New(
+		memory.New(), []cachemar.Cacher{&slowSetCacher{Cacher: memory.New(), release: release}}, replication.ReplicationOptions{
+			QueueSize: 1,
+			OnReplicationError: func(replica int, err error) {
+				if assert.ErrorIs(t, err, replication.ErrQueueFull) {
+					dropped.Add(1)
+				}
+			},
+		},
+	)
+
+	// The first write blocks the worker, the second fills the queue and the third is dropped.
+	for i := 0; i < 3; i++ {
+		require.NoError(t, replicated.Set(ctx, "key", i, time.Minute, nil))
+	}
+	assert.Eventually(t, func() bool { return dropped.Load() >= 1 }, time.Second, time.Millisecond)
+
+	close(release)
+	require.NoError(t, replicated.Close())
+	assert.ErrorIs(t, replicated.Set(ctx, "key", "value", time.Minute, nil), replication.ErrClosed)
+}
@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar/drivers/redis"
+)
+
+// flakyRedis is a minimal Redis server that drops the first failures connections and answers
+// the commands of later ones with PONG, OK or 1.
+type flakyRedis struct {
+	listener net.Listener
+	failures int64
+	accepted atomic.Int64
+}
+
+func newFlakyRedis(t *testing.T, failures int64) *flakyRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	s := &flakyRedis{listener: listener, failures: failures}
+	go s.serve()
+
+	return s
+}
+
+func (s *flakyRedis) addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *flakyRedis) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		if s.accepted.Add(1) <= s.failures {
+			_ = conn.Close()
+			continue
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *flakyRedis) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+
+		var reply string
+		switch strings.ToUpper(args[0]) {
+		case "HELLO":
+			reply = "-ERR unknown command 'HELLO'\r\n"
+		case "PING":
+			reply = "+PONG\r\n"
+		case "EXISTS":
+			reply = ":1\r\n"
+		default:
+			reply = "+OK\r\n"
+		}
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+// readCommand reads a command sent as an array of bulk strings.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("unexpected line %q", line)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		if _, err := r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+
+	return args, nil
+}
+
+func TestRetryRecoversFromTransientErrors(t *testing.T) {
+	server := newFlakyRedis(t, 2)
+
+	cache := redis.New(&redis.Options{DSN: server.addr(), RetryCount: 3, RetryBaseDelay: time.Millisecond})
+	defer cache.Close()
+
+	require.NoError(t, cache.Ping())
+	assert.Equal(t, int64(3), server.accepted.Load())
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	server := newFlakyRedis(t, 3)
+
+	cache := redis.New(&redis.Options{DSN: server.addr(), RetryCount: 1, RetryBaseDelay: time.Millisecond})
+	defer cache.Close()
+
+	assert.Error(t, cache.Ping())
+	assert.Equal(t, int64(2), server.accepted.Load())
+}
+
+func TestRetryStopsOnContextCancel(t *testing.T) {
+	server := newFlakyRedis(t, 100)
+
+	cache := redis.New(&redis.Options{DSN: server.addr(), RetryCount: 5, RetryBaseDelay: time.Second})
+	defer cache.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := cache.Exists(ctx, "key")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRetryOptionsValidate(t *testing.T) {
+	assert.Error(t, (&redis.Options{DSN: "localhost:6379", RetryCount: -1}).Validate())
+	assert.Error(t, (&redis.Options{DSN: "localhost:6379", RetryBaseDelay: -time.Second}).Validate())
+}
+
+func TestRetrySkipsNonIdempotentCommands(t *testing.T) {
+	server := newFlakyRedis(t, 2)
+
+	cache := redis.New(&redis.Options{DSN: server.addr(), RetryCount: 3, RetryBaseDelay: time.Millisecond})
+	defer cache.Close()
+
+	err := cache.Increment(context.Background(), "counter")
+	assert.Error(t, err)
+	assert.Equal(t, int64(1), server.accepted.Load())
+}
+
+func TestRetryConnectLogsToLogger(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	logger := &recordingLogger{}
+	cache := redis.New(
+		&redis.Options{
+			DSN:                  addr,
+			RetryConnect:         true,
+			RetryConnectTimeout:  200 * time.Millisecond,
+			RetryConnectInterval: 10 * time.Millisecond,
+			RetryCount:           1,
+			RetryBaseDelay:       time.Millisecond,
+			Logger:               logger,
+		},
+	)
+	defer cache.Close()
+
+	initer, ok := cache.(interface{ Init(context.Context) error })
+	require.True(t, ok)
+	assert.Error(t, initer.Init(context.Background()))
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	require.NotEmpty(t, logger.entries)
+	assert.Contains(t, logger.entries[0], "debug cachemar: ping failed, retrying [operation Ping attempt 1")
+}
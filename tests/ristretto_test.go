@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/ristretto"
+)
+
+func TestRistretto(t *testing.T) {
+	ctx := context.Background()
+	cache := ristretto.New(&ristretto.Options{})
+	defer cache.Close()
+
+	require.NoError(t, cache.Set(ctx, "key", profile{Name: "Alice", Age: 30}, time.Minute, []string{"users"}))
+
+	var value profile
+	require.NoError(t, cache.Get(ctx, "key", &value))
+	assert.Equal(t, profile{Name: "Alice", Age: 30}, value)
+	assert.True(t, cachemar.IsNotFound(cache.Get(ctx, "missing", &value)))
+
+	ttl, err := cache.GetWithTTL(ctx, "key", &value)
+	require.NoError(t, err)
+	assert.InDelta(t, time.Minute, ttl, float64(time.Second))
+
+	written, err := cache.SetIfAbsent(ctx, "key", profile{}, time.Minute, nil)
+	require.NoError(t, err)
+	assert.False(t, written)
+
+	keys, err := cache.GetKeysByTag(ctx, "users")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key"}, keys)
+
+	require.NoError(t, cache.RemoveByTag(ctx, "users"))
+	exists, err := cache.Exists(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestRistrettoExpiry(t *testing.T) {
+	ctx := context.Background()
+	cache := ristretto.New(&ristretto.Options{})
+	defer cache.Close()
+
+	require.NoError(t, cache.Set(ctx, "key", "value", 50*time.Millisecond, nil))
+	require.NoError(t, cache.Set(ctx, "forever", "value", 0, nil))
+
+	var value string
+	ttl, err := cache.GetWithTTL(ctx, "forever", &value)
+	require.NoError(t, err)
+	assert.Equal(t, cachemar.NoExpiry, ttl)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.True(t, cachemar.IsNotFound(cache.Get(ctx, "key", &value)))
+}
+
+func TestRistrettoCounters(t *testing.T) {
+	ctx := context.Background()
+	cache := ristretto.New(&ristretto.Options{})
+	defer cache.Close()
+
+	value, err := cache.IncrBy(ctx, "counter", 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), value)
+
+	require.NoError(t, cache.Increment(ctx, "counter"))
+	value, err = cache.DecrBy(ctx, "counter", 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), value)
+
+	require.NoError(t, cache.Set(ctx, "text", "not a number", time.Minute, nil))
+	_, err = cache.IncrBy(ctx, "text", 1)
+	assert.ErrorIs(t, err, cachemar.ErrInvalidType)
+
+	require.NoError(t, cache.Flush(ctx))
+	exists, err := cache.Exists(ctx, "counter")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestRistrettoMaxCost(t *testing.T) {
+	ctx := context.Background()
+	cache := ristretto.New(&ristretto.Options{MaxCost: 1 << 10, NumCounters: 1000})
+	defer cache.Close()
+
+	value := make([]byte, 200)
+	for i := 0; i < 50; i++ {
+		require.NoError(t, cache.Set(ctx, string(rune('a'+i)), value, time.Minute, nil))
+	}
+
+	stored := 0
+	for i := 0; i < 50; i++ {
+		if exists, _ := cache.Exists(ctx, string(rune('a'+i))); exists {
+			stored++
+		}
+	}
+	assert.Less(t, stored, 50, "values beyond MaxCost are evicted or rejected")
+
+	assert.Panics(t, func() { ristretto.New(&ristretto.Options{MaxCost: -1}) })
+}
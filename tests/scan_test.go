@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func TestMemoryScan(t *testing.T) {
+	ctx := context.Background()
+
+	cache := memory.New()
+	require.NoError(t, cache.Set(ctx, "user:1", "a", time.Minute, nil))
+	require.NoError(t, cache.Set(ctx, "user:2", "b", time.Minute, nil))
+	require.NoError(t, cache.Set(ctx, "user:3", "c", -time.Second, nil))
+	require.NoError(t, cache.Set(ctx, "order:1", "d", time.Minute, nil))
+
+	scanner, ok := cache.(cachemar.Scanner)
+	require.True(t, ok)
+
+	var keys []string
+	require.NoError(
+		t, scanner.Scan(
+			ctx, "user:*", func(key string) error {
+				keys = append(keys, key)
+				// The lock is released while fn runs.
+				return cache.Remove(ctx, key)
+			},
+		),
+	)
+	sort.Strings(keys)
+	assert.Equal(t, []string{"user:1", "user:2"}, keys)
+
+	stop := errors.New("stop")
+	assert.ErrorIs(t, scanner.Scan(ctx, "*", func(key string) error { return stop }), stop)
+
+	assert.Error(t, scanner.Scan(ctx, "[", func(key string) error { return nil }))
+}
@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar/drivers/memory"
+	cachetesting "github.com/stremovskyy/cachemar/testing"
+)
+
+func TestSeed(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.NewWithJSON()
+
+	fixture := filepath.Join(t.TempDir(), "seeds.json")
+	require.NoError(
+		t, os.WriteFile(fixture, []byte(`[{"key": "user:1", "value": {"name": "John"}, "ttl": "5m", "tags": ["users"]}]`), 0o600),
+	)
+
+	t.Run(
+		"seeded", func(t *testing.T) {
+			seeds := []cachetesting.SeedItem{
+				{Key: "a", Value: "value a", TTL: time.Minute},
+				{Key: "b", Value: "value b", Tags: []string{"letters"}},
+			}
+			assert.NoError(t, cachetesting.Seed(ctx, cache, seeds, t))
+			assert.NoError(t, cachetesting.SeedFromJSON(ctx, cache, fixture, t))
+
+			var value string
+			assert.NoError(t, cache.Get(ctx, "b", &value))
+			assert.Equal(t, "value b", value)
+
+			var user map[string]string
+			assert.NoError(t, cache.Get(ctx, "user:1", &user))
+			assert.Equal(t, "John", user["name"])
+
+			keys, err := cache.GetKeysByTag(ctx, "users")
+			assert.NoError(t, err)
+			assert.Equal(t, []string{"user:1"}, keys)
+		},
+	)
+
+	for _, key := range []string{"a", "b", "user:1"} {
+		exists, err := cache.Exists(ctx, key)
+		assert.NoError(t, err)
+		assert.False(t, exists, "seed %s should be removed after the test", key)
+	}
+}
@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func TestSetIfAbsentAndPresent(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+
+	written, err := manager.SetIfPresent(ctx, "key", "first", time.Minute, nil)
+	require.NoError(t, err)
+	assert.False(t, written)
+
+	written, err = manager.SetIfAbsent(ctx, "key", "first", time.Minute, []string{"tag"})
+	require.NoError(t, err)
+	assert.True(t, written)
+
+	written, err = manager.SetIfAbsent(ctx, "key", "second", time.Minute, nil)
+	require.NoError(t, err)
+	assert.False(t, written)
+
+	var value string
+	require.NoError(t, manager.Get(ctx, "key", &value))
+	assert.Equal(t, "first", value)
+
+	written, err = manager.SetIfPresent(ctx, "key", "third", time.Minute, nil)
+	require.NoError(t, err)
+	assert.True(t, written)
+
+	require.NoError(t, manager.Get(ctx, "key", &value))
+	assert.Equal(t, "third", value)
+
+	keys, err := manager.GetKeysByTag(ctx, "tag")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	// An expired key counts as absent.
+	require.NoError(t, manager.Set(ctx, "expired", "old", -time.Second, nil))
+	written, err = manager.SetIfPresent(ctx, "expired", "new", time.Minute, nil)
+	require.NoError(t, err)
+	assert.False(t, written)
+	written, err = manager.SetIfAbsent(ctx, "expired", "new", time.Minute, nil)
+	require.NoError(t, err)
+	assert.True(t, written)
+}
+
+func TestChainSetIfAbsent(t *testing.T) {
+	ctx := context.Background()
+
+	l1, l2 := memory.New(), memory.New()
+	manager := cachemar.New()
+	manager.Register("l1", l1)
+	manager.Register("l2", l2)
+
+	chain := manager.Chain()
+	chain.AddToChain("l1")
+	chain.AddToChain("l2")
+
+	// The last layer decides.
+	require.NoError(t, l2.Set(ctx, "taken", "l2", time.Minute, nil))
+	written, err := chain.SetIfAbsent(ctx, "taken", "chain", time.Minute, nil)
+	require.NoError(t, err)
+	assert.False(t, written)
+
+	exists, err := l1.Exists(ctx, "taken")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	written, err = chain.SetIfAbsent(ctx, "free", "chain", time.Minute, nil)
+	require.NoError(t, err)
+	assert.True(t, written)
+
+	for _, cache := range []cachemar.Cacher{l1, l2} {
+		var value string
+		require.NoError(t, cache.Get(ctx, "free", &value))
+		assert.Equal(t, "chain", value)
+	}
+
+	manager.SetReadOnly(true)
+	_, err = chain.SetIfAbsent(ctx, "other", "chain", time.Minute, nil)
+	assert.ErrorIs(t, err, cachemar.ErrReadOnly)
+}
+
+func TestMemorySetNX(t *testing.T) {
+	ctx := context.Background()
+
+	cache, ok := memory.New().(cachemar.ExtendedCacher)
+	require.True(t, ok)
+
+	written, err := cache.SetNX(ctx, "lock", "owner-1", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, written)
+
+	written, err = cache.SetNX(ctx, "lock", "owner-2", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, written)
+
+	var value string
+	require.NoError(t, cache.Get(ctx, "lock", &value))
+	assert.Equal(t, "owner-1", value)
+
+	require.NoError(t, cache.Remove(ctx, "lock"))
+	written, err = cache.SetNX(ctx, "lock", "owner-2", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, written)
+}
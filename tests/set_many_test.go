@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func TestSetMany(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.NewWithOptions(cachemar.WithKeyValidator(cachemar.NewPIISafeKeyValidator()))
+	manager.Register("memory", memory.New())
+
+	err := manager.SetMany(
+		ctx,
+		cachemar.NewCacheItem("a").Value("value-a").TTL(time.Minute).Tags("tag").Build(),
+		cachemar.CacheItem{Key: "user:john@example.com", Value: "value-b", TTL: time.Minute},
+		cachemar.CacheItem{Key: "c", Value: "value-c", TTL: time.Minute},
+	)
+
+	var multiErr cachemar.MultiError
+	assert.True(t, errors.As(err, &multiErr))
+	assert.Len(t, multiErr, 3)
+	assert.NoError(t, multiErr[0])
+	assert.True(t, errors.Is(multiErr[1], cachemar.ErrInvalidKey))
+	assert.NoError(t, multiErr[2])
+
+	var value string
+	assert.NoError(t, manager.Get(ctx, "a", &value))
+	assert.Equal(t, "value-a", value)
+	assert.NoError(t, manager.Get(ctx, "c", &value))
+	assert.Equal(t, "value-c", value)
+
+	keys, err := manager.GetKeysByTag(ctx, "tag")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, keys)
+}
+
+func TestMSetEncodeError(t *testing.T) {
+	ctx := context.Background()
+	cache := memory.New()
+
+	err := cache.MSet(
+		ctx, []cachemar.CacheEntry{
+			{Key: "a", Value: "value-a", TTL: time.Minute},
+			{Key: "b", Value: make(chan int), TTL: time.Minute},
+		},
+	)
+
+	var multiErr cachemar.MultiError
+	assert.True(t, errors.As(err, &multiErr))
+	assert.NoError(t, multiErr[0])
+	assert.Error(t, multiErr[1])
+
+	exists, _ := cache.Exists(ctx, "a")
+	assert.True(t, exists)
+	exists, _ = cache.Exists(ctx, "b")
+	assert.False(t, exists)
+}
+
+func TestChainMSet(t *testing.T) {
+	ctx := context.Background()
+
+	l1, l2 := memory.New(), memory.New()
+	manager := cachemar.New()
+	manager.Register("l1", l1)
+	manager.Register("l2", l2)
+
+	chain := manager.Chain()
+	chain.AddToChain("l1")
+	chain.AddToChain("l2")
+
+	err := chain.MSet(
+		ctx, []cachemar.CacheEntry{
+			{Key: "a", Value: "value-a", TTL: time.Minute, Tags: []string{"tag"}},
+			{Key: "b", Value: "value-b", TTL: time.Minute},
+		},
+	)
+	assert.NoError(t, err)
+
+	for _, cache := range []cachemar.Cacher{l1, l2} {
+		var value string
+		assert.NoError(t, cache.Get(ctx, "b", &value))
+		assert.Equal(t, "value-b", value)
+
+		keys, err := cache.GetKeysByTag(ctx, "tag")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a"}, keys)
+	}
+}
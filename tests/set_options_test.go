@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func TestSetOptionsBuilder(t *testing.T) {
+	options := cachemar.NewSetOptions().WithTTL(time.Minute).WithTags("a", "b").WithNX().Build()
+
+	assert.Equal(t, cachemar.SetOptions{TTL: time.Minute, Tags: []string{"a", "b"}, IfAbsent: true}, options)
+	assert.Equal(t, cachemar.DefaultCacheTime, cachemar.NewSetOptions().Build().TTL)
+	assert.Error(t, cachemar.NewSetOptions().WithNX().WithXX().Build().Validate())
+}
+
+func TestSetOpts(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+
+	require.NoError(t, manager.SetOpts(ctx, "key", "first", cachemar.NewSetOptions().WithTags("tag").Build()))
+
+	keys, err := manager.GetKeysByTag(ctx, "tag")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key"}, keys)
+
+	err = manager.SetOpts(ctx, "key", "second", cachemar.NewSetOptions().WithNX().Build())
+	assert.ErrorIs(t, err, cachemar.ErrNotStored)
+
+	err = manager.SetOpts(ctx, "missing", "second", cachemar.NewSetOptions().WithXX().Build())
+	assert.ErrorIs(t, err, cachemar.ErrNotStored)
+
+	require.NoError(t, manager.SetOpts(ctx, "key", "third", cachemar.NewSetOptions().WithXX().Build()))
+
+	var value string
+	require.NoError(t, manager.Get(ctx, "key", &value))
+	assert.Equal(t, "third", value)
+
+	err = manager.SetOpts(ctx, "key", "fourth", cachemar.SetOptions{TTL: time.Minute, IfAbsent: true, IfPresent: true})
+	assert.Error(t, err)
+}
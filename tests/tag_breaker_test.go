@@ -0,0 +1,43 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func TestTagCircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.NewWithOptions(cachemar.WithTagCircuitBreaker(2))
+	manager.Register("memory", memory.New())
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, manager.Set(ctx, fmt.Sprintf("hot-%d", i), i, time.Minute, []string{"hot"}))
+	}
+	assert.NoError(t, manager.Set(ctx, "cold", 1, time.Minute, []string{"cold"}))
+
+	_, err := manager.GetKeysByTag(ctx, "hot")
+	assert.True(t, errors.Is(err, cachemar.ErrTagTooLarge))
+
+	err = manager.RemoveByTag(ctx, "hot")
+	assert.True(t, errors.Is(err, cachemar.ErrTagTooLarge))
+
+	keys, err := manager.GetKeysByTag(ctx, "cold")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cold"}, keys)
+
+	err = manager.ForceRemoveByTag(ctx, "hot", 1)
+	assert.NoError(t, err)
+
+	exists, err := manager.Exists(ctx, "hot-0")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
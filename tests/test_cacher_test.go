@@ -0,0 +1,39 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	cachetesting "github.com/stremovskyy/cachemar/testing"
+)
+
+func TestTestCacher(t *testing.T) {
+	ctx := context.Background()
+	cache := cachetesting.NewTestCacher()
+
+	assert.NoError(t, cache.Set(ctx, "key", "value", time.Minute, []string{"tag"}))
+
+	var value string
+	assert.NoError(t, cache.Get(ctx, "key", &value))
+	assert.Equal(t, "value", value)
+
+	cache.AssertCalled(t, "Set", "key")
+	cache.AssertCalled(t, "Get", "key")
+	cache.AssertNotCalled(t, "Remove", "key")
+	cache.AssertCallCount(t, "Get", 1)
+
+	errDown := errors.New("down")
+	cache.SimulateError("Get", errDown)
+	assert.ErrorIs(t, cache.Get(ctx, "key", &value), errDown)
+	assert.Len(t, cache.Calls(), 3)
+	assert.ErrorIs(t, cache.Calls()[2].Error, errDown)
+
+	cache.Reset()
+	assert.Empty(t, cache.Calls())
+	assert.NoError(t, cache.Get(ctx, "key", &value))
+	cache.AssertCallCount(t, "Get", 1)
+}
@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memcached"
+	"github.com/stremovskyy/cachemar/drivers/redis"
+)
+
+// silentServer accepts connections and never responds. The connections are closed when the test finishes.
+func silentServer(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	var (
+		mu    sync.Mutex
+		conns []net.Conn
+		done  = make(chan struct{})
+	)
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+		<-done
+
+		mu.Lock()
+		defer mu.Unlock()
+		for _, conn := range conns {
+			_ = conn.Close()
+		}
+	})
+
+	go func() {
+		defer close(done)
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			mu.Lock()
+			conns = append(conns, conn)
+			mu.Unlock()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestDriverTimeouts(t *testing.T) {
+	ctx := context.Background()
+	addr := silentServer(t)
+
+	drivers := map[string]interface {
+		Get(ctx context.Context, key string, value interface{}) error
+	}{
+		"redis":     redis.New((&redis.Options{DSN: addr}).WithGetTimeout(50 * time.Millisecond)),
+		"memcached": memcached.New((&memcached.Options{Servers: []string{addr}}).WithGetTimeout(50 * time.Millisecond)),
+	}
+
+	for name, driver := range drivers {
+		t.Run(
+			name, func(t *testing.T) {
+				start := time.Now()
+
+				var value string
+				err := driver.Get(ctx, "key", &value)
+				assert.True(t, cachemar.IsTimeout(err), "expected timeout, got %v", err)
+				assert.Less(t, time.Since(start), 400*time.Millisecond)
+			},
+		)
+	}
+}
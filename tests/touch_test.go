@@ -0,0 +1,64 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func TestTouch(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+	require.NoError(t, manager.Set(ctx, "session", "user-1", time.Second, nil))
+
+	require.NoError(t, manager.Touch(ctx, "session", time.Hour))
+
+	var value string
+	ttl, err := manager.GetWithTTL(ctx, "session", &value)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", value)
+	assert.Greater(t, ttl, time.Minute)
+
+	assert.ErrorIs(t, manager.Touch(ctx, "missing", time.Hour), cachemar.ErrNotFound)
+
+	manager.SetReadOnly(true)
+	assert.ErrorIs(t, manager.Touch(ctx, "session", time.Hour), cachemar.ErrReadOnly)
+}
+
+func TestTouchExpired(t *testing.T) {
+	ctx := context.Background()
+
+	cache := memory.New()
+	require.NoError(t, cache.Set(ctx, "session", "user-1", -time.Second, nil))
+
+	assert.ErrorIs(t, cache.Touch(ctx, "session", time.Hour), cachemar.ErrNotFound)
+}
+
+func TestChainTouch(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.New()
+	manager.Register("first", memory.New())
+	manager.Register("second", memory.New())
+	chain := manager.Chain()
+	chain.AddToChain("first")
+	chain.AddToChain("second")
+
+	require.NoError(t, manager.Use("second").Set(ctx, "session", "user-1", time.Second, nil))
+	require.NoError(t, chain.Touch(ctx, "session", time.Hour))
+
+	var value string
+	ttl, err := manager.Use("second").GetWithTTL(ctx, "session", &value)
+	require.NoError(t, err)
+	assert.Greater(t, ttl, time.Minute)
+
+	assert.ErrorIs(t, chain.Touch(ctx, "missing", time.Hour), cachemar.ErrNotFound)
+}
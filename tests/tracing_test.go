@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/contrib/tracing"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+	"github.com/stremovskyy/cachemar/drivers/redis"
+)
+
+func TestAutoTracingSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	// Nothing listens on the port, so the spans end with an error.
+	options := (&redis.Options{DSN: "127.0.0.1:1", Tracer: provider.Tracer("test")}).WithAutoTracing()
+	driver := redis.New(options)
+
+	err := driver.RemoveByTags(context.Background(), []string{"users"})
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	child, parent := spans[0], spans[1]
+	assert.Equal(t, "cachemar.RemoveByTag", child.Name())
+	assert.Equal(t, "cachemar.RemoveByTags", parent.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), child.Parent().SpanID())
+	assert.Contains(t, child.Attributes(), attribute.String("tag", "users"))
+	assert.Equal(t, codes.Error, child.Status().Code)
+
+	require.NotEmpty(t, child.Events())
+	assert.Equal(t, "keys", child.Events()[0].Name)
+}
+
+func TestAutoTracingDisabled(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	driver := redis.New(&redis.Options{DSN: "127.0.0.1:1", Tracer: provider.Tracer("test")})
+
+	_, _ = driver.GetKeysByTag(context.Background(), "users")
+	assert.Empty(t, recorder.Ended())
+}
+
+func TestTracedCacher(t *testing.T) {
+	ctx := context.Background()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+	manager.WrapAll(
+		func(c cachemar.Cacher) cachemar.Cacher {
+			return tracing.NewTracedCacher(c, provider.Tracer("test"))
+		},
+	)
+
+	require.NoError(t, manager.Set(ctx, "key", "value", time.Minute, nil))
+
+	var value string
+	require.NoError(t, manager.Get(ctx, "key", &value))
+	require.ErrorIs(t, manager.Get(ctx, "missing", &value), cachemar.ErrNotFound)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	set, hit, miss := spans[0], spans[1], spans[2]
+	assert.Equal(t, "cachemar.Set", set.Name())
+	assert.Contains(t, set.Attributes(), tracing.AttributeKey.String("key"))
+	assert.Contains(t, set.Attributes(), tracing.AttributeTTL.String("1m0s"))
+	assert.Contains(t, set.Attributes(), tracing.AttributeDriver.String(cachemar.MemoryCacherName.String()))
+
+	assert.Equal(t, "cachemar.Get", hit.Name())
+	assert.Contains(t, hit.Attributes(), tracing.AttributeHit.Bool(true))
+
+	assert.Contains(t, miss.Attributes(), tracing.AttributeHit.Bool(false))
+	assert.Equal(t, codes.Unset, miss.Status().Code)
+
+	// Errors other than misses are recorded.
+	require.Error(t, manager.Increment(ctx, "key"))
+	failed := recorder.Ended()[3]
+	assert.Equal(t, codes.Error, failed.Status().Code)
+	assert.NotEmpty(t, failed.Events())
+}
+
+func TestTracedCacherOptionalInterfaces(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.NewWithOptions(cachemar.WithTagCircuitBreaker(1))
+	manager.Register("memory", memory.New())
+	manager.WrapAll(
+		func(c cachemar.Cacher) cachemar.Cacher {
+			return tracing.NewTracedCacher(c, nil)
+		},
+	)
+
+	require.NoError(t, manager.Set(ctx, "key", "value", time.Minute, []string{"tag"}))
+	require.NoError(t, manager.Set(ctx, "other", "value", time.Minute, []string{"tag"}))
+	assert.ErrorIs(t, manager.RemoveByTag(ctx, "tag"), cachemar.ErrTagTooLarge)
+
+	errFailed := errors.New("failed")
+	err := cachemar.Group(manager).
+		Add(func(ctx context.Context) error { return manager.Set(ctx, "key", "changed", time.Minute, nil) }).
+		Add(func(ctx context.Context) error { return errFailed }).
+		Execute(ctx)
+	require.ErrorIs(t, err, errFailed)
+
+	var value string
+	require.NoError(t, manager.Get(ctx, "key", &value))
+	assert.Equal(t, "value", value, "the transaction of the traced driver was rolled back")
+}
@@ -0,0 +1,44 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func TestTTL(t *testing.T) {
+	assert.Equal(t, time.Duration(0), cachemar.TTLZero.Duration())
+	assert.Equal(t, time.Minute, cachemar.TTLShort.Duration())
+	assert.Equal(t, 15*time.Minute, cachemar.TTLMedium.Duration())
+	assert.Equal(t, time.Hour, cachemar.TTLLong.Duration())
+	assert.Equal(t, 24*time.Hour, cachemar.TTLDay.Duration())
+	assert.Equal(t, 7*24*time.Hour, cachemar.TTLWeek.Duration())
+}
+
+func TestTTLJittered(t *testing.T) {
+	assert.Equal(t, time.Hour, cachemar.TTLJittered(cachemar.TTLLong, 0))
+
+	for i := 0; i < 100; i++ {
+		ttl := cachemar.TTLJittered(cachemar.TTLLong, time.Minute)
+		assert.GreaterOrEqual(t, ttl, time.Hour)
+		assert.LessOrEqual(t, ttl, time.Hour+time.Minute)
+	}
+}
+
+func TestSetTTL(t *testing.T) {
+	ctx := context.Background()
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+
+	require.NoError(t, manager.SetTTL(ctx, "key", "value", cachemar.TTLLong, nil))
+
+	result, err := manager.Inspect(ctx, "key")
+	require.NoError(t, err)
+	assert.InDelta(t, time.Hour, result.TTLRemaining, float64(time.Second))
+}
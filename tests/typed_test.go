@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+type profile struct {
+	Name string
+	Age  int
+}
+
+func TestTypedCache(t *testing.T) {
+	ctx := context.Background()
+
+	profiles := cachemar.NewTypedCache[profile](memory.New())
+	require.NoError(t, profiles.Set(ctx, "alice", profile{Name: "Alice", Age: 30}, time.Minute, nil))
+
+	value, err := profiles.Get(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, profile{Name: "Alice", Age: 30}, value)
+
+	value, err = profiles.Get(ctx, "missing")
+	assert.True(t, cachemar.IsNotFound(err))
+	assert.Zero(t, value)
+
+	loaded, err := profiles.GetOrSet(ctx, "bob", time.Minute, nil, func(ctx context.Context) (profile, error) {
+		return profile{Name: "Bob"}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Bob", loaded.Name)
+
+	value, err = profiles.Get(ctx, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, "Bob", value.Name)
+}
+
+func TestTypedCacheManagerGetOrSet(t *testing.T) {
+	ctx := context.Background()
+
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+	counters := cachemar.NewTypedCache[int](manager)
+
+	var calls atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := counters.GetOrSet(ctx, "answer", time.Minute, nil, func(ctx context.Context) (int, error) {
+				calls.Add(1)
+				time.Sleep(10 * time.Millisecond)
+				return 42, nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, 42, value)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestMGetTyped(t *testing.T) {
+	ctx := context.Background()
+
+	cache := memory.New()
+	require.NoError(t, cache.Set(ctx, "alice", profile{Name: "Alice"}, time.Minute, nil))
+	require.NoError(t, cache.Set(ctx, "bob", profile{Name: "Bob"}, time.Minute, nil))
+
+	found, err := cachemar.MGetTyped[profile](ctx, cache, []string{"alice", "missing", "bob"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]profile{"alice": {Name: "Alice"}, "bob": {Name: "Bob"}}, found)
+
+	require.NoError(t, cache.Set(ctx, "text", "not a profile", time.Minute, nil))
+	found, err = cachemar.MGetTyped[profile](ctx, cache, []string{"alice", "text"})
+	assert.Error(t, err)
+	assert.False(t, cachemar.IsNotFound(err))
+	assert.Equal(t, map[string]profile{"alice": {Name: "Alice"}}, found)
+}
@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stremovskyy/cachemar"
+	"github.com/stremovskyy/cachemar/drivers/memory"
+)
+
+func TestGetKeyVersion(t *testing.T) {
+	ctx := context.Background()
+	manager := cachemar.New()
+	manager.Register("memory", memory.New())
+
+	version, err := manager.GetKeyVersion(ctx, "products")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), version)
+
+	require.NoError(t, manager.Set(ctx, cachemar.VersionKey("products"), 1, time.Minute, nil))
+	require.NoError(t, manager.Increment(ctx, cachemar.VersionKey("products")))
+
+	version, err = manager.GetKeyVersion(ctx, "products")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), version)
+	assert.Equal(t, "products:v2", cachemar.VersionedKey("products", version))
+
+	chain := manager.Chain()
+	chain.AddToChain("memory")
+	version, err = chain.GetKeyVersion(ctx, "products")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), version)
+
+	version, err = chain.GetKeyVersion(ctx, "missing")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), version)
+}
+
+func TestWatchKeyVersion(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	manager := cachemar.NewWithOptions(cachemar.WithCheckInterval(5 * time.Millisecond))
+	manager.Register("memory", memory.New())
+
+	require.NoError(t, manager.Set(ctx, cachemar.VersionKey("products"), 1, time.Minute, nil))
+
+	versions, err := manager.WatchKeyVersion(ctx, "products")
+	require.NoError(t, err)
+
+	require.NoError(t, manager.Increment(ctx, cachemar.VersionKey("products")))
+
+	select {
+	case version := <-versions:
+		assert.Equal(t, uint64(2), version)
+	case <-time.After(time.Second):
+		t.Fatal("Expected a version change")
+	}
+
+	cancel()
+	select {
+	case _, open := <-versions:
+		assert.False(t, open)
+	case <-time.After(time.Second):
+		t.Fatal("Expected the channel to be closed")
+	}
+}
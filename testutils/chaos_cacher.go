@@ -0,0 +1,272 @@
+package testutils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+// ErrChaos is wrapped by all errors injected by the cache manager of NewChaosCacher.
+var ErrChaos = errors.New("chaos injection")
+
+// ChaosCacherOptions configures the faults injected by NewChaosCacher.
+type ChaosCacherOptions struct {
+	// ErrorRate is the probability, from 0 to 1, that a call fails with ErrChaos.
+	ErrorRate float64
+	// LatencyP50 and LatencyP99 shape the synthetic latency added to every call: half of the calls wait
+	// up to LatencyP50, and 99% of them up to LatencyP99. Zero adds no latency.
+	LatencyP50 time.Duration
+	LatencyP99 time.Duration
+	// PanicRate is the probability, from 0 to 1, that a call panics. The panic is recovered and returned
+	// as an error wrapping ErrChaos.
+	PanicRate float64
+	// Seed seeds the random source, so that the same calls get the same faults.
+	Seed int64
+}
+
+// chaosCacher injects errors, latency and panics into the calls of a cache manager.
+type chaosCacher struct {
+	inner cachemar.Cacher
+	opts  ChaosCacherOptions
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewChaosCacher returns a cache manager delegating to inner that randomly fails, slows down or panics as
+// configured by opts, to test how code behaves when the cache is unreliable.
+func NewChaosCacher(inner cachemar.Cacher, opts ChaosCacherOptions) cachemar.Cacher {
+	return &chaosCacher{
+		inner: inner,
+		opts:  opts,
+		rnd:   rand.New(rand.NewSource(opts.Seed)),
+	}
+}
+
+// draws returns the random numbers deciding the latency, the panic and the error of a call.
+func (c *chaosCacher) draws() (latency, panics, fails float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.rnd.Float64(), c.rnd.Float64(), c.rnd.Float64()
+}
+
+// latency maps a uniform draw to a latency growing linearly to LatencyP50 at the median, then to
+// LatencyP99 at the 99th percentile, where it is capped.
+func (c *chaosCacher) latency(u float64) time.Duration {
+	p50, p99 := c.opts.LatencyP50, c.opts.LatencyP99
+	if p99 < p50 {
+		p99 = p50
+	}
+
+	switch {
+	case u < 0.5:
+		return time.Duration(u / 0.5 * float64(p50))
+	case u < 0.99:
+		return p50 + time.Duration((u-0.5)/0.49*float64(p99-p50))
+	default:
+		return p99
+	}
+}
+
+// inject applies the faults drawn for a call of the method, and returns the error to fail it with.
+func (c *chaosCacher) inject(ctx context.Context, method string) (err error) {
+	latency, panics, fails := c.draws()
+
+	if d := c.latency(latency); d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s: %w: recovered panic: %v", method, ErrChaos, r)
+		}
+	}()
+
+	if panics < c.opts.PanicRate {
+		panic(method)
+	}
+	if fails < c.opts.ErrorRate {
+		return fmt.Errorf("%s: %w", method, ErrChaos)
+	}
+
+	return nil
+}
+
+func (c *chaosCacher) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
+	if err := c.inject(ctx, "Set"); err != nil {
+		return err
+	}
+	return c.inner.Set(ctx, key, value, ttl, tags)
+}
+
+func (c *chaosCacher) MSet(ctx context.Context, items []cachemar.CacheItem) error {
+	if err := c.inject(ctx, "MSet"); err != nil {
+		return err
+	}
+	return c.inner.MSet(ctx, items)
+}
+
+func (c *chaosCacher) SetOpts(ctx context.Context, key string, value interface{}, opts cachemar.SetOptions) error {
+	if err := c.inject(ctx, "SetOpts"); err != nil {
+		return err
+	}
+	return c.inner.SetOpts(ctx, key, value, opts)
+}
+
+func (c *chaosCacher) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	if err := c.inject(ctx, "SetIfAbsent"); err != nil {
+		return false, err
+	}
+	return c.inner.SetIfAbsent(ctx, key, value, ttl, tags)
+}
+
+func (c *chaosCacher) SetIfPresent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (bool, error) {
+	if err := c.inject(ctx, "SetIfPresent"); err != nil {
+		return false, err
+	}
+	return c.inner.SetIfPresent(ctx, key, value, ttl, tags)
+}
+
+func (c *chaosCacher) Get(ctx context.Context, key string, value interface{}) error {
+	if err := c.inject(ctx, "Get"); err != nil {
+		return err
+	}
+	return c.inner.Get(ctx, key, value)
+}
+
+func (c *chaosCacher) GetWithTTL(ctx context.Context, key string, value interface{}) (time.Duration, error) {
+	if err := c.inject(ctx, "GetWithTTL"); err != nil {
+		return 0, err
+	}
+	return c.inner.GetWithTTL(ctx, key, value)
+}
+
+func (c *chaosCacher) MGet(ctx context.Context, keys []string, values []interface{}) error {
+	if err := c.inject(ctx, "MGet"); err != nil {
+		return err
+	}
+	return c.inner.MGet(ctx, keys, values)
+}
+
+func (c *chaosCacher) GetAndDelete(ctx context.Context, key string, value interface{}) error {
+	if err := c.inject(ctx, "GetAndDelete"); err != nil {
+		return err
+	}
+	return c.inner.GetAndDelete(ctx, key, value)
+}
+
+func (c *chaosCacher) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	if err := c.inject(ctx, "Touch"); err != nil {
+		return err
+	}
+	return c.inner.Touch(ctx, key, ttl)
+}
+
+func (c *chaosCacher) Remove(ctx context.Context, key string) error {
+	if err := c.inject(ctx, "Remove"); err != nil {
+		return err
+	}
+	return c.inner.Remove(ctx, key)
+}
+
+func (c *chaosCacher) RemoveByTag(ctx context.Context, tag string) error {
+	if err := c.inject(ctx, "RemoveByTag"); err != nil {
+		return err
+	}
+	return c.inner.RemoveByTag(ctx, tag)
+}
+
+func (c *chaosCacher) RemoveByTags(ctx context.Context, tags []string) error {
+	if err := c.inject(ctx, "RemoveByTags"); err != nil {
+		return err
+	}
+	return c.inner.RemoveByTags(ctx, tags)
+}
+
+func (c *chaosCacher) Flush(ctx context.Context) error {
+	if err := c.inject(ctx, "Flush"); err != nil {
+		return err
+	}
+	return c.inner.Flush(ctx)
+}
+
+func (c *chaosCacher) Exists(ctx context.Context, key string) (bool, error) {
+	if err := c.inject(ctx, "Exists"); err != nil {
+		return false, err
+	}
+	return c.inner.Exists(ctx, key)
+}
+
+func (c *chaosCacher) Increment(ctx context.Context, key string) error {
+	if err := c.inject(ctx, "Increment"); err != nil {
+		return err
+	}
+	return c.inner.Increment(ctx, key)
+}
+
+func (c *chaosCacher) Decrement(ctx context.Context, key string) error {
+	if err := c.inject(ctx, "Decrement"); err != nil {
+		return err
+	}
+	return c.inner.Decrement(ctx, key)
+}
+
+func (c *chaosCacher) IncrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	if err := c.inject(ctx, "IncrBy"); err != nil {
+		return 0, err
+	}
+	return c.inner.IncrBy(ctx, key, delta)
+}
+
+func (c *chaosCacher) DecrBy(ctx context.Context, key string, delta int64) (int64, error) {
+	if err := c.inject(ctx, "DecrBy"); err != nil {
+		return 0, err
+	}
+	return c.inner.DecrBy(ctx, key, delta)
+}
+
+func (c *chaosCacher) IncrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	if err := c.inject(ctx, "IncrementFloat"); err != nil {
+		return 0, err
+	}
+	return c.inner.IncrementFloat(ctx, key, delta)
+}
+
+func (c *chaosCacher) DecrementFloat(ctx context.Context, key string, delta float64) (float64, error) {
+	if err := c.inject(ctx, "DecrementFloat"); err != nil {
+		return 0, err
+	}
+	return c.inner.DecrementFloat(ctx, key, delta)
+}
+
+func (c *chaosCacher) GetKeysByTag(ctx context.Context, tag string) ([]string, error) {
+	if err := c.inject(ctx, "GetKeysByTag"); err != nil {
+		return nil, err
+	}
+	return c.inner.GetKeysByTag(ctx, tag)
+}
+
+func (c *chaosCacher) Ping() error {
+	if err := c.inject(context.Background(), "Ping"); err != nil {
+		return err
+	}
+	return c.inner.Ping()
+}
+
+// Close closes the inner cache manager without injecting faults.
+func (c *chaosCacher) Close() error {
+	return c.inner.Close()
+}
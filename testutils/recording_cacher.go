@@ -0,0 +1,292 @@
+// Package testutils provides wrappers for asserting how code under test uses a cache.
+package testutils
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stremovskyy/cachemar"
+)
+
+// CacheCall is a method call recorded by RecordingCacher.
+type CacheCall struct {
+	// Op is the name of the Cacher method.
+	Op string
+	// Key is the key or tag the method was called with, keys of MSet and MGet and tags of RemoveByTags are
+	// joined by commas.
+	Key string
+	// Value is the value that was written, the target of a read, or the delta of a counter.
+	Value interface{}
+	TTL   time.Duration
+	Tags  []string
+	Error error
+}
+
+// RecordingCacher delegates all calls to another cache manager and records them. Errors can be simulated
+// per method with SimulateError.
+type RecordingCacher struct {
+	mu     sync.Mutex
+	inner  cachemar.Cacher
+	calls  []CacheCall
+	errors map[string]error
+}
+
+// NewRecordingCacher creates a RecordingCacher delegating to inner.
+func NewRecordingCacher(inner cachemar.Cacher) *RecordingCacher {
+	return &RecordingCacher{inner: inner, errors: make(map[string]error)}
+}
+
+// SimulateError makes all following calls of op return err without reaching the wrapped cache manager.
+// A nil err stops the simulation.
+func (c *RecordingCacher) SimulateError(op string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err == nil {
+		delete(c.errors, op)
+		return
+	}
+	c.errors[op] = err
+}
+
+// Calls returns a copy of the recorded calls in the order they were made.
+func (c *RecordingCacher) Calls() []CacheCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]CacheCall(nil), c.calls...)
+}
+
+// Reset forgets the recorded calls and the simulated errors. The data of the wrapped cache manager is kept.
+func (c *RecordingCacher) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls = nil
+	c.errors = make(map[string]error)
+}
+
+// AssertCalledWith fails the test if op was not called with the key.
+func (c *RecordingCacher) AssertCalledWith(t testing.TB, op, key string) {
+	t.Helper()
+
+	if c.count(op, &key) == 0 {
+		t.Errorf("expected %s to be called with key %q", op, key)
+	}
+}
+
+// AssertNotCalledWith fails the test if op was called with the key.
+func (c *RecordingCacher) AssertNotCalledWith(t testing.TB, op, key string) {
+	t.Helper()
+
+	if n := c.count(op, &key); n > 0 {
+		t.Errorf("expected %s not to be called with key %q, called %d times", op, key, n)
+	}
+}
+
+// AssertCallCount fails the test if op was not called exactly n times.
+func (c *RecordingCacher) AssertCallCount(t testing.TB, op string, n int) {
+	t.Helper()
+
+	if count := c.count(op, nil); count != n {
+		t.Errorf("expected %s to be called %d times, called %d times", op, n, count)
+	}
+}
+
+// count returns the number of calls of op, with the key unless it is nil.
+func (c *RecordingCacher) count(op string, key *string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for _, call := range c.calls {
+		if call.Op == op && (key == nil || call.Key == *key) {
+			n++
+		}
+	}
+	return n
+}
+
+// record runs fn unless an error is simulated for the method, and records the call with its error.
+func (c *RecordingCacher) record(call CacheCall, fn func() error) error {
+	c.mu.Lock()
+	err, simulated := c.errors[call.Op]
+	c.mu.Unlock()
+
+	if !simulated {
+		err = fn()
+	}
+	call.Error = err
+
+	c.mu.Lock()
+	c.calls = append(c.calls, call)
+	c.mu.Unlock()
+
+	return call.Error
+}
+
+func (c *RecordingCacher) Set(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) error {
+	return c.record(CacheCall{Op: "Set", Key: key, Value: value, TTL: ttl, Tags: tags}, func() error {
+		return c.inner.Set(ctx, key, value, ttl, tags)
+	})
+}
+
+func (c *RecordingCacher) MSet(ctx context.Context, items []cachemar.CacheItem) error {
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = item.Key
+	}
+
+	return c.record(CacheCall{Op: "MSet", Key: strings.Join(keys, ","), Value: items}, func() error {
+		return c.inner.MSet(ctx, items)
+	})
+}
+
+func (c *RecordingCacher) SetOpts(ctx context.Context, key string, value interface{}, opts cachemar.SetOptions) error {
+	return c.record(CacheCall{Op: "SetOpts", Key: key, Value: value, TTL: opts.TTL, Tags: opts.Tags}, func() error {
+		return c.inner.SetOpts(ctx, key, value, opts)
+	})
+}
+
+func (c *RecordingCacher) SetIfAbsent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (written bool, err error) {
+	err = c.record(CacheCall{Op: "SetIfAbsent", Key: key, Value: value, TTL: ttl, Tags: tags}, func() error {
+		written, err = c.inner.SetIfAbsent(ctx, key, value, ttl, tags)
+		return err
+	})
+	return written, err
+}
+
+func (c *RecordingCacher) SetIfPresent(ctx context.Context, key string, value interface{}, ttl time.Duration, tags []string) (written bool, err error) {
+	err = c.record(CacheCall{Op: "SetIfPresent", Key: key, Value: value, TTL: ttl, Tags: tags}, func() error {
+		written, err = c.inner.SetIfPresent(ctx, key, value, ttl, tags)
+		return err
+	})
+	return written, err
+}
+
+func (c *RecordingCacher) Get(ctx context.Context, key string, value interface{}) error {
+	return c.record(CacheCall{Op: "Get", Key: key, Value: value}, func() error {
+		return c.inner.Get(ctx, key, value)
+	})
+}
+
+func (c *RecordingCacher) GetWithTTL(ctx context.Context, key string, value interface{}) (ttl time.Duration, err error) {
+	err = c.record(CacheCall{Op: "GetWithTTL", Key: key, Value: value}, func() error {
+		ttl, err = c.inner.GetWithTTL(ctx, key, value)
+		return err
+	})
+	return ttl, err
+}
+
+func (c *RecordingCacher) MGet(ctx context.Context, keys []string, values []interface{}) error {
+	return c.record(CacheCall{Op: "MGet", Key: strings.Join(keys, ","), Value: values}, func() error {
+		return c.inner.MGet(ctx, keys, values)
+	})
+}
+
+func (c *RecordingCacher) GetAndDelete(ctx context.Context, key string, value interface{}) error {
+	return c.record(CacheCall{Op: "GetAndDelete", Key: key, Value: value}, func() error {
+		return c.inner.GetAndDelete(ctx, key, value)
+	})
+}
+
+func (c *RecordingCacher) Touch(ctx context.Context, key string, ttl time.Duration) error {
+	return c.record(CacheCall{Op: "Touch", Key: key, TTL: ttl}, func() error {
+		return c.inner.Touch(ctx, key, ttl)
+	})
+}
+
+func (c *RecordingCacher) Remove(ctx context.Context, key string) error {
+	return c.record(CacheCall{Op: "Remove", Key: key}, func() error {
+		return c.inner.Remove(ctx, key)
+	})
+}
+
+func (c *RecordingCacher) RemoveByTag(ctx context.Context, tag string) error {
+	return c.record(CacheCall{Op: "RemoveByTag", Key: tag, Tags: []string{tag}}, func() error {
+		return c.inner.RemoveByTag(ctx, tag)
+	})
+}
+
+func (c *RecordingCacher) RemoveByTags(ctx context.Context, tags []string) error {
+	return c.record(CacheCall{Op: "RemoveByTags", Key: strings.Join(tags, ","), Tags: tags}, func() error {
+		return c.inner.RemoveByTags(ctx, tags)
+	})
+}
+
+func (c *RecordingCacher) Flush(ctx context.Context) error {
+	return c.record(CacheCall{Op: "Flush"}, func() error {
+		return c.inner.Flush(ctx)
+	})
+}
+
+func (c *RecordingCacher) Exists(ctx context.Context, key string) (exists bool, err error) {
+	err = c.record(CacheCall{Op: "Exists", Key: key}, func() error {
+		exists, err = c.inner.Exists(ctx, key)
+		return err
+	})
+	return exists, err
+}
+
+func (c *RecordingCacher) Increment(ctx context.Context, key string) error {
+	return c.record(CacheCall{Op: "Increment", Key: key}, func() error {
+		return c.inner.Increment(ctx, key)
+	})
+}
+
+func (c *RecordingCacher) Decrement(ctx context.Context, key string) error {
+	return c.record(CacheCall{Op: "Decrement", Key: key}, func() error {
+		return c.inner.Decrement(ctx, key)
+	})
+}
+
+func (c *RecordingCacher) IncrBy(ctx context.Context, key string, delta int64) (value int64, err error) {
+	err = c.record(CacheCall{Op: "IncrBy", Key: key, Value: delta}, func() error {
+		value, err = c.inner.IncrBy(ctx, key, delta)
+		return err
+	})
+	return value, err
+}
+
+func (c *RecordingCacher) DecrBy(ctx context.Context, key string, delta int64) (value int64, err error) {
+	err = c.record(CacheCall{Op: "DecrBy", Key: key, Value: delta}, func() error {
+		value, err = c.inner.DecrBy(ctx, key, delta)
+		return err
+	})
+	return value, err
+}
+
+func (c *RecordingCacher) IncrementFloat(ctx context.Context, key string, delta float64) (value float64, err error) {
+	err = c.record(CacheCall{Op: "IncrementFloat", Key: key, Value: delta}, func() error {
+		value, err = c.inner.IncrementFloat(ctx, key, delta)
+		return err
+	})
+	return value, err
+}
+
+func (c *RecordingCacher) DecrementFloat(ctx context.Context, key string, delta float64) (value float64, err error) {
+	err = c.record(CacheCall{Op: "DecrementFloat", Key: key, Value: delta}, func() error {
+		value, err = c.inner.DecrementFloat(ctx, key, delta)
+		return err
+	})
+	return value, err
+}
+
+func (c *RecordingCacher) GetKeysByTag(ctx context.Context, tag string) (keys []string, err error) {
+	err = c.record(CacheCall{Op: "GetKeysByTag", Key: tag, Tags: []string{tag}}, func() error {
+		keys, err = c.inner.GetKeysByTag(ctx, tag)
+		return err
+	})
+	return keys, err
+}
+
+func (c *RecordingCacher) Ping() error {
+	return c.record(CacheCall{Op: "Ping"}, c.inner.Ping)
+}
+
+func (c *RecordingCacher) Close() error {
+	return c.record(CacheCall{Op: "Close"}, c.inner.Close)
+}
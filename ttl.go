@@ -0,0 +1,46 @@
+package cachemar
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// TTL is a named time-to-live for the common cache lifetimes.
+type TTL time.Duration
+
+const (
+	TTLZero   TTL = 0
+	TTLShort  TTL = TTL(time.Minute)
+	TTLMedium TTL = TTL(15 * time.Minute)
+	TTLLong   TTL = TTL(time.Hour)
+	TTLDay    TTL = TTL(24 * time.Hour)
+	TTLWeek   TTL = TTL(7 * 24 * time.Hour)
+)
+
+// NoExpiry is the remaining time-to-live returned by GetWithTTL for values that do not expire.
+const NoExpiry time.Duration = -1
+
+// Duration returns the TTL as a time.Duration.
+func (t TTL) Duration() time.Duration {
+	return time.Duration(t)
+}
+
+// TTLJittered returns base plus a random jitter of up to maxJitter, so that keys set together don't expire together.
+func TTLJittered(base TTL, maxJitter time.Duration) time.Duration {
+	if maxJitter <= 0 {
+		return base.Duration()
+	}
+
+	return base.Duration() + time.Duration(rand.Int63n(int64(maxJitter)+1))
+}
+
+// SetTTL stores a key-value pair like Set, with a named TTL.
+func (c *manager) SetTTL(ctx context.Context, key string, value interface{}, ttl TTL, tags []string) error {
+	return c.Set(ctx, key, value, ttl.Duration(), tags)
+}
+
+// SetTTL stores a key-value pair in the chain like Set, with a named TTL.
+func (c *chained) SetTTL(ctx context.Context, key string, value interface{}, ttl TTL, tags []string) error {
+	return c.Set(ctx, key, value, ttl.Duration(), tags)
+}
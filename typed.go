@@ -0,0 +1,89 @@
+package cachemar
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// TypedCache is a type-safe client of a cache manager for values of type T.
+type TypedCache[T any] struct {
+	c Cacher
+}
+
+// NewTypedCache returns a TypedCache storing values of type T in c, which may be a Manager.
+func NewTypedCache[T any](c Cacher) *TypedCache[T] {
+	return &TypedCache[T]{c: c}
+}
+
+// Get retrieves the value stored under key. The zero value is returned with the error.
+func (t *TypedCache[T]) Get(ctx context.Context, key string) (T, error) {
+	var value T
+	if err := t.c.Get(ctx, key, &value); err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return value, nil
+}
+
+// Set stores the value under key with the specified ttl and tags.
+func (t *TypedCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration, tags []string) error {
+	return t.c.Set(ctx, key, value, ttl, tags)
+}
+
+// GetOrSet retrieves the value stored under key and, on a miss, stores and returns the result of loader.
+// If the cache manager is a Manager, concurrent calls share one loader call like with Manager.GetOrSet.
+func (t *TypedCache[T]) GetOrSet(ctx context.Context, key string, ttl time.Duration, tags []string, loader func(ctx context.Context) (T, error)) (T, error) {
+	var value T
+
+	if manager, ok := t.c.(Manager); ok {
+		err := manager.GetOrSet(ctx, key, &value, ttl, tags, func(ctx context.Context) (interface{}, error) {
+			return loader(ctx)
+		})
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		return value, nil
+	}
+
+	if err := t.c.Get(ctx, key, &value); err == nil {
+		return value, nil
+	}
+
+	value, err := loader(ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return value, t.c.Set(ctx, key, value, ttl, tags)
+}
+
+// MGetTyped retrieves the values of multiple keys of type T with one MGet. Missing keys are absent from
+// the returned map. Any other failure is returned as a MultiError holding the error of each key by index.
+func MGetTyped[T any](ctx context.Context, c Cacher, keys []string) (map[string]T, error) {
+	values := make([]T, len(keys))
+	targets := make([]interface{}, len(keys))
+	for i := range values {
+		targets[i] = &values[i]
+	}
+
+	errs := make(MultiError, len(keys))
+	if err := c.MGet(ctx, keys, targets); err != nil && !errors.As(err, &errs) {
+		return nil, err
+	}
+
+	found := make(map[string]T, len(keys))
+	for i, key := range keys {
+		switch {
+		case errs[i] == nil:
+			found[key] = values[i]
+		case IsNotFound(errs[i]):
+			errs[i] = nil
+		}
+	}
+
+	return found, errs.ErrorOrNil()
+}
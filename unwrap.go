@@ -0,0 +1,29 @@
+package cachemar
+
+// Unwrapper is implemented by cache managers wrapping another one, e.g. to trace or measure its operations.
+// The manager looks for the optional interfaces, such as MetadataGetter or Transactor, through Unwrap, so
+// wrapping a cache manager does not disable the features built on them. Only wrappers that store the keys
+// and values unchanged may implement it.
+type Unwrapper interface {
+	// Unwrap returns the wrapped cache manager.
+	Unwrap() Cacher
+}
+
+// As returns c as a T if it implements T, or else the first cache manager it wraps, following Unwrapper,
+// that does. Optional interfaces of cache managers should be looked up with As rather than a type assertion.
+func As[T any](c Cacher) (T, bool) {
+	for c != nil {
+		if t, ok := c.(T); ok {
+			return t, true
+		}
+
+		unwrapper, ok := c.(Unwrapper)
+		if !ok {
+			break
+		}
+		c = unwrapper.Unwrap()
+	}
+
+	var zero T
+	return zero, false
+}
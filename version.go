@@ -0,0 +1,88 @@
+package cachemar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// versionKeyPrefix is prepended to a base key to form the key of its version counter.
+const versionKeyPrefix = "__version__:"
+
+// VersionKey returns the key of the version counter of baseKey. Advance the version with Increment,
+// after initializing it with Set where the cache manager requires the key to exist.
+func VersionKey(baseKey string) string {
+	return versionKeyPrefix + baseKey
+}
+
+// VersionedKey returns the key holding the given version of baseKey's content.
+func VersionedKey(baseKey string, version uint64) string {
+	return fmt.Sprintf("%s:v%d", baseKey, version)
+}
+
+// GetKeyVersion returns the version counter of baseKey, or zero if it was never set.
+func (c *manager) GetKeyVersion(ctx context.Context, baseKey string) (uint64, error) {
+	return getKeyVersion(ctx, c, baseKey)
+}
+
+// WatchKeyVersion polls the version counter of baseKey every check interval and sends each new version.
+// The channel is closed when ctx is done.
+func (c *manager) WatchKeyVersion(ctx context.Context, baseKey string) (<-chan uint64, error) {
+	return c.watchKeyVersion(ctx, c, baseKey)
+}
+
+// getKeyVersion reads the counter through cacher, so the key goes through its key preparation.
+func getKeyVersion(ctx context.Context, cacher Cacher, baseKey string) (uint64, error) {
+	// Counters are stored as integers by Increment, so they are read as such.
+	var version int64
+	err := cacher.Get(ctx, VersionKey(baseKey), &version)
+	if errors.Is(err, ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if version < 0 {
+		return 0, fmt.Errorf("invalid version %d of key %q", version, baseKey)
+	}
+
+	return uint64(version), nil
+}
+
+func (c *manager) watchKeyVersion(ctx context.Context, cacher Cacher, baseKey string) (<-chan uint64, error) {
+	version, err := getKeyVersion(ctx, cacher, baseKey)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(chan uint64)
+	go func() {
+		defer close(versions)
+
+		ticker := time.NewTicker(c.checkInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			current, err := getKeyVersion(ctx, cacher, baseKey)
+			if err != nil || current == version {
+				continue
+			}
+			version = current
+
+			select {
+			case versions <- current:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return versions, nil
+}